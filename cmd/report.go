@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/j178/leetgo/report"
+)
+
+var reportFormat string
+
+func addReportFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&reportFormat,
+		"report",
+		"",
+		"write a report of the results to stdout, in one of: "+strings.Join(report.Formats, ", "),
+	)
+}
+
+// startReport returns the writer of the report if it is requested, and moves the human-readable output
+// of the command to stderr, so the report on stdout can be piped to other tools.
+func startReport(cmd *cobra.Command) (io.Writer, error) {
+	if reportFormat == "" {
+		return nil, nil
+	}
+	if !slices.Contains(report.Formats, reportFormat) {
+		return nil, fmt.Errorf(
+			"unknown report format: %s, available: %s",
+			reportFormat,
+			strings.Join(report.Formats, ", "),
+		)
+	}
+	w := cmd.OutOrStdout()
+	cmd.SetOut(cmd.ErrOrStderr())
+	return w, nil
+}
+
+func writeReport(w io.Writer, suites []*report.Suite) error {
+	if w == nil {
+		return nil
+	}
+	return report.Write(w, reportFormat, suites)
+}
@@ -140,7 +140,11 @@ func initCommands() {
 		pickCmd,
 		infoCmd,
 		testCmd,
+		runInputCmd,
 		submitCmd,
+		submissionsCmd,
+		syncCmd,
+		testCasesCmd,
 		fixCmd,
 		editCmd,
 		extractCmd,
@@ -0,0 +1,229 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+
+	"github.com/j178/leetgo/config"
+	"github.com/j178/leetgo/lang"
+	"github.com/j178/leetgo/leetcode"
+	"github.com/j178/leetgo/utils"
+)
+
+var (
+	runInput       string
+	runInputLocal  bool
+	runInputBoth   bool
+	saveInputCase  bool
+	runInputRemote = true
+)
+
+func init() {
+	runInputCmd.Flags().StringVarP(
+		&runInput,
+		"input",
+		"i",
+		"",
+		"input of the solution, one argument per line, read from stdin if not given",
+	)
+	runInputCmd.Flags().BoolVarP(&runInputLocal, "local", "L", false, "run locally")
+	runInputCmd.Flags().BoolVarP(&runInputBoth, "both", "B", false, "run both locally and remotely")
+	runInputCmd.Flags().BoolVar(&saveInputCase, "save", false, "append the input to testcases.txt")
+}
+
+var runInputCmd = &cobra.Command{
+	Use:       "run qid",
+	Short:     "Run solution on a custom input",
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: []string{"today", "last"},
+	Example: `leetgo run 1 --input $'[2,7,11,15]\n9'
+leetgo run last -L < input.txt
+leetgo run last -B --save -i $'[3,3]\n6'`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if runInputLocal {
+			runInputRemote = false
+		}
+		if runInputBoth {
+			runInputLocal = true
+			runInputRemote = true
+		}
+
+		cfg := config.Get()
+		c := leetcode.NewClient(leetcode.ReadCredentials())
+		qs, err := leetcode.ParseQID(args[0], c)
+		if err != nil {
+			return err
+		}
+		if len(qs) > 1 {
+			return errors.New("multiple questions found, a custom input can only be run on one question")
+		}
+		q := qs[0]
+		if err := q.Fulfill(); err != nil {
+			return fmt.Errorf("failed to get question data: %w", err)
+		}
+		gen, err := lang.GetGenerator(cfg.Code.Lang)
+		if err != nil {
+			return err
+		}
+		if _, ok := gen.(lang.LocalTestable); runInputLocal && !ok {
+			return fmt.Errorf("local test not supported for %s", cfg.Code.Lang)
+		}
+
+		input, err := readRunInput(cmd)
+		if err != nil {
+			return err
+		}
+		tc := lang.TestCase{Question: q, Input: input}
+		if err := tc.Check(); err != nil {
+			return fmt.Errorf("invalid input: %w", err)
+		}
+
+		failed := false
+		var localOutput string
+		if runInputLocal {
+			log.Info("running locally", "question", q.TitleSlug)
+			result, err := lang.RunLocal(q, input)
+			if result != nil {
+				printRunResult(cmd, result)
+				localOutput = result.Output
+			}
+			if err != nil {
+				log.Error("failed to run locally", "err", err)
+				failed = true
+			}
+		}
+		if runInputRemote {
+			log.Info("running remotely", "question", q.TitleSlug)
+			user, err := c.GetUserStatus()
+			if err != nil {
+				user = &leetcode.UserStatus{}
+			}
+			result, err := runInputRemotely(cmd, q, c, gen, newLimiter(user), input)
+			if err != nil {
+				log.Error("failed to run remotely", "err", err)
+				failed = true
+			} else {
+				cmd.Print(result.Display(q))
+				if result.ExpectedRunSuccess && len(result.ExpectedCodeAnswer) > 0 {
+					tc.Output = result.ExpectedCodeAnswer[0]
+				}
+				if !result.CorrectAnswer {
+					failed = true
+				}
+			}
+		}
+		// Judge the local output with the expected answer from LeetCode when both are run.
+		if localOutput != "" && tc.HasOutput() {
+			if r := lang.GetJudger(q).Judge(input, tc.Output, localOutput); r.IsAccepted() {
+				cmd.Println(config.PassedStyle.Render("Local output matches the expected answer"))
+			} else {
+				cmd.Println(config.FailedStyle.Render("Local output differs from the expected answer: " + r.GetInfo()))
+				failed = true
+			}
+		}
+
+		if saveInputCase {
+			added, err := lang.AppendTestCase(q, tc)
+			if err != nil {
+				return fmt.Errorf("failed to save the input: %w", err)
+			}
+			if added {
+				log.Info("added the input to `testcases.txt`")
+			} else {
+				log.Info("the input is already in `testcases.txt`")
+			}
+		}
+
+		if failed {
+			return exitCode(1)
+		}
+		return nil
+	},
+}
+
+// readRunInput reads the input from the --input flag or stdin, empty lines are ignored.
+func readRunInput(cmd *cobra.Command) ([]string, error) {
+	raw := runInput
+	if !cmd.Flags().Changed("input") {
+		if stat, err := os.Stdin.Stat(); err == nil && stat.Mode()&os.ModeCharDevice != 0 {
+			log.Info("reading input from stdin, one argument per line, press Ctrl-D to finish")
+		}
+		content, err := io.ReadAll(cmd.InOrStdin())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read input: %w", err)
+		}
+		raw = string(content)
+	}
+	var input []string
+	for _, line := range utils.SplitLines(raw) {
+		if line = strings.TrimSpace(line); line != "" {
+			input = append(input, line)
+		}
+	}
+	if len(input) == 0 {
+		return nil, errors.New("no input given")
+	}
+	return input, nil
+}
+
+func printRunResult(cmd *cobra.Command, result *lang.RunResult) {
+	if result.Output != "" {
+		cmd.Printf("Output:     %s\n", result.Output)
+	}
+	if result.Stdout != "" {
+		cmd.Printf("Stdout:     %s\n", config.StdoutStyle.Render(result.Stdout))
+	}
+	if result.Usage != "" {
+		cmd.Printf("Usage:      %s\n", config.StdoutStyle.Render(result.Usage))
+	}
+}
+
+func runInputRemotely(
+	cmd *cobra.Command,
+	q *leetcode.QuestionData,
+	c leetcode.Client,
+	gen lang.Lang,
+	limiter *utils.RateLimiter,
+	input []string,
+) (*leetcode.RunCheckResult, error) {
+	solution, err := lang.GetSolutionCode(q)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get solution code: %w", err)
+	}
+
+	ctx, stop := interruptContext()
+	defer stop()
+	c = c.WithContext(ctx)
+
+	spin := newSpinner(cmd.ErrOrStderr())
+	spin.Suffix = " Running..."
+	spin.Reverse()
+	spin.Start()
+	defer spin.Stop()
+
+	limiter.Take()
+	spin.Reverse()
+
+	interResult, err := c.RunCode(q, gen.Slug(), solution, strings.Join(input, "\n"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to run: %w", err)
+	}
+
+	spin.Lock()
+	spin.Suffix = " Waiting for result..."
+	spin.Unlock()
+
+	testResult, err := waitResult(ctx, c, interResult.InterpretId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to wait result: %w", err)
+	}
+	r := testResult.(*leetcode.RunCheckResult)
+	r.InputData = interResult.TestCase
+	return r, nil
+}
@@ -0,0 +1,193 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/charmbracelet/log"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/j178/leetgo/config"
+	"github.com/j178/leetgo/lang"
+	"github.com/j178/leetgo/leetcode"
+	"github.com/j178/leetgo/utils"
+)
+
+var (
+	submissionsLimit  int
+	restoreSubmission string
+)
+
+func init() {
+	submissionsCmd.Flags().IntVarP(&submissionsLimit, "limit", "n", 20, "number of the latest submissions to list")
+	submissionsCmd.Flags().StringVar(
+		&restoreSubmission,
+		"restore",
+		"",
+		"restore a submission into the code file: its id, 'ac' for the latest accepted one, or pick one if no value given",
+	)
+	submissionsCmd.Flags().Lookup("restore").NoOptDefVal = "pick"
+}
+
+var submissionsCmd = &cobra.Command{
+	Use:       "submissions qid",
+	Short:     "List submissions of a question, and restore the code of a submission",
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: []string{"today", "last", "last/"},
+	Example: `leetgo submissions 1
+leetgo submissions last --restore
+leetgo submissions two-sum --restore ac
+leetgo submissions two-sum --restore 1054093278`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := config.Get()
+		c := leetcode.NewClient(leetcode.ReadCredentials())
+		qs, err := leetcode.ParseQID(args[0], c)
+		if err != nil {
+			return err
+		}
+		if restoreSubmission != "" && len(qs) != 1 {
+			return errors.New("--restore needs exactly one question")
+		}
+		gen, err := lang.GetGenerator(cfg.Code.Lang)
+		if err != nil {
+			return err
+		}
+
+		for _, q := range qs {
+			list, err := c.GetSubmissions(q.TitleSlug, 0, submissionsLimit)
+			if err != nil {
+				return err
+			}
+			if restoreSubmission != "" {
+				return restoreSubmissionCode(q, c, gen, list.Submissions)
+			}
+			if len(list.Submissions) == 0 {
+				log.Info("no submissions", "question", q.TitleSlug)
+				continue
+			}
+			printSubmissions(cmd.OutOrStdout(), q, list.Submissions)
+		}
+		return nil
+	},
+}
+
+func printSubmissions(out io.Writer, q *leetcode.QuestionData, submissions []*leetcode.Submission) {
+	w := table.NewWriter()
+	w.SetOutputMirror(out)
+	w.SetStyle(table.StyleColoredDark)
+	w.SetTitle(fmt.Sprintf("%s. %s", q.QuestionFrontendId, q.GetTitle()))
+	w.AppendHeader(table.Row{"ID", "Time", "Status", "Language", "Runtime", "Memory"})
+	for _, s := range submissions {
+		status := config.FailedStyle.Render(s.StatusDisplay)
+		if s.Accepted() {
+			status = config.PassedStyle.Render(s.StatusDisplay)
+		}
+		w.AppendRow(
+			table.Row{
+				s.Id,
+				time.Unix(s.Timestamp, 0).Format("2006/01/02 15:04:05"),
+				status,
+				s.LangName,
+				s.Runtime,
+				s.Memory,
+			},
+		)
+	}
+	w.Render()
+}
+
+// restoreSubmissionCode replaces the solution in the code file with the code of the submission selected by
+// restoreSubmission, the code file is generated first if it doesn't exist.
+func restoreSubmissionCode(
+	q *leetcode.QuestionData,
+	c leetcode.Client,
+	gen lang.Lang,
+	submissions []*leetcode.Submission,
+) error {
+	var inLang []*leetcode.Submission
+	for _, s := range submissions {
+		if s.Lang == gen.Slug() {
+			inLang = append(inLang, s)
+		}
+	}
+
+	id := restoreSubmission
+	switch restoreSubmission {
+	case "ac":
+		id = ""
+		for _, s := range inLang {
+			if s.Accepted() {
+				id = s.Id
+				break
+			}
+		}
+		if id == "" {
+			return fmt.Errorf("no accepted submission in %s among the latest %d", gen.Slug(), len(submissions))
+		}
+	case "pick":
+		if len(inLang) == 0 {
+			return fmt.Errorf("no submission in %s among the latest %d", gen.Slug(), len(submissions))
+		}
+		options := make([]string, len(inLang))
+		for i, s := range inLang {
+			options[i] = fmt.Sprintf(
+				"%s  %s  %s  %s  %s",
+				s.Id,
+				time.Unix(s.Timestamp, 0).Format("2006/01/02 15:04:05"),
+				s.StatusDisplay,
+				s.Runtime,
+				s.Memory,
+			)
+		}
+		var idx int
+		err := survey.AskOne(&survey.Select{Message: "Select a submission to restore:", Options: options}, &idx)
+		if err != nil {
+			return err
+		}
+		id = inLang[idx].Id
+	}
+
+	detail, err := c.GetSubmissionDetail(id)
+	if err != nil {
+		return err
+	}
+	if detail.TitleSlug != "" && detail.TitleSlug != q.TitleSlug {
+		return fmt.Errorf("submission %s is of %s, not %s", id, detail.TitleSlug, q.TitleSlug)
+	}
+	if detail.Lang != gen.Slug() {
+		return fmt.Errorf("submission %s is written in %s, use `-l %s` to restore it", id, detail.Lang, detail.Lang)
+	}
+
+	codeFile, err := lang.GetFileOutput(q, lang.CodeFile)
+	if err != nil {
+		return err
+	}
+	if !utils.IsExist(codeFile.GetPath()) {
+		if _, err := lang.Generate(q); err != nil {
+			return err
+		}
+	} else if !viper.GetBool("yes") {
+		overwrite := false
+		err = survey.AskOne(
+			&survey.Confirm{
+				Message: fmt.Sprintf("Replace the solution in %s?", utils.RelToCwd(codeFile.GetPath())),
+			}, &overwrite,
+		)
+		if err != nil {
+			return err
+		}
+		if !overwrite {
+			return nil
+		}
+	}
+	if err := lang.UpdateSolutionCode(q, detail.Code); err != nil {
+		return err
+	}
+	log.Info("submission restored", "id", id, "status", detail.StatusDisplay)
+	return nil
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"strings"
@@ -11,9 +12,14 @@ import (
 	"github.com/j178/leetgo/config"
 	"github.com/j178/leetgo/lang"
 	"github.com/j178/leetgo/leetcode"
+	"github.com/j178/leetgo/report"
 	"github.com/j178/leetgo/utils"
 )
 
+func init() {
+	addReportFlag(submitCmd)
+}
+
 var submitCmd = &cobra.Command{
 	Use:   "submit qid",
 	Short: "Submit solution",
@@ -22,11 +28,16 @@ leetgo submit two-sum
 leetgo submit last
 leetgo submit w330/1
 leetgo submit w330/
+leetgo submit last --report json
 `,
 	Aliases:   []string{"s"},
 	Args:      cobra.ExactArgs(1),
 	ValidArgs: []string{"today", "last", "last/"},
 	RunE: func(cmd *cobra.Command, args []string) error {
+		reportOut, err := startReport(cmd)
+		if err != nil {
+			return err
+		}
 		cfg := config.Get()
 		c := leetcode.NewClient(leetcode.ReadCredentials())
 		qs, err := leetcode.ParseQID(args[0], c)
@@ -45,15 +56,21 @@ leetgo submit w330/
 		limiter := newLimiter(user)
 
 		var hasFailedCase bool
+		var suites []*report.Suite
 		for _, q := range qs {
 			log.Info("submitting solution", "question", q.TitleSlug, "user", user.Whoami(c))
 			result, err := submitSolution(cmd, q, c, gen, limiter)
 			if err != nil {
 				hasFailedCase = true
 				log.Error("failed to submit solution", "err", err)
+				suites = append(suites, report.ErrorSuite(q, report.Submit, err))
+				if errors.Is(err, context.Canceled) {
+					break
+				}
 				continue
 			}
 			cmd.Print(result.Display(qs[0]))
+			suites = append(suites, report.FromSubmitCheckResult(q, result))
 
 			if !result.Accepted() {
 				hasFailedCase = true
@@ -69,6 +86,10 @@ leetgo submit w330/
 			log.Debug("failed to show today's streak", "err", err)
 		}
 
+		err = writeReport(reportOut, suites)
+		if err != nil {
+			return err
+		}
 		if hasFailedCase {
 			return exitCode(1)
 		}
@@ -92,6 +113,10 @@ func submitSolution(
 		return nil, fmt.Errorf("failed to get solution code: %w", err)
 	}
 
+	ctx, stop := interruptContext()
+	defer stop()
+	c = c.WithContext(ctx)
+
 	spin := newSpinner(cmd.ErrOrStderr())
 	spin.Suffix = " Submitting solution..."
 	spin.Reverse()
@@ -110,20 +135,23 @@ func submitSolution(
 	spin.Suffix = " Waiting for result..."
 	spin.Unlock()
 
-	testResult, err := waitResult(c, submissionId)
+	testResult, err := waitResult(ctx, c, submissionId)
 	if err != nil {
 		return nil, fmt.Errorf("failed to wait submit result: %w", err)
 	}
 	return testResult.(*leetcode.SubmitCheckResult), nil
 }
 
+// largeTestCaseSize is the size of a failed case that is worth shrinking before debugging.
+const largeTestCaseSize = 1000
+
 func appendToTestCases(q *leetcode.QuestionData, result *leetcode.SubmitCheckResult) (bool, error) {
 	genResult, err := lang.GeneratePathsOnly(q)
 	if err != nil {
 		return false, err
 	}
 	testCasesFile := genResult.GetFile(lang.TestCasesFile)
-	if testCasesFile == nil || !utils.IsExist(testCasesFile.GetPath()) {
+	if testCasesFile == nil || !utils.IsExist(lang.TestCasesPath(testCasesFile)) {
 		return false, nil
 	}
 
@@ -131,6 +159,7 @@ func appendToTestCases(q *leetcode.QuestionData, result *leetcode.SubmitCheckRes
 		Question: q,
 		Input:    strings.Split(result.LastTestcase, "\n"),
 		Output:   result.ExpectedOutput,
+		Tags:     []string{lang.TagFailedSubmission},
 	}
 	// some test cases are hidden during contest, they can be excluded by checking
 	err = failedCase.Check()
@@ -147,8 +176,10 @@ func appendToTestCases(q *leetcode.QuestionData, result *leetcode.SubmitCheckRes
 	}
 	tc.AddCase(failedCase)
 
-	content := []byte(tc.String())
-	err = utils.WriteFile(testCasesFile.GetPath(), content)
+	err = tc.Save()
+	if err == nil && len(result.LastTestcase) > largeTestCaseSize {
+		log.Info("the failed case is large, minimize it with `leetgo test --shrink -1`", "question", q.TitleSlug)
+	}
 	return true, err
 }
 
@@ -0,0 +1,264 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+
+	"github.com/j178/leetgo/config"
+	"github.com/j178/leetgo/lang"
+	"github.com/j178/leetgo/leetcode"
+	"github.com/j178/leetgo/utils"
+)
+
+var (
+	syncForce    bool
+	syncLimit    int
+	syncInterval time.Duration
+)
+
+func init() {
+	syncCmd.Flags().BoolVarP(
+		&syncForce,
+		"force",
+		"f",
+		false,
+		"sync questions synced before again, replacing the solutions in existing code files",
+	)
+	syncCmd.Flags().IntVarP(&syncLimit, "limit", "n", 0, "sync at most N questions in this run, 0 means all")
+	syncCmd.Flags().DurationVar(&syncInterval, "interval", 2*time.Second, "minimum interval between requests to LeetCode")
+}
+
+// Results of syncing a question.
+const (
+	syncDone = iota
+	syncSkipped
+	syncNoAccepted
+)
+
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Download the latest accepted solutions of all your accepted questions",
+	Long: `Download the latest accepted submission in the configured language of every question you have accepted,
+and generate the question with it as the solution.
+
+Questions whose code file already exists are left untouched, and the progress is saved after each question,
+so it's safe to interrupt and run again. Use --force to replace the solutions in existing code files.`,
+	Example: `leetgo sync
+leetgo sync -l python3 --limit 50`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := config.Get()
+		c := leetcode.NewClient(leetcode.ReadCredentials())
+		gen, err := lang.GetGenerator(cfg.Code.Lang)
+		if err != nil {
+			return err
+		}
+		user, err := c.GetUserStatus()
+		if err != nil {
+			return err
+		}
+		if !user.IsSignedIn {
+			return errors.New("not signed in, sync needs your LeetCode credentials")
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+		c = c.WithContext(ctx)
+
+		limiter := utils.NewRateLimiter(syncInterval)
+		qs := acceptedQuestions(c, limiter)
+		if len(qs) == 0 {
+			log.Info("no accepted questions found")
+			return nil
+		}
+		log.Info("syncing accepted questions", "user", user.Whoami(c), "questions", len(qs), "lang", gen.Slug())
+
+		var done, skipped, noAccepted, failed int
+		for _, q := range qs {
+			if ctx.Err() != nil {
+				log.Info("interrupted, run `leetgo sync` again to resume")
+				break
+			}
+			if syncLimit > 0 && done >= syncLimit {
+				log.Info("limit reached, run `leetgo sync` again to continue", "limit", syncLimit)
+				break
+			}
+			result, err := syncQuestion(q, c, gen, limiter)
+			if err != nil {
+				if errors.Is(err, context.Canceled) {
+					continue
+				}
+				if errors.Is(err, leetcode.ErrPaidOnlyQuestion) {
+					log.Warn("skipped paid only question", "question", q.TitleSlug)
+					skipped++
+					continue
+				}
+				log.Error("failed to sync", "question", q.TitleSlug, "err", err)
+				failed++
+				continue
+			}
+			switch result {
+			case syncDone:
+				done++
+			case syncSkipped:
+				skipped++
+			case syncNoAccepted:
+				noAccepted++
+			}
+		}
+		log.Info(
+			"sync finished",
+			"synced", done,
+			"skipped", skipped,
+			"not accepted in "+gen.Slug(), noAccepted,
+			"failed", failed,
+		)
+		if failed > 0 {
+			return exitCode(1)
+		}
+		return nil
+	},
+}
+
+// acceptedQuestions returns the questions accepted by the user, ordered by their frontend ids. The statuses
+// in the questions cache are of the time it was updated, and may be missing, so LeetCode is asked as well.
+func acceptedQuestions(c leetcode.Client, limiter *utils.RateLimiter) []*leetcode.QuestionData {
+	cache := leetcode.GetCache(c)
+	seen := make(map[string]bool)
+	var qs []*leetcode.QuestionData
+	add := func(q *leetcode.QuestionData) {
+		if !seen[q.TitleSlug] {
+			seen[q.TitleSlug] = true
+			qs = append(qs, q)
+		}
+	}
+	for _, q := range cache.GetAllQuestions() {
+		if strings.EqualFold(q.Status, "ac") {
+			add(q)
+		}
+	}
+
+	const pageSize = 100
+	for skip := 0; ; skip += pageSize {
+		limiter.Take()
+		list, err := c.GetQuestionsByFilter(leetcode.QuestionFilter{Status: "AC"}, pageSize, skip)
+		if err != nil {
+			log.Warn("failed to list accepted questions, only the statuses in the cache are used", "err", err)
+			break
+		}
+		for _, q := range list.Questions {
+			if cached := cache.GetBySlug(q.TitleSlug); cached != nil {
+				q = cached
+			}
+			add(q)
+		}
+		if !list.HasMore || len(list.Questions) == 0 {
+			break
+		}
+	}
+
+	slices.SortStableFunc(
+		qs, func(a, b *leetcode.QuestionData) int {
+			x, err1 := strconv.Atoi(a.QuestionFrontendId)
+			y, err2 := strconv.Atoi(b.QuestionFrontendId)
+			if err1 == nil && err2 == nil {
+				return x - y
+			}
+			return strings.Compare(a.QuestionFrontendId, b.QuestionFrontendId)
+		},
+	)
+	return qs
+}
+
+// syncQuestion generates the question with its latest accepted submission in the language of gen as the solution.
+// The result is recorded in the project state, so the question is skipped in later runs unless --force is given.
+func syncQuestion(
+	q *leetcode.QuestionData,
+	c leetcode.Client,
+	gen lang.Lang,
+	limiter *utils.RateLimiter,
+) (int, error) {
+	key := gen.Slug() + "/" + q.TitleSlug
+	_, synced := config.LoadState().Synced[key]
+	codeFile, err := lang.GetFileOutput(q, lang.CodeFile)
+	if err != nil {
+		return 0, err
+	}
+	exists := utils.IsExist(codeFile.GetPath())
+	if !syncForce && (synced || exists) {
+		log.Debug("skipped", "question", q.TitleSlug, "synced", synced, "exists", exists)
+		return syncSkipped, nil
+	}
+
+	submission, err := latestAccepted(c, limiter, q.TitleSlug, gen.Slug())
+	if err != nil {
+		return 0, err
+	}
+	if submission == nil {
+		log.Info("no accepted submission", "question", q.TitleSlug, "lang", gen.Slug())
+		recordSynced(key, "")
+		return syncNoAccepted, nil
+	}
+	limiter.Take()
+	detail, err := c.GetSubmissionDetail(submission.Id)
+	if err != nil {
+		return 0, err
+	}
+
+	if !exists {
+		limiter.Take()
+		if _, err := lang.Generate(q); err != nil {
+			return 0, err
+		}
+	}
+	if err := lang.UpdateSolutionCode(q, detail.Code); err != nil {
+		return 0, err
+	}
+	recordSynced(key, submission.Id)
+	return syncDone, nil
+}
+
+// latestAccepted returns the latest accepted submission of the question in the language, or nil if there is none
+// among the latest submissions.
+func latestAccepted(
+	c leetcode.Client,
+	limiter *utils.RateLimiter,
+	slug string,
+	langSlug string,
+) (*leetcode.Submission, error) {
+	const pageSize, maxSubmissions = 20, 100
+	for offset := 0; offset < maxSubmissions; offset += pageSize {
+		limiter.Take()
+		list, err := c.GetSubmissions(slug, offset, pageSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range list.Submissions {
+			if s.Accepted() && s.Lang == langSlug {
+				return s, nil
+			}
+		}
+		if !list.HasMore {
+			break
+		}
+	}
+	return nil, nil
+}
+
+func recordSynced(key string, submissionId string) {
+	state := config.LoadState()
+	if state.Synced == nil {
+		state.Synced = make(map[string]string)
+	}
+	state.Synced[key] = submissionId
+	config.SaveState(state)
+}
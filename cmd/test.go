@@ -1,18 +1,24 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
+	"os"
+	"os/signal"
 	"strings"
 	"time"
 
 	"github.com/briandowns/spinner"
 	"github.com/charmbracelet/log"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 
 	"github.com/j178/leetgo/config"
 	"github.com/j178/leetgo/lang"
 	"github.com/j178/leetgo/leetcode"
+	"github.com/j178/leetgo/report"
 	"github.com/j178/leetgo/utils"
 )
 
@@ -23,6 +29,12 @@ var (
 	autoSubmit  bool
 	targetCase  string
 	forceSubmit bool
+	stressTest  bool
+	bruteCmd    string
+	shrinkCase  int
+	watchTests  bool
+	debugCase   string
+	debugConfig bool
 )
 
 func init() {
@@ -42,7 +54,45 @@ func init() {
 	)
 	testCmd.Flags().BoolVarP(&autoSubmit, "submit", "s", false, "auto submit if all tests passed")
 	testCmd.Flags().BoolVarP(&forceSubmit, "force", "f", false, "force submit even if local test failed")
-	testCmd.Flags().StringVarP(&targetCase, "target", "t", "-", "only run the specified test case, e.g. 1, 1-3, -1, 1-")
+	testCmd.Flags().StringVarP(&targetCase, "target", "t", "-", "only run the specified test cases, e.g. 1, 1-3, -1, 1-, or names of cases")
+	testCmd.Flags().BoolVarP(
+		&watchTests,
+		"watch",
+		"w",
+		false,
+		"re-run local test on changes of the code and test cases, with -B also run remotely after local test passed",
+	)
+	testCmd.Flags().IntP("jobs", "j", 1, "number of test cases to run concurrently in local test, 0 means the number of CPUs")
+	testCmd.Flags().String("profile", "release", "build profile of C++ local test, e.g. release, debug, asan, ubsan")
+
+	testCmd.Flags().BoolVar(&stressTest, "stress", false, "compare the solution with a brute-force solution on random inputs")
+	testCmd.Flags().IntP("rounds", "n", 200, "number of random cases to run in stress test")
+	testCmd.Flags().StringVar(&bruteCmd, "brute", "", "command of the brute-force solution used in stress test")
+	testCmd.Flags().IntVar(
+		&shrinkCase,
+		"shrink",
+		0,
+		"minimize the failed test case N of testcases.txt (-1 for the last one), and save it to testcases.txt",
+	)
+
+	testCmd.Flags().StringVar(
+		&debugCase,
+		"debug",
+		"",
+		"build a debug binary and start a debugger on the specified test case, e.g. 2 or the name of a case",
+	)
+	testCmd.Flags().BoolVar(
+		&debugConfig,
+		"debug-config",
+		false,
+		"with --debug, write a VS Code launch configuration instead of starting a debugger",
+	)
+
+	addReportFlag(testCmd)
+
+	_ = viper.BindPFlag("code.jobs", testCmd.Flags().Lookup("jobs"))
+	_ = viper.BindPFlag("code.stress.rounds", testCmd.Flags().Lookup("rounds"))
+	_ = viper.BindPFlag("code.cpp.profile", testCmd.Flags().Lookup("profile"))
 }
 
 var testCmd = &cobra.Command{
@@ -54,7 +104,13 @@ var testCmd = &cobra.Command{
 	Example: `leetgo test 244
 leetgo test last
 leetgo test w330/1
-leetgo test w330/`,
+leetgo test w330/
+leetgo test 1 --stress --brute "python3 brute.py"
+leetgo test 1 --shrink -1
+leetgo test last -B --report junit > report.xml
+leetgo test last -L --watch
+leetgo test last -L --profile asan
+leetgo test last -L --debug 2`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if runLocally {
 			runRemotely = false
@@ -63,6 +119,22 @@ leetgo test w330/`,
 			runLocally = true
 			runRemotely = true
 		}
+		if reportFormat != "" && (stressTest || shrinkCase != 0 || watchTests) {
+			return errors.New("--report can't be used with --stress, --shrink or --watch")
+		}
+		if watchTests && (!runLocally || autoSubmit) {
+			return errors.New("--watch needs -L or -B, and can't be used with --submit")
+		}
+		if debugConfig && debugCase == "" {
+			return errors.New("--debug-config needs --debug")
+		}
+		if debugCase != "" && (reportFormat != "" || stressTest || shrinkCase != 0 || watchTests || autoSubmit) {
+			return errors.New("--debug can't be used with --report, --stress, --shrink, --watch or --submit")
+		}
+		reportOut, err := startReport(cmd)
+		if err != nil {
+			return err
+		}
 
 		cfg := config.Get()
 		c := leetcode.NewClient(leetcode.ReadCredentials())
@@ -75,11 +147,38 @@ leetgo test w330/`,
 		if err != nil {
 			return err
 		}
+		if cmd.Flags().Changed("profile") && gen.Slug() != "cpp" {
+			return fmt.Errorf("--profile is only supported for cpp, not %s", gen.Slug())
+		}
 		_, supportLocalTest := gen.(lang.LocalTestable)
-		if runLocally && !supportLocalTest {
+		if (runLocally || stressTest || shrinkCase != 0 || watchTests || debugCase != "") && !supportLocalTest {
 			return fmt.Errorf("local test not supported for %s", cfg.Code.Lang)
 		}
 
+		if debugCase != "" {
+			if len(qs) != 1 {
+				return errors.New("--debug needs exactly one question")
+			}
+			return lang.DebugTestCase(qs[0], debugCase, debugConfig)
+		}
+
+		if stressTest {
+			var hasMismatch bool
+			for _, q := range qs {
+				passed, err := lang.StressTest(q, cfg.Code.Stress.Rounds, bruteCmd)
+				if err != nil {
+					log.Error("failed to run stress test", "question", q.TitleSlug, "err", err)
+				}
+				if !passed {
+					hasMismatch = true
+				}
+			}
+			if hasMismatch {
+				return exitCode(1)
+			}
+			return nil
+		}
+
 		user, err := c.GetUserStatus()
 		if err != nil {
 			user = &leetcode.UserStatus{}
@@ -87,8 +186,29 @@ leetgo test w330/`,
 		testLimiter := newLimiter(user)
 		submitLimiter := newLimiter(user)
 
+		if shrinkCase != 0 {
+			var hasFailure bool
+			for _, q := range qs {
+				expected := remoteExpectedOutput(q, c, gen, testLimiter)
+				_, err := lang.ShrinkTestCase(q, shrinkCase, bruteCmd, expected)
+				if err != nil {
+					log.Error("failed to shrink test case", "question", q.TitleSlug, "err", err)
+					hasFailure = true
+				}
+			}
+			if hasFailure {
+				return exitCode(1)
+			}
+			return nil
+		}
+
+		if watchTests {
+			return watchTest(cmd, qs, c, gen, testLimiter, runRemotely)
+		}
+
 		var hasFailedCase bool
 		var hasSubmitted bool
+		var suites []*report.Suite
 		for _, q := range qs {
 			var (
 				localPassed    = true
@@ -97,10 +217,13 @@ leetgo test w330/`,
 			)
 			if runLocally {
 				log.Info("running test locally", "question", q.TitleSlug)
-				localPassed, err = lang.RunLocalTest(q, targetCase)
+				suite, err := lang.RunLocalTest(q, targetCase, cmd.OutOrStdout())
 				if err != nil {
 					log.Error("failed to run test locally", "err", err)
+					suite = report.ErrorSuite(q, report.LocalTest, err)
 				}
+				localPassed = suite.Passed()
+				suites = append(suites, suite)
 			}
 			if runRemotely {
 				log.Info("running test remotely", "question", q.TitleSlug)
@@ -108,9 +231,15 @@ leetgo test w330/`,
 				if err != nil {
 					log.Error("failed to run test remotely", "err", err)
 					remotePassed = false
+					suites = append(suites, report.ErrorSuite(q, report.RemoteTest, err))
+					if errors.Is(err, context.Canceled) {
+						hasFailedCase = true
+						break
+					}
 				} else {
 					cmd.Print(result.Display(q))
 					remotePassed = result.CorrectAnswer
+					suites = append(suites, report.FromRunCheckResult(q, result))
 				}
 			}
 
@@ -121,8 +250,14 @@ leetgo test w330/`,
 				if err != nil {
 					submitAccepted = false
 					log.Error("failed to submit solution", "err", err)
+					suites = append(suites, report.ErrorSuite(q, report.Submit, err))
+					if errors.Is(err, context.Canceled) {
+						hasFailedCase = true
+						break
+					}
 				} else {
 					cmd.Print(result.Display(q))
+					suites = append(suites, report.FromSubmitCheckResult(q, result))
 					if !result.Accepted() {
 						submitAccepted = false
 						added, _ := appendToTestCases(q, result)
@@ -145,6 +280,10 @@ leetgo test w330/`,
 			}
 		}
 
+		err = writeReport(reportOut, suites)
+		if err != nil {
+			return err
+		}
 		if hasFailedCase {
 			return exitCode(1)
 		}
@@ -187,6 +326,10 @@ func runTestRemotely(
 		}
 	}
 
+	ctx, stop := interruptContext()
+	defer stop()
+	c = c.WithContext(ctx)
+
 	spin := newSpinner(cmd.ErrOrStderr())
 	spin.Suffix = " Running tests..."
 	spin.Reverse()
@@ -205,7 +348,7 @@ func runTestRemotely(
 	spin.Suffix = " Waiting for result..."
 	spin.Unlock()
 
-	testResult, err := waitResult(c, interResult.InterpretId)
+	testResult, err := waitResult(ctx, c, interResult.InterpretId)
 	if err != nil {
 		return nil, fmt.Errorf("failed to wait test result: %w", err)
 	}
@@ -217,12 +360,11 @@ func runTestRemotely(
 		if err != nil {
 			log.Debug("failed to update test cases", "err", err)
 		} else if updated {
-			content := []byte(cases.String())
-			err = utils.WriteFile(testCasesFile.GetPath(), content)
+			err = cases.Save()
 			if err != nil {
 				log.Debug("failed to update test cases", "err", err)
 			} else {
-				log.Info("`testcases.txt` updated")
+				log.Info("test cases file updated")
 			}
 		}
 	}
@@ -230,22 +372,64 @@ func runTestRemotely(
 	return r, nil
 }
 
-func waitResult(c leetcode.Client, submissionId string) (
-	leetcode.CheckResult,
-	error,
-) {
-	for {
-		result, err := c.CheckResult(submissionId)
+// remoteExpectedOutput asks LeetCode for the expected output of an input, by running the solution remotely.
+func remoteExpectedOutput(
+	q *leetcode.QuestionData,
+	c leetcode.Client,
+	gen lang.Lang,
+	limiter *utils.RateLimiter,
+) lang.ExpectedOutputFunc {
+	return func(input []string) (string, error) {
+		solution, err := lang.GetSolutionCode(q)
+		if err != nil {
+			return "", fmt.Errorf("failed to get solution code: %w", err)
+		}
+		ctx, stop := interruptContext()
+		defer stop()
+		c := c.WithContext(ctx)
+
+		limiter.Take()
+		interResult, err := c.RunCode(q, gen.Slug(), solution, strings.Join(input, "\n"))
+		if err != nil {
+			return "", fmt.Errorf("failed to run test: %w", err)
+		}
+		testResult, err := waitResult(ctx, c, interResult.InterpretId)
 		if err != nil {
-			return nil, err
+			return "", fmt.Errorf("failed to wait test result: %w", err)
 		}
-		if result.GetState() == "SUCCESS" {
-			return result, nil
+		r := testResult.(*leetcode.RunCheckResult)
+		if !r.ExpectedRunSuccess || len(r.ExpectedCodeAnswer) == 0 {
+			return "", errors.New("no expected answer, the input may be invalid")
 		}
-		time.Sleep(1 * time.Second)
+		return r.ExpectedCodeAnswer[0], nil
 	}
 }
 
+// interruptContext returns a context canceled by Ctrl-C, which stops the requests and the polling bound to it
+// instead of killing leetgo, until the returned function is called.
+func interruptContext() (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(context.Background(), os.Interrupt)
+}
+
+// waitResult waits for the result of a run or a submission, polling as configured in `leetcode.polling`.
+func waitResult(ctx context.Context, c leetcode.Client, submissionId string) (
+	leetcode.CheckResult,
+	error,
+) {
+	polling := config.Get().LeetCode.Polling
+	// The durations are verified when loading the config.
+	timeout, _ := time.ParseDuration(polling.Timeout)
+	interval, _ := time.ParseDuration(polling.Interval)
+	maxInterval, _ := time.ParseDuration(polling.MaxInterval)
+	return leetcode.WaitResult(
+		ctx, c, submissionId, leetcode.PollOptions{
+			Timeout:     timeout,
+			Interval:    interval,
+			MaxInterval: maxInterval,
+		},
+	)
+}
+
 func newLimiter(user *leetcode.UserStatus) *utils.RateLimiter {
 	if user.IsPremium {
 		return utils.NewRateLimiter(1 * time.Second)
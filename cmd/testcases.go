@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+
+	"github.com/j178/leetgo/lang"
+	"github.com/j178/leetgo/leetcode"
+	"github.com/j178/leetgo/utils"
+)
+
+var testCasesFormat string
+
+func init() {
+	testCasesCmd.Flags().StringVar(
+		&testCasesFormat,
+		"to",
+		lang.TestCasesFormatYAML,
+		"format to convert the test cases file to, one of: "+strings.Join(lang.TestCasesFormats, ", "),
+	)
+	_ = testCasesCmd.RegisterFlagCompletionFunc(
+		"to",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return lang.TestCasesFormats, cobra.ShellCompDirectiveNoFileComp
+		},
+	)
+}
+
+var testCasesCmd = &cobra.Command{
+	Use:       "testcases qid",
+	Short:     "Convert test cases file between txt, yaml and json",
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: []string{"today", "last", "last/"},
+	Example: `leetgo testcases last
+leetgo testcases 1 --to json
+leetgo testcases 1 --to txt`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c := leetcode.NewClient(leetcode.ReadCredentials())
+		qs, err := leetcode.ParseQID(args[0], c)
+		if err != nil {
+			return err
+		}
+		for _, q := range qs {
+			path, err := lang.ConvertTestCases(q, testCasesFormat)
+			if err != nil {
+				return err
+			}
+			log.Info("test cases converted", "question", q.TitleSlug, "file", utils.RelToCwd(path))
+		}
+		return nil
+	},
+}
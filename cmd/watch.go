@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/cobra"
+
+	"github.com/j178/leetgo/lang"
+	"github.com/j178/leetgo/leetcode"
+	"github.com/j178/leetgo/utils"
+)
+
+// Editors may write a file several times on a single save, wait for them to settle before re-running.
+const watchDebounce = 300 * time.Millisecond
+
+// watchTest runs the local test of the questions, and re-runs it whenever the code file or `testcases.txt`
+// of a question changes, until interrupted. If runRemote is true, the test is also run remotely after
+// the local test passed.
+func watchTest(
+	cmd *cobra.Command,
+	qs []*leetcode.QuestionData,
+	c leetcode.Client,
+	gen lang.Lang,
+	limiter *utils.RateLimiter,
+	runRemote bool,
+) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to watch files: %w", err)
+	}
+	defer watcher.Close()
+
+	// Many editors save a file by replacing it, which a watch on the file itself doesn't survive,
+	// so the directories are watched and the events are filtered by file.
+	files := make(map[string]*leetcode.QuestionData)
+	contents := make(map[string]string)
+	dirs := make(map[string]bool)
+	for _, q := range qs {
+		genResult, err := lang.GeneratePathsOnly(q)
+		if err != nil {
+			return err
+		}
+		var paths []string
+		if f := genResult.GetFile(lang.CodeFile); f != nil {
+			paths = append(paths, f.GetPath())
+		}
+		if f := genResult.GetFile(lang.TestCasesFile); f != nil {
+			// A structured test cases file may be created or removed while watching.
+			paths = append(paths, lang.TestCasesPaths(f)...)
+		}
+		for _, path := range paths {
+			path, err := filepath.Abs(path)
+			if err != nil {
+				return err
+			}
+			files[path] = q
+			contents[path], _ = readFileString(path)
+			dir := filepath.Dir(path)
+			if dirs[dir] {
+				continue
+			}
+			if err := watcher.Add(dir); err != nil {
+				return fmt.Errorf("failed to watch %s: %w", utils.RelToCwd(dir), err)
+			}
+			dirs[dir] = true
+		}
+	}
+
+	run := func(q *leetcode.QuestionData) {
+		log.Info("running test locally", "question", q.TitleSlug)
+		suite, err := lang.RunLocalTest(q, targetCase, cmd.OutOrStdout())
+		if err != nil {
+			log.Error("failed to run test locally", "err", err)
+			return
+		}
+		if !runRemote || !suite.Passed() {
+			return
+		}
+		log.Info("running test remotely", "question", q.TitleSlug)
+		result, err := runTestRemotely(cmd, q, c, gen, limiter)
+		if err != nil {
+			log.Error("failed to run test remotely", "err", err)
+			return
+		}
+		cmd.Print(result.Display(q))
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	// All the questions are run once at start.
+	pending := make(map[*leetcode.QuestionData]bool, len(qs))
+	for _, q := range qs {
+		pending[q] = true
+	}
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			log.Error("failed to watch files", "err", err)
+		case ev, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if !ev.Has(fsnotify.Write) && !ev.Has(fsnotify.Create) && !ev.Has(fsnotify.Remove) {
+				continue
+			}
+			q := files[ev.Name]
+			if q == nil {
+				continue
+			}
+			// Skip the events that don't change the content, e.g. saving an unmodified file.
+			content, _ := readFileString(ev.Name)
+			if content == contents[ev.Name] {
+				continue
+			}
+			contents[ev.Name] = content
+			log.Debug("file changed", "file", utils.RelToCwd(ev.Name))
+			pending[q] = true
+			timer.Reset(watchDebounce)
+		case <-timer.C:
+			clearScreen(cmd.OutOrStdout())
+			for _, q := range qs {
+				if pending[q] {
+					run(q)
+				}
+			}
+			clear(pending)
+			log.Info("watching for changes, press Ctrl-C to stop")
+		}
+	}
+}
+
+func readFileString(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	return string(content), err
+}
+
+// clearScreen clears the terminal, it does nothing if the output is not a terminal.
+func clearScreen(w io.Writer) {
+	f, ok := w.(*os.File)
+	if !ok {
+		return
+	}
+	if stat, err := f.Stat(); err != nil || stat.Mode()&os.ModeCharDevice == 0 {
+		return
+	}
+	_, _ = io.WriteString(f, "\033[H\033[2J")
+}
@@ -8,7 +8,9 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/dustin/go-humanize"
 	"github.com/google/shlex"
 	"github.com/mitchellh/go-homedir"
 	"github.com/spf13/viper"
@@ -68,17 +70,60 @@ type Modifier struct {
 	Script string `yaml:"script,omitempty" mapstructure:"script"`
 }
 
+type Judge struct {
+	Question string `yaml:"question" mapstructure:"question" comment:"Question id or slug."`
+	Script   string `yaml:"script,omitempty" mapstructure:"script" comment:"JavaScript that defines a judge(input, expected, actual) function."`
+	Command  string `yaml:"command,omitempty" mapstructure:"command" comment:"Executable called with paths of the input, expected output and actual output files."`
+	// Without script or command, the built-in judger is used with the configured order-insensitive levels.
+	IgnoreOrder []int  `yaml:"ignore_order,omitempty" mapstructure:"ignore_order" comment:"Nesting levels of the output compared ignoring order, 0 is the outermost list, e.g. [0, 1] for group-anagrams."`
+	Limits      Limits `yaml:"limits,omitempty" mapstructure:"limits" comment:"Resource limits of the question, override the limits of the language."`
+}
+
+// Limits are the resource limits of a local test case, an empty value falls back to the upper level.
+type Limits struct {
+	Time   string `yaml:"time,omitempty" mapstructure:"time" comment:"CPU time limit of a test case, e.g. 3s, 500ms."`
+	Memory string `yaml:"memory,omitempty" mapstructure:"memory" comment:"Memory limit of a test case, e.g. 256MB, on the resident memory of the test program (not supported on Windows)."`
+	Output string `yaml:"output,omitempty" mapstructure:"output" comment:"Output size limit of a test case, e.g. 16MB."`
+}
+
+// StressParam constrains the random values generated for a parameter in stress tests, unset fields are seeded from
+// the "Constraints" section of the question.
+type StressParam struct {
+	Range    []int64 `yaml:"range,omitempty" mapstructure:"range" comment:"Range of numbers, array elements and node values, e.g. [-10, 10]."`
+	Len      []int   `yaml:"len,omitempty" mapstructure:"len" comment:"Range of the length of arrays and strings, or the number of nodes of trees and lists, e.g. [1, 8]."`
+	InnerLen []int   `yaml:"inner_len,omitempty" mapstructure:"inner_len" comment:"Range of the length of inner arrays and strings, e.g. [1, 8]."`
+	Charset  string  `yaml:"charset,omitempty" mapstructure:"charset" comment:"Characters of strings, e.g. abc."`
+	Distinct bool    `yaml:"distinct,omitempty" mapstructure:"distinct" comment:"Whether elements of arrays are distinct."`
+}
+
+type StressTest struct {
+	Question string                 `yaml:"question" mapstructure:"question" comment:"Question id or slug."`
+	Brute    string                 `yaml:"brute,omitempty" mapstructure:"brute" comment:"Command of the brute-force solution, it reads a test case from stdin and prints the output.\nAn executable brute (e.g. brute.py) next to testcases.txt is used if not set."`
+	Params   map[string]StressParam `yaml:"params,omitempty" mapstructure:"params" comment:"Constraints of the parameters, keyed by parameter name."`
+}
+
+type StressConfig struct {
+	Rounds    int          `yaml:"rounds" mapstructure:"rounds" comment:"Number of random cases to run in a stress test.\n(will be overridden by command line flag --rounds)."`
+	Questions []StressTest `yaml:"questions,omitempty" mapstructure:"questions" comment:"Per question settings of stress tests."`
+}
+
 type CodeConfig struct {
 	Lang                    string         `yaml:"lang" mapstructure:"lang" comment:"Language of code generated for questions: go, cpp, python, java... \n(will be overridden by command line flag -l/--lang)."`
 	FilenameTemplate        string         `yaml:"filename_template" mapstructure:"filename_template" comment:"The default template to generate filename (without extension), e.g. {{.Id}}.{{.Slug}}\nAvailable attributes: Id, Slug, Title, Difficulty, Lang, SlugIsMeaningful\n(Most questions have descriptive slugs, but some consist of random characters. The SlugIsMeaningful boolean indicates whether a slug is meaningful.)\nAvailable functions: lower, upper, trim, padWithZero, toUnderscore, group."`
 	SeparateDescriptionFile bool           `yaml:"separate_description_file" mapstructure:"separate_description_file" comment:"Generate question description into a separate question.md file, otherwise it will be embed in the code file."`
 	Blocks                  []Block        `yaml:"blocks,omitempty" mapstructure:"blocks" comment:"Default block definitions for all languages."`
 	Modifiers               []Modifier     `yaml:"modifiers,omitempty" mapstructure:"modifiers" comment:"Default modifiers for all languages."`
+	Jobs                    int            `yaml:"jobs" mapstructure:"jobs" comment:"Number of test cases to run concurrently in local test, 0 means the number of CPUs.\n(will be overridden by command line flag -j/--jobs)."`
+	Limits                  Limits         `yaml:"limits" mapstructure:"limits" comment:"Default resource limits of local test cases, can be overridden per language and per question (in judges)."`
+	Judges                  []Judge        `yaml:"judges,omitempty" mapstructure:"judges" comment:"Special judges for questions that accept more than one answer.\nA judge.js or an executable judge next to testcases.txt is also used."`
+	Stress                  StressConfig   `yaml:"stress" mapstructure:"stress" comment:"Stress tests compare the solution with a brute-force one on random inputs (leetgo test --stress)."`
 	Go                      GoConfig       `yaml:"go" mapstructure:"go"`
 	Python                  PythonConfig   `yaml:"python3" mapstructure:"python3"`
 	Cpp                     CppConfig      `yaml:"cpp" mapstructure:"cpp"`
+	C                       CConfig        `yaml:"c" mapstructure:"c"`
 	Rust                    RustConfig     `yaml:"rust" mapstructure:"rust"`
 	Java                    BaseLangConfig `yaml:"java" mapstructure:"java"`
+	Kotlin                  KotlinConfig   `yaml:"kotlin" mapstructure:"kotlin"`
 	// Add more languages here
 }
 
@@ -88,6 +133,7 @@ type BaseLangConfig struct {
 	SeparateDescriptionFile bool       `yaml:"separate_description_file,omitempty" mapstructure:"separate_description_file" comment:"Generate question description into a separate question.md file, otherwise it will be embed in the code file."`
 	Blocks                  []Block    `yaml:"blocks,omitempty" mapstructure:"blocks" comment:"Replace some blocks of the generated code."`
 	Modifiers               []Modifier `yaml:"modifiers,omitempty" mapstructure:"modifiers" comment:"Functions that modify the generated code."`
+	Limits                  Limits     `yaml:"limits,omitempty" mapstructure:"limits" comment:"Resource limits of local test cases, override the default code.limits."`
 }
 
 type GoConfig struct {
@@ -101,8 +147,22 @@ type PythonConfig struct {
 
 type CppConfig struct {
 	BaseLangConfig `yaml:",inline" mapstructure:",squash"`
-	CXX            string `yaml:"cxx" mapstructure:"cxx" comment:"C++ compiler"`
-	CXXFLAGS       string `yaml:"cxxflags" mapstructure:"cxxflags" comment:"C++ compiler flags (our Leetcode I/O library implementation requires C++17)."`
+	CXX            string            `yaml:"cxx" mapstructure:"cxx" comment:"C++ compiler"`
+	CXXFLAGS       string            `yaml:"cxxflags" mapstructure:"cxxflags" comment:"C++ compiler flags shared by all build profiles (our Leetcode I/O library implementation requires C++17)."`
+	Profile        string            `yaml:"profile" mapstructure:"profile" comment:"Build profile used in local test, one of the profiles below.\n(will be overridden by command line flag --profile)."`
+	Profiles       map[string]string `yaml:"profiles" mapstructure:"profiles" comment:"Build profiles, the name to the compiler flags appended to cxxflags (so they override e.g. its -O2), each profile is built to its own binary.\nSanitizer reports of the asan and ubsan profiles are shown in the verdict of the failed case."`
+}
+
+type CConfig struct {
+	BaseLangConfig `yaml:",inline" mapstructure:",squash"`
+	CC             string `yaml:"cc" mapstructure:"cc" comment:"C compiler"`
+	CFLAGS         string `yaml:"cflags" mapstructure:"cflags" comment:"C compiler flags (our Leetcode I/O library implementation requires C11)."`
+}
+
+type KotlinConfig struct {
+	BaseLangConfig `yaml:",inline" mapstructure:",squash"`
+	Kotlinc        string `yaml:"kotlinc" mapstructure:"kotlinc" comment:"Path to the Kotlin compiler."`
+	Flags          string `yaml:"flags" mapstructure:"flags" comment:"Kotlin compiler flags."`
 }
 
 type RustConfig struct {
@@ -134,6 +194,14 @@ func (c *Credentials) UnmarshalYAML(node *yaml.Node) error {
 type LeetCodeConfig struct {
 	Site        LeetcodeSite `yaml:"site" mapstructure:"site" comment:"LeetCode site, https://leetcode.com or https://leetcode.cn"`
 	Credentials Credentials  `yaml:"credentials" mapstructure:"credentials" comment:"Credentials to access LeetCode."`
+	Polling     Polling      `yaml:"polling" mapstructure:"polling" comment:"How to wait for the results of remote tests and submissions."`
+}
+
+// Polling controls how the results of remote tests and submissions are polled.
+type Polling struct {
+	Timeout     string `yaml:"timeout" mapstructure:"timeout" comment:"Give up waiting for a result after this long, e.g. 2m. 0 means waiting until interrupted."`
+	Interval    string `yaml:"interval" mapstructure:"interval" comment:"Interval between the first polls, doubled after each poll."`
+	MaxInterval string `yaml:"max_interval" mapstructure:"max_interval" comment:"Upper bound of the interval between polls."`
 }
 
 func (c *Config) HomeDir() string {
@@ -216,6 +284,14 @@ func defaultConfig() *Config {
 			Lang:                    "go",
 			FilenameTemplate:        `{{ .Id | padWithZero 4 }}{{ if .SlugIsMeaningful }}.{{ .Slug }}{{ end }}`,
 			SeparateDescriptionFile: true,
+			Jobs:                    1,
+			Limits: Limits{
+				Time:   "3s",
+				Output: "16MB",
+			},
+			Stress: StressConfig{
+				Rounds: 200,
+			},
 			Modifiers: []Modifier{
 				{Name: "removeUselessComments"},
 			},
@@ -234,12 +310,28 @@ func defaultConfig() *Config {
 				BaseLangConfig: BaseLangConfig{OutDir: "cpp"},
 				CXX:            "g++",
 				CXXFLAGS:       "-O2 -std=c++17",
+				Profile:        "release",
+				Profiles: map[string]string{
+					"release": "",
+					"debug":   "-O0 -g",
+					"asan":    "-O1 -g -fno-omit-frame-pointer -fsanitize=address",
+					"ubsan":   "-O1 -g -fsanitize=undefined -fno-sanitize-recover=all",
+				},
+			},
+			C: CConfig{
+				BaseLangConfig: BaseLangConfig{OutDir: "c"},
+				CC:             "gcc",
+				CFLAGS:         "-O2 -std=gnu11",
 			},
 			Python: PythonConfig{
 				BaseLangConfig: BaseLangConfig{OutDir: "python"},
 				Executable:     constants.DefaultPython,
 			},
 			Java: BaseLangConfig{OutDir: "java"},
+			Kotlin: KotlinConfig{
+				BaseLangConfig: BaseLangConfig{OutDir: "kotlin"},
+				Kotlinc:        "kotlinc",
+			},
 			Rust: RustConfig{BaseLangConfig: BaseLangConfig{OutDir: "rust"}},
 			// Add more languages here
 		},
@@ -248,6 +340,11 @@ func defaultConfig() *Config {
 			Credentials: Credentials{
 				From: []string{"browser"},
 			},
+			Polling: Polling{
+				Timeout:     "2m",
+				Interval:    "500ms",
+				MaxInterval: "5s",
+			},
 		},
 		Editor: Editor{
 			Use: "none",
@@ -300,6 +397,9 @@ func verify(c *Config) error {
 			return errors.New("username/password authentication is not supported for leetcode.com")
 		}
 	}
+	if err := c.LeetCode.Polling.verify(); err != nil {
+		return fmt.Errorf("invalid `leetcode.polling`: %w", err)
+	}
 
 	if c.Editor.Args != "" {
 		if _, err := shlex.Split(c.Editor.Args); err != nil {
@@ -311,6 +411,111 @@ func verify(c *Config) error {
 			return fmt.Errorf("invalid `code.cpp.cxxflags`: %w", err)
 		}
 	}
+	if _, ok := c.Code.Cpp.Profiles[c.Code.Cpp.Profile]; !ok {
+		return fmt.Errorf("unknown `code.cpp.profile`: %s", c.Code.Cpp.Profile)
+	}
+	for name, flags := range c.Code.Cpp.Profiles {
+		if _, err := shlex.Split(flags); err != nil {
+			return fmt.Errorf("invalid flags of `code.cpp.profiles.%s`: %w", name, err)
+		}
+	}
+	if c.Code.C.CFLAGS != "" {
+		if _, err := shlex.Split(c.Code.C.CFLAGS); err != nil {
+			return fmt.Errorf("invalid `code.c.cflags`: %w", err)
+		}
+	}
+	if c.Code.Kotlin.Flags != "" {
+		if _, err := shlex.Split(c.Code.Kotlin.Flags); err != nil {
+			return fmt.Errorf("invalid `code.kotlin.flags`: %w", err)
+		}
+	}
+	for _, t := range c.Code.Stress.Questions {
+		if t.Question == "" {
+			return errors.New("`code.stress.questions.question` not set")
+		}
+		if t.Brute != "" {
+			if _, err := shlex.Split(t.Brute); err != nil {
+				return fmt.Errorf("invalid `code.stress.questions.brute` of %s: %w", t.Question, err)
+			}
+		}
+		for name, p := range t.Params {
+			if err := p.verify(); err != nil {
+				return fmt.Errorf("invalid stress params %s of %s: %w", name, t.Question, err)
+			}
+		}
+	}
+	if err := c.Code.Limits.verify(); err != nil {
+		return fmt.Errorf("invalid `code.limits`: %w", err)
+	}
+	for _, j := range c.Code.Judges {
+		if j.Question == "" {
+			return errors.New("`code.judges.question` not set")
+		}
+		if j.Script != "" && j.Command != "" {
+			return fmt.Errorf("judge of %s should have only one of `script` and `command`", j.Question)
+		}
+		if j.Script == "" && j.Command == "" && j.IgnoreOrder == nil && j.Limits == (Limits{}) {
+			return fmt.Errorf(
+				"judge of %s should have one of `script`, `command`, `ignore_order` and `limits`",
+				j.Question,
+			)
+		}
+		if err := j.Limits.verify(); err != nil {
+			return fmt.Errorf("invalid `code.judges.limits` of %s: %w", j.Question, err)
+		}
+		if j.Command != "" {
+			if _, err := shlex.Split(j.Command); err != nil {
+				return fmt.Errorf("invalid `code.judges.command` of %s: %w", j.Question, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (p StressParam) verify() error {
+	if p.Range != nil && (len(p.Range) != 2 || p.Range[0] > p.Range[1]) {
+		return fmt.Errorf("range should be [min, max], got %v", p.Range)
+	}
+	for _, r := range [][]int{p.Len, p.InnerLen} {
+		if r != nil && (len(r) != 2 || r[0] < 0 || r[0] > r[1]) {
+			return fmt.Errorf("length should be [min, max], got %v", r)
+		}
+	}
+	return nil
+}
+
+func (p Polling) verify() error {
+	if v, err := time.ParseDuration(p.Timeout); err != nil || v < 0 {
+		return fmt.Errorf("timeout should be a non-negative duration, got %q", p.Timeout)
+	}
+	durations := []struct{ name, value string }{
+		{"interval", p.Interval},
+		{"max_interval", p.MaxInterval},
+	}
+	for _, d := range durations {
+		if v, err := time.ParseDuration(d.value); err != nil || v <= 0 {
+			return fmt.Errorf("%s should be a positive duration, got %q", d.name, d.value)
+		}
+	}
+	return nil
+}
+
+func (l Limits) verify() error {
+	if l.Time != "" {
+		if _, err := time.ParseDuration(l.Time); err != nil {
+			return fmt.Errorf("time: %w", err)
+		}
+	}
+	if l.Memory != "" {
+		if _, err := humanize.ParseBytes(l.Memory); err != nil {
+			return fmt.Errorf("memory: %w", err)
+		}
+	}
+	if l.Output != "" {
+		if _, err := humanize.ParseBytes(l.Output); err != nil {
+			return fmt.Errorf("output: %w", err)
+		}
+	}
 	return nil
 }
 
@@ -20,6 +20,9 @@ type LastQuestion struct {
 type State struct {
 	LastQuestion LastQuestion `json:"last_question"`
 	LastContest  string       `json:"last_contest"`
+	// Synced records the questions handled by `leetgo sync`, from "<lang>/<slug>" to the id of the restored
+	// submission, or "" if the question has no accepted submission in the language.
+	Synced map[string]string `json:"synced,omitempty"`
 }
 
 type States map[string]State
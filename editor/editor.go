@@ -57,6 +57,11 @@ func (ed *editor) substituteArgs(result *lang.GenerateResult) ([]string, error)
 		return f.GetPath()
 	}
 
+	testCasesPath := ""
+	if f := result.GetFile(lang.TestCasesFile); f != nil {
+		testCasesPath = lang.TestCasesPath(f)
+	}
+
 	data := struct {
 		Folder          string
 		Files           string
@@ -70,7 +75,7 @@ func (ed *editor) substituteArgs(result *lang.GenerateResult) ([]string, error)
 		CodeFile:        getPath(lang.CodeFile),
 		TestFile:        getPath(lang.TestFile),
 		DescriptionFile: getPath(lang.DocFile),
-		TestCasesFile:   getPath(lang.TestCasesFile),
+		TestCasesFile:   testCasesPath,
 	}
 
 	args := slices.Clone(ed.args)
@@ -98,6 +103,9 @@ func (ed *editor) substituteArgs(result *lang.GenerateResult) ([]string, error)
 			allFiles := make([]string, len(result.Files))
 			for j, f := range result.Files {
 				allFiles[j] = f.GetPath()
+				if f.Type == lang.TestCasesFile {
+					allFiles[j] = testCasesPath
+				}
 			}
 			args = slices.Replace(args, i, i+1, allFiles...)
 			break
@@ -117,6 +117,13 @@ type LocalTestable interface {
 	RunLocalTest(q *leetcode.QuestionData, outDir string, targetCase string) (bool, error)
 }
 
+// localTestBuilder is implemented by the builtin languages. They build the test program once and run each test
+// case through the returned runner, so stress tests, shrinking and `leetgo run` can reuse the build.
+type localTestBuilder interface {
+	// buildLocalTest builds the test program of the question, and returns the runner of its test cases.
+	buildLocalTest(q *leetcode.QuestionData, outDir string) (*GenerateResult, caseRunner, error)
+}
+
 func getCodeStringConfig(lang Lang, key string) string {
 	ans := viper.GetString("code." + lang.Slug() + "." + key)
 	if ans != "" {
@@ -142,24 +149,42 @@ func getOutDir(q *leetcode.QuestionData, lang Lang) string {
 	if q.IsContest() {
 		return filepath.Join(cfg.ProjectRoot(), cfg.Contest.OutDir)
 	}
+	return getLangOutDir(lang)
+}
+
+// getLangOutDir returns the absolute path of the output directory of the language, regardless of the question.
+func getLangOutDir(lang Lang) string {
 	outDir := getCodeStringConfig(lang, "out_dir")
 	// If outDir is not set, use the language slug as the outDir.
 	if outDir == "" {
 		outDir = lang.Slug()
 	}
-	outDir = filepath.Join(cfg.ProjectRoot(), outDir)
-	return outDir
+	return filepath.Join(config.Get().ProjectRoot(), outDir)
 }
 
-func getTempBinFile(q *leetcode.QuestionData, lang Lang) (string, error) {
+// getTempBinFile returns the path of the executable built for the question. Builds of different profiles,
+// e.g. debug, are kept in different files, an empty profile is the default build.
+func getTempBinFile(q *leetcode.QuestionData, lang Lang, profile string) (string, error) {
 	tmpDir := config.Get().TempDir()
 	if err := utils.CreateIfNotExists(tmpDir, true); err != nil {
 		return "", err
 	}
 	filename := fmt.Sprintf("%s-%s.exec", q.TitleSlug, lang.Slug())
+	if profile != "" {
+		filename = fmt.Sprintf("%s-%s-%s.exec", q.TitleSlug, lang.Slug(), profile)
+	}
 	return filepath.Join(tmpDir, filename), nil
 }
 
+// getTempBinDir returns a directory to put build outputs of the question, for languages that produce more than one file.
+func getTempBinDir(q *leetcode.QuestionData, lang Lang) (string, error) {
+	dir := filepath.Join(config.Get().TempDir(), fmt.Sprintf("%s-%s", q.TitleSlug, lang.Slug()))
+	if err := utils.CreateIfNotExists(dir, true); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
 func separateDescriptionFile(lang Lang) bool {
 	ans := viper.Get("code." + lang.Slug() + ".separate_description_file")
 	if ans != nil {
@@ -0,0 +1,15 @@
+package lang
+
+import (
+	"testing"
+)
+
+func TestLocalTestableLangsCanBuild(t *testing.T) {
+	for _, l := range SupportedLangs {
+		_, testable := l.(LocalTestable)
+		_, builder := l.(localTestBuilder)
+		if testable != builder {
+			t.Errorf("%s: LocalTestable = %v, localTestBuilder = %v", l.Slug(), testable, builder)
+		}
+	}
+}
@@ -0,0 +1,233 @@
+package lang
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	strip "github.com/grokify/html-strip-tags-go"
+
+	"github.com/j178/leetgo/leetcode"
+	"github.com/j178/leetgo/utils"
+)
+
+// bash runs shell questions. The solution reads a file like `file.txt` from the working directory,
+// so every test case runs in a temporary directory with the file materialized from the input.
+//
+// Test cases of shell questions have the content of the file as input and the expected stdout as output,
+// both quoted as JSON strings to fit in a single line:
+//
+//	input:
+//	"Line 1\nLine 2"
+//	output:
+//	"Line 2"
+type bash struct {
+	baseLang
+}
+
+var (
+	bashInputFileRe = regexp.MustCompile(`\b[\w-]+\.txt\b`)
+	bashPreRe       = regexp.MustCompile(`(?s)<pre>(.*?)</pre>`)
+)
+
+// bashInputFile returns the name of the file the script is expected to read, `file.txt` for most of the questions.
+func bashInputFile(q *leetcode.QuestionData) string {
+	content := q.Content
+	if content == "" {
+		content = q.TranslatedContent
+	}
+	if name := bashInputFileRe.FindString(content); name != "" {
+		return name
+	}
+	return "file.txt"
+}
+
+// parseBashExample parses the example of the question, the content of the input file is in the first `<pre>` block
+// and the expected output is in the second one.
+func parseBashExample(q *leetcode.QuestionData) (string, string, bool) {
+	content := q.Content
+	if content == "" {
+		content = q.TranslatedContent
+	}
+	matches := bashPreRe.FindAllStringSubmatch(content, 2)
+	if len(matches) < 2 {
+		return "", "", false
+	}
+	clean := func(s string) string {
+		s = html.UnescapeString(strip.StripTags(s))
+		return strings.Trim(s, "\n") + "\n"
+	}
+	return clean(matches[0][1]), clean(matches[1][1]), true
+}
+
+func (b bash) generateTestCasesContent(q *leetcode.QuestionData) string {
+	tc := TestCases{Question: q}
+	if input, output, ok := parseBashExample(q); ok {
+		tc.AddCase(
+			TestCase{
+				Input:  []string{strconv.Quote(input)},
+				Output: strconv.Quote(output),
+			},
+		)
+	}
+	return tc.String()
+}
+
+func (b bash) generateTestCasesFile(q *leetcode.QuestionData, filename string) (FileOutput, error) {
+	return FileOutput{
+		Filename: filename,
+		Content:  b.generateTestCasesContent(q),
+		Type:     TestCasesFile,
+	}, nil
+}
+
+func runBashCase(ctx context.Context, q *leetcode.QuestionData, c TestCase, limits caseLimits, script string) (
+	string,
+	*os.ProcessState,
+	error,
+) {
+	input, err := strconv.Unquote(c.Input[0])
+	if err != nil {
+		return "", nil, startError{fmt.Errorf("invalid input: %w", err)}
+	}
+	tmpDir, err := os.MkdirTemp("", "leetgo-bash-")
+	if err != nil {
+		return "", nil, startError{err}
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	// Keep the script out of the working directory, so `ls` and globs in the solution only see the input file.
+	scriptFile := filepath.Join(tmpDir, "solution.sh")
+	workDir := filepath.Join(tmpDir, "work")
+	if err := utils.WriteFile(scriptFile, []byte(script)); err != nil {
+		return "", nil, startError{err}
+	}
+	if err := utils.WriteFile(filepath.Join(workDir, bashInputFile(q)), []byte(input)); err != nil {
+		return "", nil, startError{err}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stdout := &limitedWriter{limit: limits.output, onExceed: cancel}
+	stderr := &limitedWriter{limit: limits.output, onExceed: cancel}
+	cmd := exec.CommandContext(ctx, "bash", scriptFile)
+	cmd.Dir = workDir
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	// Commands in a pipeline may outlive the killed bash process and hold the output open.
+	cmd.WaitDelay = time.Second
+	err = runCommand(cmd, limits.memory)
+	if stdout.exceeded || stderr.exceeded {
+		err = errOutputLimitExceeded
+	}
+	output := fmt.Sprintf("%s\n%s %s\n", stderr, testCaseOutputMark, strconv.Quote(stdout.String()))
+	return output, cmd.ProcessState, err
+}
+
+func (b bash) buildLocalTest(q *leetcode.QuestionData, outDir string) (*GenerateResult, caseRunner, error) {
+	genResult, err := b.GeneratePaths(q)
+	if err != nil {
+		return nil, nil, fmt.Errorf("generate paths failed: %w", err)
+	}
+	genResult.SetOutDir(outDir)
+
+	codeFile := genResult.GetFile(CodeFile)
+	if !utils.IsExist(codeFile.GetPath()) {
+		return nil, nil, fmt.Errorf("file %s not found", utils.RelToCwd(codeFile.GetPath()))
+	}
+	// Only the code between the markers is run, the description comment is not valid bash.
+	script, err := getSolutionCodeFromFile(codeFile)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	runner := func(ctx context.Context, c TestCase, limits caseLimits) (string, *os.ProcessState, error) {
+		return runBashCase(ctx, q, c, limits, script)
+	}
+	return genResult, runner, nil
+}
+
+func (b bash) RunLocalTest(q *leetcode.QuestionData, outDir string, targetCase string) (bool, error) {
+	return runLocalTest(b, q, outDir, targetCase)
+}
+
+func (b bash) Generate(q *leetcode.QuestionData) (*GenerateResult, error) {
+	filenameTmpl := getFilenameTemplate(q, b)
+	baseFilename, err := q.GetFormattedFilename(b.slug, filenameTmpl)
+	if err != nil {
+		return nil, err
+	}
+	genResult := &GenerateResult{
+		Question: q,
+		Lang:     b,
+		SubDir:   baseFilename,
+	}
+
+	separateDescriptionFile := separateDescriptionFile(b)
+	blocks := getBlocks(b)
+	modifiers, err := getModifiers(b, builtinModifiers)
+	if err != nil {
+		return nil, err
+	}
+	codeFile, err := b.generateCodeFile(q, "solution.sh", blocks, modifiers, separateDescriptionFile)
+	if err != nil {
+		return nil, err
+	}
+	testcaseFile, err := b.generateTestCasesFile(q, "testcases.txt")
+	if err != nil {
+		return nil, err
+	}
+	genResult.AddFile(codeFile)
+	genResult.AddFile(testcaseFile)
+
+	if separateDescriptionFile {
+		docFile, err := b.generateDescriptionFile(q, "question.md")
+		if err != nil {
+			return nil, err
+		}
+		genResult.AddFile(docFile)
+	}
+
+	return genResult, nil
+}
+
+func (b bash) GeneratePaths(q *leetcode.QuestionData) (*GenerateResult, error) {
+	filenameTmpl := getFilenameTemplate(q, b)
+	baseFilename, err := q.GetFormattedFilename(b.slug, filenameTmpl)
+	if err != nil {
+		return nil, err
+	}
+	genResult := &GenerateResult{
+		SubDir:   baseFilename,
+		Question: q,
+		Lang:     b,
+	}
+	genResult.AddFile(
+		FileOutput{
+			Filename: "solution.sh",
+			Type:     CodeFile,
+		},
+	)
+	genResult.AddFile(
+		FileOutput{
+			Filename: "testcases.txt",
+			Type:     TestCasesFile,
+		},
+	)
+	if separateDescriptionFile(b) {
+		genResult.AddFile(
+			FileOutput{
+				Filename: "question.md",
+				Type:     DocFile,
+			},
+		)
+	}
+	return genResult, nil
+}
@@ -0,0 +1,465 @@
+package lang
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/shlex"
+
+	"github.com/j178/leetgo/config"
+	"github.com/j178/leetgo/leetcode"
+	cUtils "github.com/j178/leetgo/testutils/c"
+	"github.com/j178/leetgo/utils"
+)
+
+type cLang struct {
+	baseLang
+}
+
+func (c cLang) InitWorkspace(outDir string) error {
+	if should, err := c.shouldInit(outDir); err != nil || !should {
+		return err
+	}
+
+	headerPath := filepath.Join(outDir, cUtils.HeaderName)
+	err := utils.WriteFile(headerPath, cUtils.HeaderContent)
+	if err != nil {
+		return err
+	}
+
+	err = UpdateDep(c)
+	return err
+}
+
+func (c cLang) shouldInit(outDir string) (bool, error) {
+	headerPath := filepath.Join(outDir, cUtils.HeaderName)
+	if !utils.IsExist(headerPath) {
+		return true, nil
+	}
+
+	update, err := IsDepUpdateToDate(c)
+	if err != nil {
+		return false, err
+	}
+	return !update, nil
+}
+
+var cTypes = map[string]string{
+	"void":      "void",
+	"integer":   "int",
+	"long":      "long long",
+	"string":    "char *",
+	"double":    "double",
+	"ListNode":  "struct ListNode *",
+	"TreeNode":  "struct TreeNode *",
+	"boolean":   "bool",
+	"character": "char",
+}
+
+// cIONames are the names used by the scan and print functions of LC_IO.h, e.g. lc_scan_int_array.
+var cIONames = map[string]string{
+	"integer":   "int",
+	"long":      "long",
+	"string":    "string",
+	"double":    "double",
+	"ListNode":  "list",
+	"TreeNode":  "tree",
+	"boolean":   "bool",
+	"character": "char",
+}
+
+func (c cLang) getCTypeName(t string) (int, string, string) {
+	elem := strings.ReplaceAll(t, "[]", "")
+	return strings.Count(t, "[]"), cTypes[elem], cIONames[elem]
+}
+
+// declare returns a C declaration of a variable with d levels of pointers to type t.
+func (c cLang) declare(d int, t string, n string) string {
+	decl := t
+	if d > 0 && !strings.HasSuffix(decl, "*") {
+		decl += " "
+	}
+	decl += strings.Repeat("*", d)
+	if !strings.HasSuffix(decl, "*") {
+		decl += " "
+	}
+	return decl + n
+}
+
+// generateScanCode returns the code to declare and scan a parameter from `p`, and the arguments to pass it to
+// a function in LeetCode's C calling convention: arrays are followed by their sizes, matrices by their sizes and
+// column sizes.
+func (c cLang) generateScanCode(param leetcode.MetaDataParam, indent string) (string, []string) {
+	dimCnt, cType, ioName := c.getCTypeName(param.Type)
+	switch dimCnt {
+	case 0:
+		code := fmt.Sprintf("%s%s = lc_scan_%s(&lc_p_);\n", indent, c.declare(0, cType, param.Name), ioName)
+		return code, []string{param.Name}
+	case 1:
+		size := param.Name + "Size"
+		code := fmt.Sprintf("%sint %s;\n", indent, size)
+		code += fmt.Sprintf(
+			"%s%s = lc_scan_%s_array(&lc_p_, &%s);\n",
+			indent,
+			c.declare(1, cType, param.Name),
+			ioName,
+			size,
+		)
+		return code, []string{param.Name, size}
+	default:
+		size, colSize := param.Name+"Size", param.Name+"ColSize"
+		code := fmt.Sprintf("%sint %s;\n%sint *%s;\n", indent, size, indent, colSize)
+		code += fmt.Sprintf(
+			"%s%s = lc_scan_%s_matrix(&lc_p_, &%s, &%s);\n",
+			indent,
+			c.declare(2, cType, param.Name),
+			ioName,
+			size,
+			colSize,
+		)
+		return code, []string{param.Name, size, colSize}
+	}
+}
+
+// generatePrintCode returns the code to print a value of type t to `lc_out_`, sizes are named after the value.
+func (c cLang) generatePrintCode(t string, n string, size string, colSize string, indent string) string {
+	dimCnt, _, ioName := c.getCTypeName(t)
+	switch dimCnt {
+	case 0:
+		return fmt.Sprintf("%slc_print_%s(&lc_out_, %s);\n", indent, ioName, n)
+	case 1:
+		return fmt.Sprintf("%slc_print_%s_array(&lc_out_, %s, %s);\n", indent, ioName, n, size)
+	default:
+		return fmt.Sprintf("%slc_print_%s_matrix(&lc_out_, %s, %s, %s);\n", indent, ioName, n, size, colSize)
+	}
+}
+
+// generateCallCode returns the code to call a function and print its result to `lc_out_`.
+// Array results are returned through returnSize and returnColumnSizes.
+func (c cLang) generateCallCode(fn string, args []string, returnType string, indent string) string {
+	if returnType == "" || returnType == "void" {
+		return fmt.Sprintf(
+			"%s%s(%s);\n%slc_buf_puts(&lc_out_, \"null\");\n",
+			indent,
+			fn,
+			strings.Join(args, ", "),
+			indent,
+		)
+	}
+	dimCnt, cType, _ := c.getCTypeName(returnType)
+	var code string
+	switch dimCnt {
+	case 0:
+	case 1:
+		code += fmt.Sprintf("%sint returnSize;\n", indent)
+		args = append(args, "&returnSize")
+	default:
+		code += fmt.Sprintf("%sint returnSize;\n%sint *returnColumnSizes;\n", indent, indent)
+		args = append(args, "&returnSize", "&returnColumnSizes")
+	}
+	code += fmt.Sprintf(
+		"%s%s = %s(%s);\n",
+		indent,
+		c.declare(dimCnt, cType, cReturnName),
+		fn,
+		strings.Join(args, ", "),
+	)
+	code += c.generatePrintCode(returnType, cReturnName, "returnSize", "returnColumnSizes", indent)
+	return code
+}
+
+// The locals of the test harness are prefixed with lc_ and suffixed with _, so they never collide with
+// the parameters of the question, e.g. `p` of isSameTree, which are declared in the same scope.
+const (
+	cReturnName = "lc_res_"
+	cObjectName = "lc_obj_"
+)
+
+const cMainTemplate = `int main(void) {
+	lc_buf lc_out_ = {0};
+	const char *lc_p_;
+
+%s
+	printf("\n%s %%s\n", lc_out_.data);
+	return 0;
+}`
+
+func (c cLang) generateNormalTestCode(q *leetcode.QuestionData) (string, error) {
+	code := ""
+	var args []string
+	for _, param := range q.MetaData.Params {
+		scanCode, paramArgs := c.generateScanCode(param, "\t")
+		code += "\tlc_p_ = lc_read_line();\n" + scanCode
+		args = append(args, paramArgs...)
+	}
+	code += "\n"
+	if q.MetaData.Return != nil && q.MetaData.Return.Type != "void" {
+		code += c.generateCallCode(q.MetaData.Name, args, q.MetaData.Return.Type, "\t")
+	} else {
+		code += fmt.Sprintf("\t%s(%s);\n", q.MetaData.Name, strings.Join(args, ", "))
+		if q.MetaData.Output != nil {
+			param := q.MetaData.Params[q.MetaData.Output.ParamIndex]
+			code += c.generatePrintCode(param.Type, param.Name, param.Name+"Size", param.Name+"ColSize", "\t")
+		} else {
+			code += "\tlc_buf_puts(&lc_out_, \"null\");\n"
+		}
+	}
+
+	testContent := fmt.Sprintf(cMainTemplate, code, testCaseOutputMark)
+	if q.MetaData.Manual {
+		testContent = fmt.Sprintf("// %s\n%s", manualWarning, testContent)
+	}
+	return testContent, nil
+}
+
+// toCFuncName returns the function name of a system design method, e.g. LRUCache.get -> lRUCacheGet.
+func toCFuncName(className string, method string) string {
+	return strings.ToLower(className[:1]) + className[1:] + strings.ToUpper(method[:1]) + method[1:]
+}
+
+func (c cLang) generateParamsScanCode(params []leetcode.MetaDataParam, indent string) (string, []string) {
+	code := fmt.Sprintf("%slc_expect(&lc_p_, '[');\n", indent)
+	var args []string
+	for i, param := range params {
+		if i > 0 {
+			code += fmt.Sprintf("%slc_expect(&lc_p_, ',');\n", indent)
+		}
+		scanCode, paramArgs := c.generateScanCode(param, indent)
+		code += scanCode
+		args = append(args, paramArgs...)
+	}
+	code += fmt.Sprintf("%slc_expect(&lc_p_, ']');\n", indent)
+	return code, args
+}
+
+func (c cLang) generateSystemDesignTestCode(q *leetcode.QuestionData) (string, error) {
+	className := q.MetaData.ClassName
+	code := "\tlc_p_ = lc_read_line();\n\tint lc_ops_size_;\n"
+	code += "\tchar **lc_ops_ = lc_scan_string_array(&lc_p_, &lc_ops_size_);\n\n"
+	code += "\tlc_p_ = lc_read_line();\n\tlc_expect(&lc_p_, '[');\n"
+	scanCode, args := c.generateParamsScanCode(q.MetaData.Constructor.Params, "\t")
+	code += scanCode
+	code += fmt.Sprintf(
+		"\t%s *%s = %s(%s);\n",
+		className,
+		cObjectName,
+		toCFuncName(className, "create"),
+		strings.Join(args, ", "),
+	)
+	code += "\tlc_buf_puts(&lc_out_, \"[null\");\n\n"
+
+	code += "\tfor (int lc_i_ = 1; lc_i_ < lc_ops_size_; lc_i_++) {\n"
+	code += "\t\tlc_expect(&lc_p_, ',');\n\t\tlc_buf_putc(&lc_out_, ',');\n"
+	for i, method := range q.MetaData.Methods {
+		cond := "if"
+		if i > 0 {
+			cond = " else if"
+		}
+		if i == 0 {
+			code += "\t\t"
+		}
+		code += fmt.Sprintf("%s (strcmp(lc_ops_[lc_i_], \"%s\") == 0) {\n", cond, method.Name)
+		scanCode, args := c.generateParamsScanCode(method.Params, "\t\t\t")
+		code += scanCode
+		code += c.generateCallCode(
+			toCFuncName(className, method.Name),
+			append([]string{cObjectName}, args...),
+			method.Return.Type,
+			"\t\t\t",
+		)
+		code += "\t\t}"
+	}
+	code += "\n\t}\n"
+	code += fmt.Sprintf("\tlc_expect(&lc_p_, ']');\n\t%s(%s);\n", toCFuncName(className, "free"), cObjectName)
+	code += "\tlc_buf_putc(&lc_out_, ']');\n"
+
+	testContent := fmt.Sprintf(cMainTemplate, code, testCaseOutputMark)
+	if q.MetaData.Manual {
+		testContent = fmt.Sprintf("// %s\n%s", manualWarning, testContent)
+	}
+	return testContent, nil
+}
+
+func (c cLang) generateTestContent(q *leetcode.QuestionData) (string, error) {
+	if q.MetaData.SystemDesign {
+		return c.generateSystemDesignTestCode(q)
+	}
+	return c.generateNormalTestCode(q)
+}
+
+func (c cLang) generateCodeFile(
+	q *leetcode.QuestionData,
+	filename string,
+	blocks []config.Block,
+	modifiers []ModifierFunc,
+	separateDescriptionFile bool,
+) (
+	FileOutput,
+	error,
+) {
+	codeHeader := fmt.Sprintf("#include \"%s\"\n", cUtils.HeaderName)
+	testContent, err := c.generateTestContent(q)
+	if err != nil {
+		return FileOutput{}, err
+	}
+	blocks = append(
+		[]config.Block{
+			{
+				Name:     beforeBeforeMarker,
+				Template: codeHeader,
+			},
+			{
+				Name:     afterAfterMarker,
+				Template: testContent,
+			},
+		},
+		blocks...,
+	)
+	content, err := c.generateCodeContent(
+		q,
+		blocks,
+		modifiers,
+		separateDescriptionFile,
+	)
+	if err != nil {
+		return FileOutput{}, err
+	}
+	return FileOutput{
+		Filename: filename,
+		Content:  content,
+		Type:     CodeFile | TestFile,
+	}, nil
+}
+
+func (c cLang) buildLocalTest(q *leetcode.QuestionData, outDir string) (*GenerateResult, caseRunner, error) {
+	genResult, execFile, err := c.build(q, outDir, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	return genResult, newCommandRunner(genResult, []string{execFile}), nil
+}
+
+func (c cLang) RunLocalTest(q *leetcode.QuestionData, outDir string, targetCase string) (bool, error) {
+	return runLocalTest(c, q, outDir, targetCase)
+}
+
+func (c cLang) BuildDebug(q *leetcode.QuestionData, outDir string) (*GenerateResult, *debugTarget, error) {
+	genResult, execFile, err := c.build(q, outDir, true)
+	if err != nil {
+		return nil, nil, err
+	}
+	return genResult, &debugTarget{kind: debugNative, program: execFile, dir: outDir}, nil
+}
+
+// build compiles the test program and returns the path of the executable. A debug build replaces
+// the optimization flags of `CFLAGS` with `-O0 -g`.
+func (c cLang) build(q *leetcode.QuestionData, outDir string, debug bool) (*GenerateResult, string, error) {
+	genResult, err := c.GeneratePaths(q)
+	if err != nil {
+		return nil, "", fmt.Errorf("generate paths failed: %w", err)
+	}
+	genResult.SetOutDir(outDir)
+
+	testFile := genResult.GetFile(TestFile).GetPath()
+	if !utils.IsExist(testFile) {
+		return nil, "", fmt.Errorf("file %s not found", utils.RelToCwd(testFile))
+	}
+	profile := ""
+	if debug {
+		profile = "debug"
+	}
+	execFile, err := getTempBinFile(q, c, profile)
+	if err != nil {
+		return nil, "", fmt.Errorf("generate temporary binary file path failed: %w", err)
+	}
+
+	cfg := config.Get()
+	compilerFlags, _ := shlex.Split(cfg.Code.C.CFLAGS)
+	if debug {
+		compilerFlags = debugFlags(compilerFlags)
+	}
+	args := []string{cfg.Code.C.CC}
+	args = append(args, compilerFlags...)
+	args = append(args, "-I", outDir, "-o", execFile, testFile, "-lm")
+
+	err = buildTest(q, genResult, args)
+	if err != nil {
+		return nil, "", fmt.Errorf("compilation failed: %w", err)
+	}
+	return genResult, execFile, nil
+}
+
+func (c cLang) Generate(q *leetcode.QuestionData) (*GenerateResult, error) {
+	filenameTmpl := getFilenameTemplate(q, c)
+	baseFilename, err := q.GetFormattedFilename(c.slug, filenameTmpl)
+	if err != nil {
+		return nil, err
+	}
+	genResult := &GenerateResult{
+		Question: q,
+		Lang:     c,
+		SubDir:   baseFilename,
+	}
+
+	separateDescriptionFile := separateDescriptionFile(c)
+	blocks := getBlocks(c)
+	modifiers, err := getModifiers(c, builtinModifiers)
+	if err != nil {
+		return nil, err
+	}
+	codeFile, err := c.generateCodeFile(q, "solution.c", blocks, modifiers, separateDescriptionFile)
+	if err != nil {
+		return nil, err
+	}
+	testcaseFile, err := c.generateTestCasesFile(q, "testcases.txt")
+	if err != nil {
+		return nil, err
+	}
+	genResult.AddFile(codeFile)
+	genResult.AddFile(testcaseFile)
+
+	if separateDescriptionFile {
+		docFile, err := c.generateDescriptionFile(q, "question.md")
+		if err != nil {
+			return nil, err
+		}
+		genResult.AddFile(docFile)
+	}
+
+	return genResult, nil
+}
+
+func (c cLang) GeneratePaths(q *leetcode.QuestionData) (*GenerateResult, error) {
+	filenameTmpl := getFilenameTemplate(q, c)
+	baseFilename, err := q.GetFormattedFilename(c.slug, filenameTmpl)
+	if err != nil {
+		return nil, err
+	}
+	genResult := &GenerateResult{
+		SubDir:   baseFilename,
+		Question: q,
+		Lang:     c,
+	}
+	genResult.AddFile(
+		FileOutput{
+			Filename: "solution.c",
+			Type:     CodeFile | TestFile,
+		},
+	)
+	genResult.AddFile(
+		FileOutput{
+			Filename: "testcases.txt",
+			Type:     TestCasesFile,
+		},
+	)
+	if separateDescriptionFile(c) {
+		genResult.AddFile(
+			FileOutput{
+				Filename: "question.md",
+				Type:     DocFile,
+			},
+		)
+	}
+	return genResult, nil
+}
@@ -0,0 +1,53 @@
+package lang
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/j178/leetgo/leetcode"
+	cUtils "github.com/j178/leetgo/testutils/c"
+)
+
+func TestCTestCodeParamNames(t *testing.T) {
+	// The parameters are named like the locals of the harness, see isSameTree and isMatch.
+	q := &leetcode.QuestionData{
+		TitleSlug: "same-tree",
+		MetaData: leetcode.MetaData{
+			Name: "isSameTree",
+			Params: []leetcode.MetaDataParam{
+				{Name: "p", Type: "TreeNode"},
+				{Name: "out", Type: "string"},
+			},
+			Return: &leetcode.MetaDataReturn{Type: "boolean"},
+		},
+	}
+	code, err := cLang{}.generateTestContent(q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(code, "struct TreeNode *p = lc_scan_tree(&lc_p_);") {
+		t.Errorf("parameter p is not scanned with the harness pointer:\n%s", code)
+	}
+
+	gcc, err := exec.LookPath("gcc")
+	if err != nil {
+		t.Skip("gcc not found")
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, cUtils.HeaderName), cUtils.HeaderContent, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	src := "#include \"LC_IO.h\"\n" +
+		"bool isSameTree(struct TreeNode *p, char *out) { return p == NULL && out != NULL; }\n" + code
+	file := filepath.Join(dir, "solution.c")
+	if err := os.WriteFile(file, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	out, err := exec.Command(gcc, "-fsyntax-only", file).CombinedOutput()
+	if err != nil {
+		t.Errorf("generated code doesn't compile: %v\n%s", err, out)
+	}
+}
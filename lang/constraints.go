@@ -0,0 +1,447 @@
+package lang
+
+import (
+	"fmt"
+	"html"
+	"math"
+	"math/rand/v2"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	strip "github.com/grokify/html-strip-tags-go"
+
+	"github.com/j178/leetgo/config"
+	"github.com/j178/leetgo/leetcode"
+	goutils "github.com/j178/leetgo/testutils/go"
+)
+
+// Random inputs of stress tests are kept small by default, small cases are much easier to debug.
+const (
+	defaultStressValueMin = -10
+	defaultStressValueMax = 10
+	defaultStressLenMax   = 8
+	defaultStressCharset  = "abc"
+)
+
+// paramConstraint is the resolved constraint of a parameter, used to generate random values of it.
+type paramConstraint struct {
+	valueMin, valueMax int64
+	lenMin, lenMax     int
+	innerMin, innerMax int
+	charset            string
+	distinct           bool
+}
+
+var (
+	constraintsHeaderRe = regexp.MustCompile(`Constraints:|提示：|提示:`)
+	constraintItemRe    = regexp.MustCompile(`(?s)<li>(.*?)</li>`)
+	supRe               = regexp.MustCompile(`(?s)<sup>(.*?)</sup>`)
+	comparisonRe        = regexp.MustCompile(`\s*(<=|<)\s*`)
+	aliasRe             = regexp.MustCompile(`^(\w+)\s*==\s*([\w\[\].]+)$`)
+	boundRe             = regexp.MustCompile(`^([+-])?(\d+)(?:\^(\d+))?(?:\*(\d+)(?:\^(\d+))?)?([+-]\d+)?$`)
+	nodeRangeRe         = regexp.MustCompile(`\[\s*([^,\]]+?)\s*,\s*([^,\]]+?)\s*]`)
+	wordRe              = regexp.MustCompile(`\w+`)
+)
+
+// parseBound parses a bound like `10^4`, `-2^31`, `2 * 10^5` or `2^31 - 1`.
+func parseBound(s string) (int64, bool) {
+	s = strings.NewReplacer(" ", "", "×", "*", "⋅", "*", "·", "*").Replace(s)
+	m := boundRe.FindStringSubmatch(s)
+	if m == nil {
+		return 0, false
+	}
+	num := func(s string) float64 {
+		v, _ := strconv.ParseFloat(s, 64)
+		return v
+	}
+	v := num(m[2])
+	if m[3] != "" {
+		v = math.Pow(v, num(m[3]))
+	}
+	if m[4] != "" {
+		f := num(m[4])
+		if m[5] != "" {
+			f = math.Pow(f, num(m[5]))
+		}
+		v *= f
+	}
+	if m[1] == "-" {
+		v = -v
+	}
+	if m[6] != "" {
+		v += num(m[6])
+	}
+	switch {
+	case v >= math.MaxInt64:
+		return math.MaxInt64, true
+	case v <= math.MinInt64:
+		return math.MinInt64, true
+	}
+	return int64(v), true
+}
+
+// parseConstraints seeds the constraints of the parameters from the "Constraints" section of the question content.
+// Bounds that can't be found are left open, e.g. a range of [1, MaxInt64] for `1 <= k <= nums.length`.
+func parseConstraints(q *leetcode.QuestionData) map[string]config.StressParam {
+	content := q.Content
+	if content == "" {
+		content = q.TranslatedContent
+	}
+	loc := constraintsHeaderRe.FindStringIndex(content)
+	if loc == nil {
+		return nil
+	}
+
+	params := map[string]string{}
+	for _, p := range q.MetaData.Params {
+		params[p.Name] = p.Type
+	}
+	result := map[string]config.StressParam{}
+	update := func(name string, f func(p *config.StressParam)) {
+		if _, ok := params[name]; !ok {
+			return
+		}
+		p := result[name]
+		f(&p)
+		result[name] = p
+	}
+	nodeParams := func() []string {
+		var names []string
+		for _, p := range q.MetaData.Params {
+			if p.Type == "TreeNode" || p.Type == "ListNode" {
+				names = append(names, p.Name)
+			}
+		}
+		return names
+	}
+	// Parameters mentioned in the text, or all the parameters if none is mentioned.
+	mentioned := func(text string) []string {
+		var names []string
+		for _, w := range wordRe.FindAllString(text, -1) {
+			if _, ok := params[w]; ok && !slices.Contains(names, w) {
+				names = append(names, w)
+			}
+		}
+		if len(names) == 0 {
+			for _, p := range q.MetaData.Params {
+				names = append(names, p.Name)
+			}
+		}
+		return names
+	}
+
+	aliases := map[string]string{}
+	for _, m := range constraintItemRe.FindAllStringSubmatch(content[loc[1]:], -1) {
+		text := supRe.ReplaceAllString(m[1], "^$1")
+		text = html.UnescapeString(strip.StripTags(text))
+		text = strings.NewReplacer("≤", "<=", "\u00a0", " ").Replace(text)
+		text = strings.TrimSpace(strings.TrimRight(strings.TrimSpace(text), ".。"))
+
+		if am := aliasRe.FindStringSubmatch(text); am != nil {
+			aliases[am[1]] = am[2]
+			continue
+		}
+		lower := strings.ToLower(text)
+		if strings.Contains(lower, "number of nodes") || strings.Contains(text, "节点") {
+			if rm := nodeRangeRe.FindStringSubmatch(text); rm != nil {
+				lo, ok1 := parseBound(rm[1])
+				hi, ok2 := parseBound(rm[2])
+				if ok1 && ok2 {
+					for _, name := range nodeParams() {
+						update(name, func(p *config.StressParam) { p.Len = []int{clampInt(lo), clampInt(hi)} })
+					}
+				}
+				continue
+			}
+		}
+		if charset := parseCharset(lower); charset != "" {
+			for _, name := range mentioned(text) {
+				if t := params[name]; strings.HasPrefix(t, "string") || strings.HasPrefix(t, "character") {
+					update(name, func(p *config.StressParam) { p.Charset = charset })
+				}
+			}
+			continue
+		}
+		if strings.Contains(lower, "unique") || strings.Contains(lower, "distinct") ||
+			strings.Contains(text, "互不相同") || strings.Contains(text, "各不相同") {
+			for _, name := range mentioned(text) {
+				update(name, func(p *config.StressParam) { p.Distinct = true })
+			}
+			continue
+		}
+
+		parts := comparisonRe.Split(text, -1)
+		ops := comparisonRe.FindAllStringSubmatch(text, -1)
+		var lo, hi int64 = math.MinInt64, math.MaxInt64
+		var targets string
+		switch len(parts) {
+		case 3:
+			targets = parts[1]
+			if v, ok := parseBound(parts[0]); ok {
+				lo = v
+				if ops[0][1] == "<" {
+					lo++
+				}
+			}
+			if v, ok := parseBound(parts[2]); ok {
+				hi = v
+				if ops[1][1] == "<" {
+					hi--
+				}
+			}
+		case 2:
+			if v, ok := parseBound(parts[0]); ok {
+				targets, lo = parts[1], v
+				if ops[0][1] == "<" {
+					lo++
+				}
+			} else if v, ok := parseBound(parts[1]); ok {
+				targets, hi = parts[0], v
+				if ops[0][1] == "<" {
+					hi--
+				}
+			}
+		}
+		if targets == "" {
+			continue
+		}
+		for _, target := range strings.Split(targets, ",") {
+			target = strings.TrimSpace(target)
+			if alias, ok := aliases[target]; ok {
+				target = alias
+			}
+			applyBound(target, lo, hi, update, nodeParams)
+		}
+	}
+	return result
+}
+
+// applyBound applies the bound to the parameter referred by the target, e.g. `nums.length`, `nums[i]`,
+// `words[i].length` or `Node.val`.
+func applyBound(
+	target string,
+	lo, hi int64,
+	update func(name string, f func(p *config.StressParam)),
+	nodeParams func() []string,
+) {
+	setRange := func(p *config.StressParam) { p.Range = []int64{lo, hi} }
+	if target == "Node.val" || target == "node.val" {
+		for _, name := range nodeParams() {
+			update(name, setRange)
+		}
+		return
+	}
+	name, rest, _ := strings.Cut(target, ".")
+	indexed := strings.Contains(name, "[")
+	name, _, _ = strings.Cut(name, "[")
+	switch {
+	case rest == "length" && indexed:
+		update(name, func(p *config.StressParam) { p.InnerLen = []int{clampInt(lo), clampInt(hi)} })
+	case rest == "length":
+		update(name, func(p *config.StressParam) { p.Len = []int{clampInt(lo), clampInt(hi)} })
+	case rest == "":
+		update(name, setRange)
+	}
+}
+
+func clampInt(v int64) int {
+	return int(max(min(v, math.MaxInt32), 0))
+}
+
+// parseCharset returns the characters described by a constraint like "s consists of lowercase English letters".
+func parseCharset(text string) string {
+	if !strings.Contains(text, "consist") && !strings.Contains(text, "only") && !strings.Contains(text, "组成") &&
+		!strings.Contains(text, "仅") && !strings.Contains(text, "只") {
+		return ""
+	}
+	var charset string
+	if strings.Contains(text, "lowercase") || strings.Contains(text, "小写") {
+		charset += "abcdefghijklmnopqrstuvwxyz"
+	}
+	if strings.Contains(text, "uppercase") || strings.Contains(text, "大写") {
+		charset += "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	}
+	if strings.Contains(text, "digit") || strings.Contains(text, "数字") {
+		charset += "0123456789"
+	}
+	return charset
+}
+
+// narrowRange narrows the range to be near the default range, a huge range is useless for finding small cases.
+func narrowRange(lo, hi, defaultLo, defaultHi int64) (int64, int64) {
+	span := defaultHi - defaultLo
+	l, h := max(lo, defaultLo), min(hi, defaultHi)
+	switch {
+	case l <= h:
+		return l, h
+	case lo > defaultHi:
+		return lo, min(hi, lo+span)
+	default:
+		return max(lo, hi-span), hi
+	}
+}
+
+// resolveConstraint resolves the constraint of the parameter, the configured constraints are used as is,
+// the ones seeded from the question are narrowed to keep the cases small.
+func resolveConstraint(configured, seeded config.StressParam) paramConstraint {
+	c := paramConstraint{
+		valueMin: defaultStressValueMin,
+		valueMax: defaultStressValueMax,
+		lenMin:   1,
+		lenMax:   defaultStressLenMax,
+		innerMin: 1,
+		innerMax: defaultStressLenMax,
+		charset:  defaultStressCharset,
+		distinct: configured.Distinct || seeded.Distinct,
+	}
+	// Keep the minimum length, empty arrays and strings are common edge cases.
+	narrowLen := func(r []int) (int, int) {
+		return r[0], min(r[1], max(r[0], 1)+defaultStressLenMax-1)
+	}
+
+	switch {
+	case configured.Range != nil:
+		c.valueMin, c.valueMax = configured.Range[0], configured.Range[1]
+	case seeded.Range != nil:
+		c.valueMin, c.valueMax = narrowRange(seeded.Range[0], seeded.Range[1], c.valueMin, c.valueMax)
+	}
+	switch {
+	case configured.Len != nil:
+		c.lenMin, c.lenMax = configured.Len[0], configured.Len[1]
+	case seeded.Len != nil:
+		c.lenMin, c.lenMax = narrowLen(seeded.Len)
+	}
+	switch {
+	case configured.InnerLen != nil:
+		c.innerMin, c.innerMax = configured.InnerLen[0], configured.InnerLen[1]
+	case seeded.InnerLen != nil:
+		c.innerMin, c.innerMax = narrowLen(seeded.InnerLen)
+	}
+	switch {
+	case configured.Charset != "":
+		c.charset = configured.Charset
+	case seeded.Charset != "":
+		// A few characters make collisions likely, which is where bugs usually hide.
+		c.charset = seeded.Charset[:min(len(seeded.Charset), len(defaultStressCharset))]
+	}
+	return c
+}
+
+// inputGenerator generates random inputs from the types of the parameters.
+type inputGenerator struct {
+	rnd *rand.Rand
+	// scale grows from 0 to 1 along the rounds, so the earlier cases are smaller.
+	scale float64
+}
+
+func (g *inputGenerator) intn(lo, hi int64) int64 {
+	if hi <= lo {
+		return lo
+	}
+	return lo + g.rnd.Int64N(hi-lo+1)
+}
+
+func (g *inputGenerator) length(lo, hi int) int {
+	hi = lo + int(math.Ceil(float64(hi-lo)*g.scale))
+	return int(g.intn(int64(lo), int64(hi)))
+}
+
+func (g *inputGenerator) str(c paramConstraint, n int) string {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = c.charset[g.rnd.IntN(len(c.charset))]
+	}
+	return string(b)
+}
+
+// generate generates a serialized random value of the LeetCode type. n is the length of arrays and strings,
+// -1 means a random one.
+func (g *inputGenerator) generate(tp string, c paramConstraint, level int, n int) (string, error) {
+	if n < 0 {
+		if level == 0 {
+			n = g.length(c.lenMin, c.lenMax)
+		} else {
+			n = g.length(c.innerMin, c.innerMax)
+		}
+	}
+	switch tp {
+	case "integer", "long":
+		return strconv.FormatInt(g.intn(c.valueMin, c.valueMax), 10), nil
+	case "double":
+		v := float64(c.valueMin) + g.rnd.Float64()*float64(c.valueMax-c.valueMin)
+		return strconv.FormatFloat(v, 'f', 5, 64), nil
+	case "boolean":
+		return strconv.FormatBool(g.rnd.IntN(2) == 1), nil
+	case "character":
+		return strconv.Quote(g.str(c, 1)), nil
+	case "string":
+		return strconv.Quote(g.str(c, n)), nil
+	case "ListNode":
+		var head *goutils.ListNode
+		for range n {
+			head = &goutils.ListNode{Val: int(g.intn(c.valueMin, c.valueMax)), Next: head}
+		}
+		return head.String(), nil
+	case "TreeNode":
+		var root *goutils.TreeNode
+		for range n {
+			node := &goutils.TreeNode{Val: int(g.intn(c.valueMin, c.valueMax))}
+			p := &root
+			for *p != nil {
+				if g.rnd.IntN(2) == 0 {
+					p = &(*p).Left
+				} else {
+					p = &(*p).Right
+				}
+			}
+			*p = node
+		}
+		return root.String(), nil
+	}
+
+	elem, ok := strings.CutSuffix(tp, "[]")
+	if !ok {
+		return "", fmt.Errorf("unsupported type: %s", tp)
+	}
+	if c.distinct && (elem == "integer" || elem == "long") {
+		n = int(min(int64(n), c.valueMax-c.valueMin+1))
+	}
+	// Nested arrays are rectangular, like matrices and grids.
+	innerLen := -1
+	if strings.HasSuffix(elem, "[]") {
+		innerLen = g.length(c.innerMin, c.innerMax)
+	}
+	elems := make([]string, 0, n)
+	for len(elems) < n {
+		e, err := g.generate(elem, c, level+1, innerLen)
+		if err != nil {
+			return "", err
+		}
+		if c.distinct && slices.Contains(elems, e) {
+			// Give up the distinctness of this array when the domain is too small.
+			if g.rnd.IntN(100) != 0 {
+				continue
+			}
+		}
+		elems = append(elems, e)
+	}
+	return "[" + strings.Join(elems, ",") + "]", nil
+}
+
+// generateInput generates a random input of the question.
+func (g *inputGenerator) generateInput(q *leetcode.QuestionData, constraints map[string]paramConstraint) (
+	[]string,
+	error,
+) {
+	input := make([]string, 0, len(q.MetaData.Params))
+	for _, p := range q.MetaData.Params {
+		v, err := g.generate(p.Type, constraints[p.Name], 0, -1)
+		if err != nil {
+			return nil, fmt.Errorf("param %s: %w", p.Name, err)
+		}
+		input = append(input, v)
+	}
+	return input, nil
+}
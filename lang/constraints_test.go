@@ -0,0 +1,74 @@
+package lang
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/j178/leetgo/config"
+	"github.com/j178/leetgo/leetcode"
+)
+
+func TestParseBound(t *testing.T) {
+	tests := map[string]int64{
+		"0":           0,
+		"10^4":        10000,
+		"-10^9":       -1000000000,
+		"2 * 10^5":    200000,
+		"2^31 - 1":    math.MaxInt32,
+		"-2^31":       math.MinInt32,
+		"3 × 10^4":    30000,
+		"10^20":       math.MaxInt64,
+		"nums.length": 0,
+	}
+	for s, want := range tests {
+		got, ok := parseBound(s)
+		if ok != (s != "nums.length") || got != want {
+			t.Errorf("parseBound(%q) = %d, %v, want %d", s, got, ok, want)
+		}
+	}
+}
+
+func TestParseConstraints(t *testing.T) {
+	q := &leetcode.QuestionData{
+		Content: `<p><strong>Constraints:</strong></p>
+<ul>
+	<li><code>n == nums.length</code></li>
+	<li><code>1 &lt;= n &lt;= 10<sup>4</sup></code></li>
+	<li><code>-10<sup>9</sup> &lt;= nums[i] &lt;= 10<sup>9</sup></code></li>
+	<li><code>1 &lt;= k &lt;= nums.length</code></li>
+	<li><code>1 &lt;= words[i].length &lt;= 20</code></li>
+	<li><code>words[i]</code> consists of lowercase English letters.</li>
+	<li>All the integers of <code>nums</code> are <strong>unique</strong>.</li>
+	<li>The number of nodes in the tree is in the range <code>[0, 100]</code>.</li>
+	<li><code>-100 &lt;= Node.val &lt;= 100</code></li>
+</ul>`,
+		MetaData: leetcode.MetaData{
+			Params: []leetcode.MetaDataParam{
+				{Name: "nums", Type: "integer[]"},
+				{Name: "k", Type: "integer"},
+				{Name: "words", Type: "string[]"},
+				{Name: "root", Type: "TreeNode"},
+			},
+		},
+	}
+	want := map[string]config.StressParam{
+		"nums":  {Len: []int{1, 10000}, Range: []int64{-1000000000, 1000000000}, Distinct: true},
+		"k":     {Range: []int64{1, math.MaxInt64}},
+		"words": {InnerLen: []int{1, 20}, Charset: "abcdefghijklmnopqrstuvwxyz"},
+		"root":  {Len: []int{0, 100}, Range: []int64{-100, 100}},
+	}
+	got := parseConstraints(q)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseConstraints() = %+v, want %+v", got, want)
+	}
+
+	c := resolveConstraint(config.StressParam{}, got["nums"])
+	if c.valueMin != -10 || c.valueMax != 10 || c.lenMin != 1 || c.lenMax != 8 || !c.distinct {
+		t.Errorf("resolveConstraint() = %+v", c)
+	}
+	c = resolveConstraint(config.StressParam{Range: []int64{0, 1}}, got["root"])
+	if c.valueMin != 0 || c.valueMax != 1 || c.lenMin != 0 || c.lenMax != 8 {
+		t.Errorf("resolveConstraint() = %+v", c)
+	}
+}
@@ -2,7 +2,9 @@ package lang
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/google/shlex"
@@ -317,34 +319,97 @@ using namespace std;
 	}, nil
 }
 
+// sanitizerEnv turns off the leak detection of AddressSanitizer, the test program doesn't free the
+// inputs, and LeetCode doesn't check leaks either. Options set by the user are kept.
+var sanitizerEnv = map[string]string{
+	"ASAN_OPTIONS":  "detect_leaks=0",
+	"UBSAN_OPTIONS": "print_stacktrace=1",
+}
+
+func (c cpp) buildLocalTest(q *leetcode.QuestionData, outDir string) (*GenerateResult, caseRunner, error) {
+	profile := config.Get().Code.Cpp.Profile
+	genResult, execFile, err := c.build(q, outDir, profile, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	var env []string
+	if hasSanitizer(c.profileFlags(profile)) {
+		for k, v := range sanitizerEnv {
+			if _, ok := os.LookupEnv(k); !ok {
+				env = append(env, k+"="+v)
+			}
+		}
+	}
+	return genResult, newEnvCommandRunner(genResult, []string{execFile}, env), nil
+}
+
 func (c cpp) RunLocalTest(q *leetcode.QuestionData, outDir string, targetCase string) (bool, error) {
+	return runLocalTest(c, q, outDir, targetCase)
+}
+
+func (c cpp) BuildDebug(q *leetcode.QuestionData, outDir string) (*GenerateResult, *debugTarget, error) {
+	genResult, execFile, err := c.build(q, outDir, "debug", true)
+	if err != nil {
+		return nil, nil, err
+	}
+	return genResult, &debugTarget{kind: debugNative, program: execFile, dir: outDir}, nil
+}
+
+// profileFlags returns the compiler flags of the build profile, `cxxflags` followed by the flags of the profile.
+func (c cpp) profileFlags(profile string) []string {
+	cfg := config.Get()
+	flags, _ := shlex.Split(cfg.Code.Cpp.CXXFLAGS)
+	profileFlags, _ := shlex.Split(cfg.Code.Cpp.Profiles[profile])
+	return append(flags, profileFlags...)
+}
+
+// hasSanitizer reports whether the compiler flags enable any sanitizer.
+func hasSanitizer(flags []string) bool {
+	return slices.ContainsFunc(
+		flags, func(f string) bool {
+			return strings.HasPrefix(f, "-fsanitize=")
+		},
+	)
+}
+
+// build compiles the test program with the flags of the profile, and returns the path of the executable.
+// Each profile is built to its own file, so switching between them doesn't always rebuild. A debug build
+// replaces the optimization flags with `-O0 -g`.
+func (c cpp) build(
+	q *leetcode.QuestionData,
+	outDir string,
+	profile string,
+	debug bool,
+) (*GenerateResult, string, error) {
 	genResult, err := c.GeneratePaths(q)
 	if err != nil {
-		return false, fmt.Errorf("generate paths failed: %w", err)
+		return nil, "", fmt.Errorf("generate paths failed: %w", err)
 	}
 	genResult.SetOutDir(outDir)
 
 	testFile := genResult.GetFile(TestFile).GetPath()
 	if !utils.IsExist(testFile) {
-		return false, fmt.Errorf("file %s not found", utils.RelToCwd(testFile))
+		return nil, "", fmt.Errorf("file %s not found", utils.RelToCwd(testFile))
 	}
-	execFile, err := getTempBinFile(q, c)
+	execFile, err := getTempBinFile(q, c, profile)
 	if err != nil {
-		return false, fmt.Errorf("generate temporary binary file path failed: %w", err)
+		return nil, "", fmt.Errorf("generate temporary binary file path failed: %w", err)
 	}
 
 	cfg := config.Get()
-	compilerFlags, _ := shlex.Split(cfg.Code.Cpp.CXXFLAGS)
+	compilerFlags := c.profileFlags(profile)
+	if debug {
+		compilerFlags = debugFlags(compilerFlags)
+	}
 	args := []string{cfg.Code.Cpp.CXX}
 	args = append(args, compilerFlags...)
 	args = append(args, "-I", outDir, "-o", execFile, testFile)
 
 	err = buildTest(q, genResult, args)
 	if err != nil {
-		return false, fmt.Errorf("compilation failed: %w", err)
+		return nil, "", fmt.Errorf("compilation failed: %w", err)
 	}
-
-	return runTest(q, genResult, []string{execFile}, targetCase)
+	return genResult, execFile, nil
 }
 
 func (c cpp) Generate(q *leetcode.QuestionData) (*GenerateResult, error) {
@@ -0,0 +1,405 @@
+package lang
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/log"
+
+	"github.com/j178/leetgo/config"
+	"github.com/j178/leetgo/leetcode"
+	csharpUtils "github.com/j178/leetgo/testutils/csharp"
+	"github.com/j178/leetgo/utils"
+)
+
+const csharpPropsFile = csharpUtils.ProjectName + ".props"
+
+// The props file is shared by the utils project and the test project of each question,
+// so that they target the same framework.
+const csharpPropsTemplate = `<Project>
+  <PropertyGroup>
+    <TargetFramework>%s</TargetFramework>
+    <LangVersion>latest</LangVersion>
+    <Nullable>disable</Nullable>
+    <ImplicitUsings>disable</ImplicitUsings>
+  </PropertyGroup>
+</Project>
+`
+
+const csharpUtilsProject = `<Project Sdk="Microsoft.NET.Sdk">
+  <Import Project="` + csharpPropsFile + `" />
+</Project>
+`
+
+const csharpTestProjectTemplate = `<Project Sdk="Microsoft.NET.Sdk">
+  <Import Project="%[1]s" />
+  <PropertyGroup>
+    <OutputType>Exe</OutputType>
+    <AssemblyName>Solution</AssemblyName>
+    <EnableDefaultCompileItems>false</EnableDefaultCompileItems>
+  </PropertyGroup>
+  <ItemGroup>
+    <Compile Include="%[2]s" />
+    <Compile Include="%[3]s" />
+    <ProjectReference Include="%[4]s" />
+  </ItemGroup>
+</Project>
+`
+
+type csharp struct {
+	baseLang
+}
+
+// dotnetTargetFramework returns the target framework moniker of the installed dotnet SDK, e.g. net8.0.
+func dotnetTargetFramework() (string, error) {
+	out, err := exec.Command("dotnet", "--version").Output()
+	if err != nil {
+		return "", fmt.Errorf("get dotnet version failed: %w", err)
+	}
+	major, minor, ok := strings.Cut(strings.TrimSpace(string(out)), ".")
+	if !ok {
+		return "", fmt.Errorf("unexpected dotnet version: %s", out)
+	}
+	minor, _, _ = strings.Cut(minor, ".")
+	return fmt.Sprintf("net%s.%s", major, minor), nil
+}
+
+func (c csharp) InitWorkspace(outDir string) error {
+	if should, err := c.shouldInit(outDir); err != nil || !should {
+		return err
+	}
+
+	projectDir := filepath.Join(outDir, csharpUtils.ProjectName)
+	err := fs.WalkDir(
+		csharpUtils.Sources, csharpUtils.ProjectName, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			content, err := csharpUtils.Sources.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			return utils.WriteFile(filepath.Join(outDir, filepath.FromSlash(path)), content)
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	targetFramework, err := dotnetTargetFramework()
+	if err != nil {
+		return err
+	}
+	err = utils.WriteFile(
+		filepath.Join(projectDir, csharpPropsFile),
+		[]byte(fmt.Sprintf(csharpPropsTemplate, targetFramework)),
+	)
+	if err != nil {
+		return err
+	}
+	projectFile := filepath.Join(projectDir, csharpUtils.ProjectName+".csproj")
+	err = utils.WriteFile(projectFile, []byte(csharpUtilsProject))
+	if err != nil {
+		return err
+	}
+
+	// Build the utils project once, so that tests of each question only need to compile the solution.
+	cmd := exec.Command("dotnet", "build", projectFile, "-c", "Release", "--nologo")
+	log.Info("dotnet build", "cmd", cmd.String())
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Dir = projectDir
+	err = cmd.Run()
+	if err != nil {
+		return err
+	}
+
+	err = UpdateDep(c)
+	return err
+}
+
+func (c csharp) shouldInit(outDir string) (bool, error) {
+	projectDir := filepath.Join(outDir, csharpUtils.ProjectName)
+	if !utils.IsExist(filepath.Join(projectDir, csharpUtils.ProjectName+".csproj")) ||
+		!utils.IsExist(filepath.Join(projectDir, csharpPropsFile)) {
+		return true, nil
+	}
+	entries, err := csharpUtils.Sources.ReadDir(csharpUtils.ProjectName)
+	if err != nil {
+		return false, err
+	}
+	for _, entry := range entries {
+		if !utils.IsExist(filepath.Join(projectDir, entry.Name())) {
+			return true, nil
+		}
+	}
+
+	update, err := IsDepUpdateToDate(c)
+	if err != nil {
+		return false, err
+	}
+	return !update, nil
+}
+
+// See java.generateNormalTestCode for why the harness looks up the solution by reflection.
+func (c csharp) generateNormalTestCode(q *leetcode.QuestionData) string {
+	const template = `public static class Program
+{
+    public static void Main()
+    {
+        var stdin = Console.In;
+        var method = LeetCodeIO.GetMethod(typeof(Solution), "%s");
+        var args = LeetCodeIO.ReadArgs(stdin, method);
+%s
+        Console.WriteLine("\n%s " + LeetCodeIO.Serialize(ans, ansType));
+    }
+}
+`
+	var code string
+	if q.MetaData.Return != nil && q.MetaData.Return.Type != "void" {
+		code = "        var ans = LeetCodeIO.Invoke(new Solution(), method, args);\n"
+		code += "        var ansType = method.ReturnType;"
+	} else {
+		code = "        LeetCodeIO.Invoke(new Solution(), method, args);\n"
+		if q.MetaData.Output != nil {
+			code += fmt.Sprintf("        var ans = args[%d];\n", q.MetaData.Output.ParamIndex)
+			code += fmt.Sprintf("        var ansType = method.GetParameters()[%d].ParameterType;", q.MetaData.Output.ParamIndex)
+		} else {
+			code += "        object ans = null;\n"
+			code += "        var ansType = typeof(void);"
+		}
+	}
+	return fmt.Sprintf(template, q.MetaData.Name, code, testCaseOutputMark)
+}
+
+func (c csharp) generateSystemDesignTestCode(q *leetcode.QuestionData) string {
+	const template = `public static class Program
+{
+    public static void Main()
+    {
+        var stdin = Console.In;
+        var ops = LeetCodeIO.SplitArray(LeetCodeIO.ReadLine(stdin));
+        var opsParams = LeetCodeIO.SplitArray(LeetCodeIO.ReadLine(stdin));
+        var output = new List<string> { "null" };
+
+        var constructor = LeetCodeIO.GetConstructor(typeof(%[1]s));
+        var constructorArgs = LeetCodeIO.DeserializeArgs(constructor, LeetCodeIO.SplitArray(opsParams[0]));
+        var obj = LeetCodeIO.NewInstance(constructor, constructorArgs);
+
+        for (var i = 1; i < ops.Count; i++)
+        {
+            var op = (string)LeetCodeIO.Deserialize(typeof(string), ops[i]);
+            var method = LeetCodeIO.GetMethod(typeof(%[1]s), op);
+            var args = LeetCodeIO.DeserializeArgs(method, LeetCodeIO.SplitArray(opsParams[i]));
+            var ans = LeetCodeIO.Invoke(obj, method, args);
+            output.Add(method.ReturnType == typeof(void) ? "null" : LeetCodeIO.Serialize(ans, method.ReturnType));
+        }
+
+        Console.WriteLine("\n%[2]s " + LeetCodeIO.JoinArray(output));
+    }
+}
+`
+	return fmt.Sprintf(template, q.MetaData.ClassName, testCaseOutputMark)
+}
+
+func (c csharp) generateTestFile(q *leetcode.QuestionData, filename string) (FileOutput, error) {
+	content := "using System;\nusing System.Collections.Generic;\nusing Leetgo;\n\n"
+	if q.MetaData.Manual {
+		content = fmt.Sprintf("// %s\n%s", manualWarning, content)
+	}
+	if q.MetaData.SystemDesign {
+		content += c.generateSystemDesignTestCode(q)
+	} else {
+		content += c.generateNormalTestCode(q)
+	}
+	return FileOutput{
+		Filename: filename,
+		Content:  content,
+		Type:     TestFile,
+	}, nil
+}
+
+func (c csharp) generateCodeFile(
+	q *leetcode.QuestionData,
+	filename string,
+	blocks []config.Block,
+	modifiers []ModifierFunc,
+	separateDescriptionFile bool,
+) (
+	FileOutput,
+	error,
+) {
+	const codeHeader = `using System;
+using System.Collections.Generic;
+using System.Linq;
+using System.Text;
+`
+	blocks = append(
+		[]config.Block{
+			{
+				Name:     beforeBeforeMarker,
+				Template: codeHeader,
+			},
+		},
+		blocks...,
+	)
+	content, err := c.generateCodeContent(
+		q,
+		blocks,
+		modifiers,
+		separateDescriptionFile,
+	)
+	if err != nil {
+		return FileOutput{}, err
+	}
+	return FileOutput{
+		Filename: filename,
+		Content:  content,
+		Type:     CodeFile,
+	}, nil
+}
+
+func (c csharp) buildLocalTest(q *leetcode.QuestionData, outDir string) (*GenerateResult, caseRunner, error) {
+	genResult, err := c.GeneratePaths(q)
+	if err != nil {
+		return nil, nil, fmt.Errorf("generate paths failed: %w", err)
+	}
+	genResult.SetOutDir(outDir)
+
+	codeFile, err := filepath.Abs(genResult.GetFile(CodeFile).GetPath())
+	if err != nil {
+		return nil, nil, err
+	}
+	testFile, err := filepath.Abs(genResult.GetFile(TestFile).GetPath())
+	if err != nil {
+		return nil, nil, err
+	}
+	if !utils.IsExist(testFile) {
+		return nil, nil, fmt.Errorf("file %s not found", utils.RelToCwd(testFile))
+	}
+	projectDir, err := getTempBinDir(q, c)
+	if err != nil {
+		return nil, nil, fmt.Errorf("generate temporary project directory failed: %w", err)
+	}
+
+	// The test project lives in the temp dir, so that build outputs don't pollute the question directory.
+	// dotnet build is incremental, it only recompiles when the solution changes.
+	utilsDir, err := filepath.Abs(filepath.Join(outDir, csharpUtils.ProjectName))
+	if err != nil {
+		return nil, nil, err
+	}
+	projectFile := filepath.Join(projectDir, "Solution.csproj")
+	project := fmt.Sprintf(
+		csharpTestProjectTemplate,
+		filepath.Join(utilsDir, csharpPropsFile),
+		codeFile,
+		testFile,
+		filepath.Join(utilsDir, csharpUtils.ProjectName+".csproj"),
+	)
+	err = utils.WriteFile(projectFile, []byte(project))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	binDir := filepath.Join(projectDir, "bin")
+	args := []string{"dotnet", "build", projectFile, "-c", "Release", "-o", binDir, "--nologo", "-v", "q"}
+	err = buildTest(q, genResult, args)
+	if err != nil {
+		return nil, nil, fmt.Errorf("build failed: %w", err)
+	}
+
+	return genResult, newCommandRunner(genResult, []string{"dotnet", filepath.Join(binDir, "Solution.dll")}), nil
+}
+
+func (c csharp) RunLocalTest(q *leetcode.QuestionData, outDir string, targetCase string) (bool, error) {
+	return runLocalTest(c, q, outDir, targetCase)
+}
+
+func (c csharp) Generate(q *leetcode.QuestionData) (*GenerateResult, error) {
+	filenameTmpl := getFilenameTemplate(q, c)
+	baseFilename, err := q.GetFormattedFilename(c.slug, filenameTmpl)
+	if err != nil {
+		return nil, err
+	}
+	genResult := &GenerateResult{
+		Question: q,
+		Lang:     c,
+		SubDir:   baseFilename,
+	}
+
+	separateDescriptionFile := separateDescriptionFile(c)
+	blocks := getBlocks(c)
+	modifiers, err := getModifiers(c, builtinModifiers)
+	if err != nil {
+		return nil, err
+	}
+	codeFile, err := c.generateCodeFile(q, "solution.cs", blocks, modifiers, separateDescriptionFile)
+	if err != nil {
+		return nil, err
+	}
+	testFile, err := c.generateTestFile(q, "Program.cs")
+	if err != nil {
+		return nil, err
+	}
+	testcaseFile, err := c.generateTestCasesFile(q, "testcases.txt")
+	if err != nil {
+		return nil, err
+	}
+	genResult.AddFile(codeFile)
+	genResult.AddFile(testFile)
+	genResult.AddFile(testcaseFile)
+
+	if separateDescriptionFile {
+		docFile, err := c.generateDescriptionFile(q, "question.md")
+		if err != nil {
+			return nil, err
+		}
+		genResult.AddFile(docFile)
+	}
+
+	return genResult, nil
+}
+
+func (c csharp) GeneratePaths(q *leetcode.QuestionData) (*GenerateResult, error) {
+	filenameTmpl := getFilenameTemplate(q, c)
+	baseFilename, err := q.GetFormattedFilename(c.slug, filenameTmpl)
+	if err != nil {
+		return nil, err
+	}
+	genResult := &GenerateResult{
+		SubDir:   baseFilename,
+		Question: q,
+		Lang:     c,
+	}
+	genResult.AddFile(
+		FileOutput{
+			Filename: "solution.cs",
+			Type:     CodeFile,
+		},
+	)
+	genResult.AddFile(
+		FileOutput{
+			Filename: "Program.cs",
+			Type:     TestFile,
+		},
+	)
+	genResult.AddFile(
+		FileOutput{
+			Filename: "testcases.txt",
+			Type:     TestCasesFile,
+		},
+	)
+	if separateDescriptionFile(c) {
+		genResult.AddFile(
+			FileOutput{
+				Filename: "question.md",
+				Type:     DocFile,
+			},
+		)
+	}
+	return genResult, nil
+}
@@ -0,0 +1,256 @@
+package lang
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	"github.com/goccy/go-json"
+
+	"github.com/j178/leetgo/config"
+	"github.com/j178/leetgo/leetcode"
+	"github.com/j178/leetgo/utils"
+)
+
+// Debuggable is an interface for languages whose test program can be run under a debugger.
+type Debuggable interface {
+	// BuildDebug builds the test program of the question with debug info, and returns how to debug it.
+	BuildDebug(q *leetcode.QuestionData, outDir string) (*GenerateResult, *debugTarget, error)
+}
+
+// Kinds of debug targets, they decide the debugger to use.
+const (
+	debugNative = "native"
+	debugGo     = "go"
+	debugPython = "python"
+)
+
+// debugTarget is a test program built for debugging.
+type debugTarget struct {
+	kind string
+	// program is the executable, or the interpreter for a script.
+	program string
+	args    []string
+	dir     string
+}
+
+// debugFlags replaces the optimization and debug info flags of a C/C++ compiler with the ones for debugging.
+func debugFlags(flags []string) []string {
+	flags = slices.DeleteFunc(
+		slices.Clone(flags), func(f string) bool {
+			return strings.HasPrefix(f, "-O") || strings.HasPrefix(f, "-g") || f == "-s"
+		},
+	)
+	return append(flags, "-O0", "-g")
+}
+
+// firstAvailable returns the first of the commands found in PATH.
+func firstAvailable(commands ...string) (string, error) {
+	for _, c := range commands {
+		if _, err := exec.LookPath(c); err == nil {
+			return c, nil
+		}
+	}
+	return "", fmt.Errorf("none of %s is found in PATH", strings.Join(commands, ", "))
+}
+
+// nativeDebuggers returns the debuggers of native programs, the preferred of the platform first.
+func nativeDebuggers(rust bool) []string {
+	debuggers := []string{"gdb", "lldb"}
+	if runtime.GOOS == "darwin" {
+		debuggers = []string{"lldb", "gdb"}
+	}
+	if rust {
+		// The wrappers shipped with Rust pretty print the standard types.
+		debuggers = []string{"rust-" + debuggers[0], "rust-" + debuggers[1], debuggers[0], debuggers[1]}
+	}
+	return debuggers
+}
+
+// command returns the command starting the debugger, with the input file wired to stdin of the test program.
+func (t *debugTarget) command(lang Lang, inputFile string) ([]string, error) {
+	switch t.kind {
+	case debugGo:
+		dlv, err := firstAvailable("dlv")
+		if err != nil {
+			return nil, fmt.Errorf("%w, install it by `go install github.com/go-delve/delve/cmd/dlv@latest`", err)
+		}
+		args := []string{dlv, "exec", t.program, "--wd", t.dir, "--redirect", "stdin:" + inputFile}
+		if len(t.args) > 0 {
+			args = append(append(args, "--"), t.args...)
+		}
+		return args, nil
+	case debugNative:
+		debugger, err := firstAvailable(nativeDebuggers(lang.Slug() == "rust")...)
+		if err != nil {
+			return nil, err
+		}
+		if strings.HasSuffix(debugger, "lldb") {
+			args := []string{debugger, "-o", "settings set target.input-path " + inputFile, "--", t.program}
+			return append(args, t.args...), nil
+		}
+		// gdb runs the program through a shell, which does the redirection.
+		runArgs := append(slices.Clone(t.args), "<", shellQuote(inputFile))
+		return []string{debugger, "-q", "-ex", "set args " + strings.Join(runArgs, " "), t.program}, nil
+	case debugPython:
+		// pdb reads commands from sys.stdin by default, so it's given the terminal explicitly, and the input
+		// of the case is opened as sys.stdin of the test program.
+		script := `import pdb, sys
+tty, sys.stdin = sys.stdin, open(sys.argv[1])
+path = sys.argv[2]
+sys.argv = sys.argv[2:]
+sys.path.insert(0, __import__("os").path.dirname(path))
+with open(path) as f:
+    code = compile(f.read(), path, "exec")
+debugger = pdb.Pdb(stdin=tty)
+debugger.use_rawinput = False
+debugger.run(code, {"__name__": "__main__", "__file__": path})`
+		args := []string{t.program, "-c", script, inputFile}
+		return append(args, t.args...), nil
+	default:
+		return nil, fmt.Errorf("unknown debug target: %s", t.kind)
+	}
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// DebugTestCase builds the test program for debugging, and starts a debugger on the case selected by caseExpr,
+// e.g. `2` or the name of the case. If writeLaunchConfig is true, a launch configuration of VS Code is written
+// instead.
+func DebugTestCase(q *leetcode.QuestionData, caseExpr string, writeLaunchConfig bool) error {
+	cfg := config.Get()
+	gen, err := GetGenerator(cfg.Code.Lang)
+	if err != nil {
+		return err
+	}
+	debuggable, ok := gen.(Debuggable)
+	if !ok {
+		return fmt.Errorf("debugging is not supported for %s", gen.Slug())
+	}
+	err = q.Fulfill()
+	if err != nil {
+		return fmt.Errorf("failed to get question data: %w", err)
+	}
+	outDir := getOutDir(q, gen)
+	if !utils.IsExist(outDir) {
+		return fmt.Errorf("no code generated for %s in language %s", q.TitleSlug, gen.Slug())
+	}
+
+	genResult, target, err := debuggable.BuildDebug(q, outDir)
+	if err != nil {
+		return err
+	}
+	c, err := selectDebugCase(q, genResult, caseExpr)
+	if err != nil {
+		return err
+	}
+	tmpDir := config.Get().TempDir()
+	if err := utils.CreateIfNotExists(tmpDir, true); err != nil {
+		return err
+	}
+	inputFile := filepath.Join(tmpDir, fmt.Sprintf("%s-%s.input", q.TitleSlug, gen.Slug()))
+	if err := utils.WriteFile(inputFile, []byte(c.InputString())); err != nil {
+		return err
+	}
+
+	if writeLaunchConfig {
+		return writeVSCodeLaunchConfig(q, target, inputFile)
+	}
+	args, err := target.command(gen, inputFile)
+	if err != nil {
+		return err
+	}
+	log.Info("starting debugger", "case", c.Label(), "cmd", strings.Join(args, " "))
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Dir = target.dir
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+func selectDebugCase(q *leetcode.QuestionData, genResult *GenerateResult, caseExpr string) (TestCase, error) {
+	testcaseFile := genResult.GetFile(TestCasesFile)
+	if testcaseFile == nil {
+		panic("no test cases file generated")
+	}
+	tc, err := ParseTestCases(q, testcaseFile)
+	if err != nil {
+		return TestCase{}, err
+	}
+	r, err := tc.SelectCases(caseExpr)
+	if err != nil {
+		return TestCase{}, err
+	}
+	var selected []TestCase
+	for _, c := range tc.Cases {
+		if r.Contains(c.No) {
+			selected = append(selected, c)
+		}
+	}
+	if len(selected) != 1 {
+		return TestCase{}, fmt.Errorf("%q should select exactly one case, got %d", caseExpr, len(selected))
+	}
+	return selected[0], nil
+}
+
+// writeVSCodeLaunchConfig adds a launch configuration of the CodeLLDB extension to `.vscode/launch.json`
+// of the project, replacing the one of the same question. Only native programs are supported, because
+// the debug adapters of the other languages can't redirect stdin.
+func writeVSCodeLaunchConfig(q *leetcode.QuestionData, target *debugTarget, inputFile string) error {
+	if target.kind != debugNative {
+		return errors.New("launch configuration is only supported for C, C++ and Rust")
+	}
+	name := "leetgo: " + q.TitleSlug
+	launch := map[string]any{
+		"name":    name,
+		"type":    "lldb",
+		"request": "launch",
+		"program": target.program,
+		"args":    target.args,
+		"cwd":     target.dir,
+		"stdio":   []any{inputFile, nil, nil},
+	}
+
+	file := filepath.Join(config.Get().ProjectRoot(), ".vscode", "launch.json")
+	content := map[string]any{"version": "0.2.0"}
+	if utils.IsExist(file) {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(data, &content); err != nil {
+			return fmt.Errorf("failed to parse %s, comments are not supported: %w", utils.RelToCwd(file), err)
+		}
+	}
+	configs, _ := content["configurations"].([]any)
+	configs = slices.DeleteFunc(
+		configs, func(c any) bool {
+			m, ok := c.(map[string]any)
+			return ok && m["name"] == name
+		},
+	)
+	content["configurations"] = append(configs, launch)
+
+	data, err := json.MarshalIndent(content, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := utils.WriteFile(file, append(data, '\n')); err != nil {
+		return err
+	}
+	log.Info(
+		"launch configuration written, start it from the Run and Debug view",
+		"file", utils.RelToCwd(file),
+		"name", name,
+	)
+	return nil
+}
@@ -11,9 +11,15 @@ import (
 
 // If client dependency needs to be updated, update this version number.
 var depVersions = map[string]int{
+	cGen.slug:       1,
 	cppGen.slug:     1,
+	csharpGen.slug:  1,
 	golangGen.slug:  3,
-	python3Gen.slug: 1,
+	javaGen.slug:    2,
+	jsGen.slug:      1,
+	kotlinGen.slug:  2,
+	tsGen.slug:      1,
+	python3Gen.slug: 2,
 	rustGen.slug:    1,
 }
 
@@ -202,6 +202,11 @@ func GetSolutionCode(q *leetcode.QuestionData) (string, error) {
 	if err != nil {
 		return "", errors.New("code file not found")
 	}
+	return getSolutionCodeFromFile(codeFile)
+}
+
+// getSolutionCodeFromFile extracts the solution code between the code markers of the file.
+func getSolutionCodeFromFile(codeFile *FileOutput) (string, error) {
 	code, err := codeFile.GetContent()
 	if err != nil {
 		return "", err
@@ -159,28 +159,57 @@ func (g golang) InitWorkspace(outDir string) error {
 	return err
 }
 
+func (g golang) buildLocalTest(q *leetcode.QuestionData, outDir string) (*GenerateResult, caseRunner, error) {
+	genResult, execFile, err := g.build(q, outDir, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	return genResult, newCommandRunner(genResult, []string{execFile}), nil
+}
+
 func (g golang) RunLocalTest(q *leetcode.QuestionData, outDir string, targetCase string) (bool, error) {
+	return runLocalTest(g, q, outDir, targetCase)
+}
+
+func (g golang) BuildDebug(q *leetcode.QuestionData, outDir string) (*GenerateResult, *debugTarget, error) {
+	genResult, execFile, err := g.build(q, outDir, true)
+	if err != nil {
+		return nil, nil, err
+	}
+	return genResult, &debugTarget{kind: debugGo, program: execFile, dir: outDir}, nil
+}
+
+// build builds the test program and returns the path of the executable. A debug build disables
+// optimizations and inlining, so that the debugger can follow the code.
+func (g golang) build(q *leetcode.QuestionData, outDir string, debug bool) (*GenerateResult, string, error) {
 	genResult, err := g.GeneratePaths(q)
 	if err != nil {
-		return false, fmt.Errorf("generate paths failed: %w", err)
+		return nil, "", fmt.Errorf("generate paths failed: %w", err)
 	}
 	genResult.SetOutDir(outDir)
 
 	testFile := genResult.GetFile(TestFile).GetPath()
 	if !utils.IsExist(testFile) {
-		return false, fmt.Errorf("file %s not found", utils.RelToCwd(testFile))
+		return nil, "", fmt.Errorf("file %s not found", utils.RelToCwd(testFile))
 	}
-	execFile, err := getTempBinFile(q, g)
+	profile := ""
+	if debug {
+		profile = "debug"
+	}
+	execFile, err := getTempBinFile(q, g, profile)
 	if err != nil {
-		return false, fmt.Errorf("get temp bin file failed: %w", err)
+		return nil, "", fmt.Errorf("get temp bin file failed: %w", err)
 	}
 
-	err = buildTest(q, genResult, []string{"go", "build", "-o", execFile, testFile})
+	args := []string{"go", "build", "-o", execFile, testFile}
+	if debug {
+		args = []string{"go", "build", "-gcflags=all=-N -l", "-o", execFile, testFile}
+	}
+	err = buildTest(q, genResult, args)
 	if err != nil {
-		return false, fmt.Errorf("build failed: %w", err)
+		return nil, "", fmt.Errorf("build failed: %w", err)
 	}
-
-	return runTest(q, genResult, []string{execFile}, targetCase)
+	return genResult, execFile, nil
 }
 
 // toGoType converts LeetCode type name to Go type name.
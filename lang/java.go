@@ -0,0 +1,284 @@
+package lang
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+
+	"github.com/j178/leetgo/config"
+	"github.com/j178/leetgo/leetcode"
+	javaUtils "github.com/j178/leetgo/testutils/java"
+	"github.com/j178/leetgo/utils"
+)
+
+type java struct {
+	baseLang
+}
+
+func (j java) InitWorkspace(outDir string) error {
+	if should, err := j.shouldInit(outDir); err != nil || !should {
+		return err
+	}
+
+	err := fs.WalkDir(
+		javaUtils.Sources, javaUtils.PackageName, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			content, err := javaUtils.Sources.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			return utils.WriteFile(filepath.Join(outDir, filepath.FromSlash(path)), content)
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	err = UpdateDep(j)
+	return err
+}
+
+func (j java) shouldInit(outDir string) (bool, error) {
+	entries, err := javaUtils.Sources.ReadDir(javaUtils.PackageName)
+	if err != nil {
+		return false, err
+	}
+	for _, entry := range entries {
+		if !utils.IsExist(filepath.Join(outDir, javaUtils.PackageName, entry.Name())) {
+			return true, nil
+		}
+	}
+
+	update, err := IsDepUpdateToDate(j)
+	if err != nil {
+		return false, err
+	}
+	return !update, nil
+}
+
+// The Java harness resolves parameter types by reflection, because the metadata can't tell
+// whether a method takes `int[]` or `List<Integer>`.
+func (j java) generateNormalTestCode(q *leetcode.QuestionData) (string, error) {
+	const template = `class Main {
+	public static void main(String[] args) throws Throwable {
+		BufferedReader stdin = new BufferedReader(new InputStreamReader(System.in));
+		java.lang.reflect.Method method = LeetCodeIO.getMethod(Solution.class, "%s", %d);
+		Object[] params = LeetCodeIO.readArgs(stdin, method);
+%s
+		System.out.println("\n%s " + LeetCodeIO.serialize(ans));
+	}
+}`
+	var code string
+	if q.MetaData.Return != nil && q.MetaData.Return.Type != "void" {
+		code = "\t\tObject ans = LeetCodeIO.invoke(new Solution(), method, params);"
+	} else {
+		code = "\t\tLeetCodeIO.invoke(new Solution(), method, params);\n"
+		if q.MetaData.Output != nil {
+			code += fmt.Sprintf("\t\tObject ans = params[%d];", q.MetaData.Output.ParamIndex)
+		} else {
+			code += "\t\tObject ans = null;"
+		}
+	}
+
+	testContent := fmt.Sprintf(template, q.MetaData.Name, len(q.MetaData.Params), code, testCaseOutputMark)
+	if q.MetaData.Manual {
+		testContent = fmt.Sprintf("// %s\n%s", manualWarning, testContent)
+	}
+	return testContent, nil
+}
+
+func (j java) generateSystemDesignTestCode(q *leetcode.QuestionData) (string, error) {
+	const template = `class Main {
+	public static void main(String[] args) throws Throwable {
+		BufferedReader stdin = new BufferedReader(new InputStreamReader(System.in));
+		List<String> ops = LeetCodeIO.splitArray(LeetCodeIO.readLine(stdin));
+		List<String> params = LeetCodeIO.splitArray(LeetCodeIO.readLine(stdin));
+		List<String> output = new ArrayList<>();
+		output.add("null");
+
+		java.lang.reflect.Constructor<?> constructor = LeetCodeIO.getConstructor(%[1]s.class);
+		Object[] constructorParams = LeetCodeIO.deserializeArgs(constructor, LeetCodeIO.splitArray(params.get(0)));
+		Object obj = LeetCodeIO.newInstance(constructor, constructorParams);
+
+		for (int i = 1; i < ops.size(); i++) {
+			String op = (String) LeetCodeIO.deserialize(String.class, ops.get(i));
+			List<String> opArgs = LeetCodeIO.splitArray(params.get(i));
+			java.lang.reflect.Method method = LeetCodeIO.getMethod(%[1]s.class, op, opArgs.size());
+			Object[] methodParams = LeetCodeIO.deserializeArgs(method, opArgs);
+			Object ans = LeetCodeIO.invoke(obj, method, methodParams);
+			output.add(method.getReturnType() == void.class ? "null" : LeetCodeIO.serialize(ans));
+		}
+
+		System.out.println("\n%[2]s " + LeetCodeIO.joinArray(output));
+	}
+}`
+	testContent := fmt.Sprintf(template, q.MetaData.ClassName, testCaseOutputMark)
+	if q.MetaData.Manual {
+		testContent = fmt.Sprintf("// %s\n%s", manualWarning, testContent)
+	}
+	return testContent, nil
+}
+
+func (j java) generateTestContent(q *leetcode.QuestionData) (string, error) {
+	if q.MetaData.SystemDesign {
+		return j.generateSystemDesignTestCode(q)
+	}
+	return j.generateNormalTestCode(q)
+}
+
+func (j java) generateCodeFile(
+	q *leetcode.QuestionData,
+	filename string,
+	blocks []config.Block,
+	modifiers []ModifierFunc,
+	separateDescriptionFile bool,
+) (
+	FileOutput,
+	error,
+) {
+	codeHeader := fmt.Sprintf(
+		`import java.io.*;
+import java.util.*;
+import java.util.stream.*;
+
+import %s.*;
+`, javaUtils.PackageName,
+	)
+	testContent, err := j.generateTestContent(q)
+	if err != nil {
+		return FileOutput{}, err
+	}
+	blocks = append(
+		[]config.Block{
+			{
+				Name:     beforeBeforeMarker,
+				Template: codeHeader,
+			},
+			{
+				Name:     afterAfterMarker,
+				Template: testContent,
+			},
+		},
+		blocks...,
+	)
+	content, err := j.generateCodeContent(
+		q,
+		blocks,
+		modifiers,
+		separateDescriptionFile,
+	)
+	if err != nil {
+		return FileOutput{}, err
+	}
+	return FileOutput{
+		Filename: filename,
+		Content:  content,
+		Type:     CodeFile | TestFile,
+	}, nil
+}
+
+func (j java) buildLocalTest(q *leetcode.QuestionData, outDir string) (*GenerateResult, caseRunner, error) {
+	genResult, err := j.GeneratePaths(q)
+	if err != nil {
+		return nil, nil, fmt.Errorf("generate paths failed: %w", err)
+	}
+	genResult.SetOutDir(outDir)
+
+	testFile := genResult.GetFile(TestFile).GetPath()
+	if !utils.IsExist(testFile) {
+		return nil, nil, fmt.Errorf("file %s not found", utils.RelToCwd(testFile))
+	}
+	classDir, err := getTempBinDir(q, j)
+	if err != nil {
+		return nil, nil, fmt.Errorf("generate temporary class directory failed: %w", err)
+	}
+
+	// The test utils live in `<outDir>/leetgo`, javac compiles them on demand through -sourcepath.
+	args := []string{"javac", "-encoding", "UTF-8", "-sourcepath", outDir, "-d", classDir, testFile}
+	err = buildTest(q, genResult, args)
+	if err != nil {
+		return nil, nil, fmt.Errorf("compilation failed: %w", err)
+	}
+
+	return genResult, newCommandRunner(genResult, []string{"java", "-cp", classDir, "Main"}), nil
+}
+
+func (j java) RunLocalTest(q *leetcode.QuestionData, outDir string, targetCase string) (bool, error) {
+	return runLocalTest(j, q, outDir, targetCase)
+}
+
+func (j java) Generate(q *leetcode.QuestionData) (*GenerateResult, error) {
+	filenameTmpl := getFilenameTemplate(q, j)
+	baseFilename, err := q.GetFormattedFilename(j.slug, filenameTmpl)
+	if err != nil {
+		return nil, err
+	}
+	genResult := &GenerateResult{
+		Question: q,
+		Lang:     j,
+		SubDir:   baseFilename,
+	}
+
+	separateDescriptionFile := separateDescriptionFile(j)
+	blocks := getBlocks(j)
+	modifiers, err := getModifiers(j, builtinModifiers)
+	if err != nil {
+		return nil, err
+	}
+	codeFile, err := j.generateCodeFile(q, "solution.java", blocks, modifiers, separateDescriptionFile)
+	if err != nil {
+		return nil, err
+	}
+	testcaseFile, err := j.generateTestCasesFile(q, "testcases.txt")
+	if err != nil {
+		return nil, err
+	}
+	genResult.AddFile(codeFile)
+	genResult.AddFile(testcaseFile)
+
+	if separateDescriptionFile {
+		docFile, err := j.generateDescriptionFile(q, "question.md")
+		if err != nil {
+			return nil, err
+		}
+		genResult.AddFile(docFile)
+	}
+
+	return genResult, nil
+}
+
+func (j java) GeneratePaths(q *leetcode.QuestionData) (*GenerateResult, error) {
+	filenameTmpl := getFilenameTemplate(q, j)
+	baseFilename, err := q.GetFormattedFilename(j.slug, filenameTmpl)
+	if err != nil {
+		return nil, err
+	}
+	genResult := &GenerateResult{
+		SubDir:   baseFilename,
+		Question: q,
+		Lang:     j,
+	}
+	genResult.AddFile(
+		FileOutput{
+			Filename: "solution.java",
+			Type:     CodeFile | TestFile,
+		},
+	)
+	genResult.AddFile(
+		FileOutput{
+			Filename: "testcases.txt",
+			Type:     TestCasesFile,
+		},
+	)
+	if separateDescriptionFile(j) {
+		genResult.AddFile(
+			FileOutput{
+				Filename: "question.md",
+				Type:     DocFile,
+			},
+		)
+	}
+	return genResult, nil
+}
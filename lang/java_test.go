@@ -0,0 +1,30 @@
+package lang
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/j178/leetgo/leetcode"
+)
+
+func TestJavaTestCodeMethodArity(t *testing.T) {
+	q := testQuestion()
+	q.MetaData.Name = "twoSum"
+
+	code, err := javaGen.generateTestContent(q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(code, `LeetCodeIO.getMethod(Solution.class, "twoSum", 2)`) {
+		t.Errorf("method is not looked up by its parameter count:\n%s", code)
+	}
+
+	q.MetaData = leetcode.MetaData{SystemDesign: true, ClassName: "LRUCache"}
+	code, err = javaGen.generateTestContent(q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(code, "LeetCodeIO.getMethod(LRUCache.class, op, opArgs.size())") {
+		t.Errorf("method is not looked up by its parameter count:\n%s", code)
+	}
+}
@@ -0,0 +1,441 @@
+package lang
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	"github.com/goccy/go-json"
+
+	"github.com/j178/leetgo/config"
+	"github.com/j178/leetgo/leetcode"
+	jsUtils "github.com/j178/leetgo/testutils/js"
+	"github.com/j178/leetgo/utils"
+)
+
+var tsDeps = []string{
+	"typescript@5.6.3",
+}
+
+// tscFlags are used both for the generated tsconfig.json and for the transpile step of local test.
+var tscFlags = map[string]any{
+	"target":          "es2022",
+	"module":          "commonjs",
+	"esModuleInterop": true,
+	"skipLibCheck":    true,
+}
+
+// javascript generates code for both JavaScript and TypeScript, TypeScript is transpiled to JavaScript before running.
+type javascript struct {
+	baseLang
+	typescript bool
+}
+
+func (j javascript) shouldInit(outDir string) (bool, error) {
+	if !utils.IsExist(filepath.Join(outDir, "package.json")) {
+		return true, nil
+	}
+	if !utils.IsExist(filepath.Join(outDir, "node_modules", jsUtils.PackageName)) {
+		return true, nil
+	}
+	update, err := IsDepUpdateToDate(j)
+	if err != nil {
+		return false, err
+	}
+	return !update, nil
+}
+
+func (j javascript) InitWorkspace(outDir string) error {
+	if should, err := j.shouldInit(outDir); err != nil || !should {
+		return err
+	}
+
+	err := fs.WalkDir(
+		jsUtils.Files, ".", func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			content, err := jsUtils.Files.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			return utils.WriteFile(filepath.Join(outDir, jsUtils.PackageName, filepath.FromSlash(path)), content)
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	err = j.writePackageJson(outDir)
+	if err != nil {
+		return err
+	}
+	if j.typescript {
+		err = writeJson(filepath.Join(outDir, "tsconfig.json"), map[string]any{"compilerOptions": tscFlags})
+		if err != nil {
+			return err
+		}
+	}
+
+	cmd := exec.Command("npm", "install", "--no-audit", "--no-fund")
+	if j.typescript {
+		cmd.Args = append(cmd.Args, "--save-dev")
+		cmd.Args = append(cmd.Args, tsDeps...)
+	}
+	log.Info("npm install", "cmd", cmd.String())
+	cmd.Dir = outDir
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	err = cmd.Run()
+	if err != nil {
+		return err
+	}
+
+	err = UpdateDep(j)
+	return err
+}
+
+// writePackageJson adds the bundled package as a local dependency, other fields of an existing package.json are kept.
+func (j javascript) writePackageJson(outDir string) error {
+	pkgPath := filepath.Join(outDir, "package.json")
+	pkg := map[string]any{
+		"name":    "leetcode-solutions",
+		"private": true,
+	}
+	data, err := os.ReadFile(pkgPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, &pkg); err != nil {
+			return fmt.Errorf("invalid %s: %w", utils.RelToCwd(pkgPath), err)
+		}
+	}
+	deps, _ := pkg["dependencies"].(map[string]any)
+	if deps == nil {
+		deps = map[string]any{}
+	}
+	deps[jsUtils.PackageName] = "file:./" + jsUtils.PackageName
+	pkg["dependencies"] = deps
+	return writeJson(pkgPath, pkg)
+}
+
+func writeJson(path string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return utils.WriteFile(path, append(data, '\n'))
+}
+
+func (j javascript) buildLocalTest(q *leetcode.QuestionData, outDir string) (*GenerateResult, caseRunner, error) {
+	genResult, err := j.GeneratePaths(q)
+	if err != nil {
+		return nil, nil, fmt.Errorf("generate paths failed: %w", err)
+	}
+	genResult.SetOutDir(outDir)
+
+	testFile := genResult.GetFile(TestFile).GetPath()
+	if !utils.IsExist(testFile) {
+		return nil, nil, fmt.Errorf("file %s not found", utils.RelToCwd(testFile))
+	}
+	if !j.typescript {
+		return genResult, newCommandRunner(genResult, []string{"node", testFile}), nil
+	}
+
+	tsc := filepath.Join(outDir, "node_modules", "typescript", "bin", "tsc")
+	if !utils.IsExist(tsc) {
+		return nil, nil, fmt.Errorf("typescript not found, please run `npm install` in %s", utils.RelToCwd(outDir))
+	}
+	// Keep the transpiled file inside the workspace, so that node can resolve the bundled package from it.
+	buildDir := filepath.Join(outDir, "node_modules", ".cache", "leetgo", q.TitleSlug)
+	args := []string{"node", tsc}
+	for _, k := range slices.Sorted(maps.Keys(tscFlags)) {
+		v := tscFlags[k]
+		if b, ok := v.(bool); ok {
+			if b {
+				args = append(args, "--"+k)
+			}
+			continue
+		}
+		args = append(args, "--"+k, fmt.Sprint(v))
+	}
+	args = append(args, "--outDir", buildDir, testFile)
+	err = buildTest(q, genResult, args)
+	if err != nil {
+		return nil, nil, fmt.Errorf("transpile failed: %w", err)
+	}
+
+	jsFile := filepath.Join(buildDir, strings.TrimSuffix(filepath.Base(testFile), j.extension)+".js")
+	return genResult, newCommandRunner(genResult, []string{"node", jsFile}), nil
+}
+
+func (j javascript) RunLocalTest(q *leetcode.QuestionData, outDir string, targetCase string) (bool, error) {
+	return runLocalTest(j, q, outDir, targetCase)
+}
+
+func (j javascript) mainDecl() string {
+	if j.typescript {
+		return "function main(): void {"
+	}
+	return "function main() {"
+}
+
+func (j javascript) generateNormalTestCode(q *leetcode.QuestionData) (string, error) {
+	const template = `%s
+%s}
+
+main();
+`
+	code := ""
+	paramNames := make([]string, 0, len(q.MetaData.Params))
+	for _, param := range q.MetaData.Params {
+		code += fmt.Sprintf("\tconst %s = deserialize(\"%s\", readLine());\n", param.Name, param.Type)
+		paramNames = append(paramNames, param.Name)
+	}
+	if q.MetaData.Return != nil && q.MetaData.Return.Type != "void" {
+		code += fmt.Sprintf("\tconst ans = %s(%s);\n", q.MetaData.Name, strings.Join(paramNames, ", "))
+		code += fmt.Sprintf(
+			"\tconsole.log(\"\\n%s \" + serialize(ans, \"%s\"));\n",
+			testCaseOutputMark,
+			q.MetaData.Return.Type,
+		)
+	} else {
+		code += fmt.Sprintf("\t%s(%s);\n", q.MetaData.Name, strings.Join(paramNames, ", "))
+		if q.MetaData.Output != nil {
+			param := q.MetaData.Params[q.MetaData.Output.ParamIndex]
+			code += fmt.Sprintf("\tconst ans = %s;\n", param.Name)
+			code += fmt.Sprintf(
+				"\tconsole.log(\"\\n%s \" + serialize(ans, \"%s\"));\n",
+				testCaseOutputMark,
+				param.Type,
+			)
+		} else {
+			code += fmt.Sprintf("\tconsole.log(\"\\n%s null\");\n", testCaseOutputMark)
+		}
+	}
+
+	testContent := fmt.Sprintf(template, j.mainDecl(), code)
+	if q.MetaData.Manual {
+		testContent = fmt.Sprintf("// %s\n%s", manualWarning, testContent)
+	}
+	return testContent, nil
+}
+
+func (j javascript) generateSystemDesignTestCode(q *leetcode.QuestionData) (string, error) {
+	const template = `%s
+	const ops = deserialize("string[]", readLine());
+	const params = splitArray(readLine());
+	const output = ["null"];
+
+%s
+
+	for (let i = 1; i < ops.length; i++) {
+		switch (ops[i]) {
+%s
+		}
+	}
+	console.log("\n%s " + joinArray(output));
+}
+
+main();
+`
+	var prepareCode string
+	var paramNames []string
+	if len(q.MetaData.Constructor.Params) > 0 {
+		prepareCode += "\tconst constructorParams = splitArray(params[0]);\n"
+		for i, param := range q.MetaData.Constructor.Params {
+			prepareCode += fmt.Sprintf(
+				"\tconst %s = deserialize(\"%s\", constructorParams[%d]);\n",
+				param.Name,
+				param.Type,
+				i,
+			)
+			paramNames = append(paramNames, param.Name)
+		}
+	}
+	prepareCode += fmt.Sprintf("\tconst obj = new %s(%s);", q.MetaData.ClassName, strings.Join(paramNames, ", "))
+
+	callCode := ""
+	for _, method := range q.MetaData.Methods {
+		methodCall := fmt.Sprintf("\t\t\tcase \"%s\": {\n", method.Name)
+		if len(method.Params) > 0 {
+			methodCall += "\t\t\t\tconst methodParams = splitArray(params[i]);\n"
+		}
+		var methodParamNames []string
+		for i, param := range method.Params {
+			methodCall += fmt.Sprintf(
+				"\t\t\t\tconst %s = deserialize(\"%s\", methodParams[%d]);\n",
+				param.Name,
+				param.Type,
+				i,
+			)
+			methodParamNames = append(methodParamNames, param.Name)
+		}
+		if method.Return.Type != "" && method.Return.Type != "void" {
+			methodCall += fmt.Sprintf(
+				"\t\t\t\toutput.push(serialize(obj.%s(%s), \"%s\"));\n",
+				method.Name,
+				strings.Join(methodParamNames, ", "),
+				method.Return.Type,
+			)
+		} else {
+			methodCall += fmt.Sprintf(
+				"\t\t\t\tobj.%s(%s);\n",
+				method.Name,
+				strings.Join(methodParamNames, ", "),
+			)
+			methodCall += "\t\t\t\toutput.push(\"null\");\n"
+		}
+		methodCall += "\t\t\t\tbreak;\n\t\t\t}\n"
+		callCode += methodCall
+	}
+	callCode = callCode[:len(callCode)-1] // remove last newline
+	testContent := fmt.Sprintf(
+		template,
+		j.mainDecl(),
+		prepareCode,
+		callCode,
+		testCaseOutputMark,
+	)
+	if q.MetaData.Manual {
+		testContent = fmt.Sprintf("// %s\n%s", manualWarning, testContent)
+	}
+	return testContent, nil
+}
+
+func (j javascript) generateTestContent(q *leetcode.QuestionData) (string, error) {
+	if q.MetaData.SystemDesign {
+		return j.generateSystemDesignTestCode(q)
+	}
+	return j.generateNormalTestCode(q)
+}
+
+func (j javascript) generateCodeFile(
+	q *leetcode.QuestionData,
+	filename string,
+	blocks []config.Block,
+	modifiers []ModifierFunc,
+	separateDescriptionFile bool,
+) (
+	FileOutput,
+	error,
+) {
+	const imports = "ListNode, TreeNode, deserialize, serialize, splitArray, joinArray, readLine"
+	codeHeader := fmt.Sprintf("const { %s } = require(\"%s\");\n", imports, jsUtils.PackageName)
+	if j.typescript {
+		codeHeader = fmt.Sprintf("import { %s } from \"%s\";\n", imports, jsUtils.PackageName)
+	}
+	testContent, err := j.generateTestContent(q)
+	if err != nil {
+		return FileOutput{}, err
+	}
+	blocks = append(
+		[]config.Block{
+			{
+				Name:     beforeBeforeMarker,
+				Template: codeHeader,
+			},
+			{
+				Name:     afterAfterMarker,
+				Template: testContent,
+			},
+		},
+		blocks...,
+	)
+	content, err := j.generateCodeContent(
+		q,
+		blocks,
+		modifiers,
+		separateDescriptionFile,
+	)
+	if err != nil {
+		return FileOutput{}, err
+	}
+	return FileOutput{
+		Filename: filename,
+		Content:  content,
+		Type:     CodeFile | TestFile,
+	}, nil
+}
+
+func (j javascript) GeneratePaths(q *leetcode.QuestionData) (*GenerateResult, error) {
+	filenameTmpl := getFilenameTemplate(q, j)
+	baseFilename, err := q.GetFormattedFilename(j.slug, filenameTmpl)
+	if err != nil {
+		return nil, err
+	}
+	genResult := &GenerateResult{
+		SubDir:   baseFilename,
+		Question: q,
+		Lang:     j,
+	}
+	genResult.AddFile(
+		FileOutput{
+			Filename: "solution" + j.extension,
+			Type:     CodeFile | TestFile,
+		},
+	)
+	genResult.AddFile(
+		FileOutput{
+			Filename: "testcases.txt",
+			Type:     TestCasesFile,
+		},
+	)
+	if separateDescriptionFile(j) {
+		genResult.AddFile(
+			FileOutput{
+				Filename: "question.md",
+				Type:     DocFile,
+			},
+		)
+	}
+	return genResult, nil
+}
+
+func (j javascript) Generate(q *leetcode.QuestionData) (*GenerateResult, error) {
+	filenameTmpl := getFilenameTemplate(q, j)
+	baseFilename, err := q.GetFormattedFilename(j.slug, filenameTmpl)
+	if err != nil {
+		return nil, err
+	}
+	genResult := &GenerateResult{
+		Question: q,
+		Lang:     j,
+		SubDir:   baseFilename,
+	}
+
+	separateDescriptionFile := separateDescriptionFile(j)
+	blocks := getBlocks(j)
+	modifiers, err := getModifiers(j, builtinModifiers)
+	if err != nil {
+		return nil, err
+	}
+	codeFile, err := j.generateCodeFile(q, "solution"+j.extension, blocks, modifiers, separateDescriptionFile)
+	if err != nil {
+		return nil, err
+	}
+	testcaseFile, err := j.generateTestCasesFile(q, "testcases.txt")
+	if err != nil {
+		return nil, err
+	}
+	genResult.AddFile(codeFile)
+	genResult.AddFile(testcaseFile)
+
+	if separateDescriptionFile {
+		docFile, err := j.generateDescriptionFile(q, "question.md")
+		if err != nil {
+			return nil, err
+		}
+		genResult.AddFile(docFile)
+	}
+
+	return genResult, nil
+}
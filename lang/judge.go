@@ -4,12 +4,14 @@ import (
 	"fmt"
 	"math"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 
 	"github.com/goccy/go-json"
 	strip "github.com/grokify/html-strip-tags-go"
 
+	"github.com/j178/leetgo/config"
 	"github.com/j178/leetgo/leetcode"
 	goutils "github.com/j178/leetgo/testutils/go"
 )
@@ -63,6 +65,11 @@ func newSliceJudger(ignoreOrder bool, subJudger Judger) *sliceJudger {
 }
 
 func (j *sliceJudger) Judge(input []string, output, actualOutput string) JudgeResult {
+	return j.judge(input, nil, output, actualOutput)
+}
+
+// judge compares the slices nested at path, so that the difference can be reported like "at row 3 col 2".
+func (j *sliceJudger) judge(input []string, path []int, output, actualOutput string) JudgeResult {
 	if output == actualOutput {
 		return accepted()
 	}
@@ -70,28 +77,106 @@ func (j *sliceJudger) Judge(input []string, output, actualOutput string) JudgeRe
 	a, err1 := goutils.SplitArray(output)
 	b, err2 := goutils.SplitArray(actualOutput)
 	if err1 != nil || err2 != nil {
-		return failed(fmt.Sprintf("expected %q, got %q", output, actualOutput))
+		return failed(fmt.Sprintf("expected %q, got %q", output, actualOutput) + at(path))
 	}
 	if len(a) != len(b) {
-		return failed(fmt.Sprintf("expected %d elements, got %d", len(a), len(b)))
+		return failed(fmt.Sprintf("expected %d elements, got %d", len(a), len(b)) + at(path))
 	}
 
 	if j.ignoreOrder {
-		if !j.compareIgnoringOrder(a, b) {
-			return failed(fmt.Sprintf("expected %q, got %q", output, actualOutput))
-		}
-		return accepted()
+		return j.compareIgnoringOrder(path, a, b)
 	}
 
 	for i := range a {
+		p := append(path[:len(path):len(path)], i)
+		if sub, ok := j.subJudger.(*sliceJudger); ok {
+			if r := sub.judge(input, p, a[i], b[i]); !r.IsAccepted() {
+				return r
+			}
+			continue
+		}
 		if r := j.subJudger.Judge(input, a[i], b[i]); !r.IsAccepted() {
-			rr := failed(r.GetInfo() + " at index " + strconv.Itoa(i))
-			return rr
+			return failed(r.GetInfo() + at(p))
 		}
 	}
 	return accepted()
 }
 
+// compareIgnoringOrder compares the elements as multisets, elements are compared by their canonical forms,
+// so nested slices that ignore order are equal regardless of the order of their elements.
+func (j *sliceJudger) compareIgnoringOrder(path []int, expected, actual []string) JudgeResult {
+	cnt := map[string]int{}
+	for _, v := range expected {
+		cnt[canonicalValue(j.subJudger, v)]++
+	}
+	for i, v := range actual {
+		c := canonicalValue(j.subJudger, v)
+		cnt[c]--
+		if cnt[c] < 0 {
+			return failed(fmt.Sprintf("unexpected element %s", v) + at(append(path[:len(path):len(path)], i)))
+		}
+	}
+	for _, v := range expected {
+		if cnt[canonicalValue(j.subJudger, v)] > 0 {
+			return failed(fmt.Sprintf("missing element %s", v) + at(path))
+		}
+	}
+	return accepted()
+}
+
+// canonicalValue returns a representation of the raw value that is equal for values the judger accepts as equal.
+func canonicalValue(j Judger, raw string) string {
+	raw = strings.TrimSpace(raw)
+	switch j := j.(type) {
+	case *sliceJudger:
+		elems, err := goutils.SplitArray(raw)
+		if err != nil {
+			return raw
+		}
+		for i, e := range elems {
+			elems[i] = canonicalValue(j.subJudger, e)
+		}
+		if j.ignoreOrder {
+			slices.Sort(elems)
+		}
+		return "[" + strings.Join(elems, ",") + "]"
+	case floatJudger:
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return raw
+		}
+		return strconv.FormatFloat(math.Round(f*1e5)/1e5, 'f', -1, 64)
+	case treeJudger:
+		if t, err := goutils.DeserializeTreeNode(raw); err == nil {
+			return t.String()
+		}
+	case listJudger:
+		if l, err := goutils.DeserializeListNode(raw); err == nil {
+			return l.String()
+		}
+	}
+	return raw
+}
+
+// at formats the location of a nested value, e.g. " at index 3" or " at row 3 col 2".
+func at(path []int) string {
+	switch len(path) {
+	case 0:
+		return ""
+	case 1:
+		return fmt.Sprintf(" at index %d", path[0])
+	case 2:
+		return fmt.Sprintf(" at row %d col %d", path[0], path[1])
+	default:
+		var sb strings.Builder
+		sb.WriteString(" at index ")
+		for _, i := range path {
+			sb.WriteString("[" + strconv.Itoa(i) + "]")
+		}
+		return sb.String()
+	}
+}
+
 var anyOrderRe = regexp.MustCompile(`(?i)return.* in any order`)
 
 // TODO improve the detection of "any order"
@@ -110,23 +195,72 @@ func shouldIgnoreOrder(q *leetcode.QuestionData) bool {
 	return false
 }
 
-func (j *sliceJudger) compareIgnoringOrder(actual, expected []string) bool {
-	cnt := map[string]int{}
-	for _, v := range actual {
-		cnt[v]++
+// treeJudger compares binary trees node by node.
+type treeJudger struct{}
+
+func (treeJudger) Judge(input []string, output, actualOutput string) JudgeResult {
+	a, err1 := goutils.DeserializeTreeNode(output)
+	b, err2 := goutils.DeserializeTreeNode(actualOutput)
+	if err1 != nil || err2 != nil {
+		return failed(fmt.Sprintf("expected %q, got %q", output, actualOutput))
 	}
-	for _, v := range expected {
-		cnt[v]--
-		if cnt[v] < 0 {
-			return false
+	if path, ok := diffTree(a, b, "root"); !ok {
+		return failed(fmt.Sprintf("node at path %s: expected %s, got %s", path, treeNodeValue(a, path), treeNodeValue(b, path)))
+	}
+	return accepted()
+}
+
+// diffTree returns the path of the first node that differs in pre-order.
+func diffTree(a, b *goutils.TreeNode, path string) (string, bool) {
+	if a == nil || b == nil {
+		return path, a == b
+	}
+	if a.Val != b.Val {
+		return path, false
+	}
+	if p, ok := diffTree(a.Left, b.Left, path+".left"); !ok {
+		return p, false
+	}
+	return diffTree(a.Right, b.Right, path+".right")
+}
+
+func treeNodeValue(root *goutils.TreeNode, path string) string {
+	n := root
+	for _, step := range strings.Split(path, ".")[1:] {
+		if n == nil {
+			break
+		}
+		if step == "left" {
+			n = n.Left
+		} else {
+			n = n.Right
 		}
 	}
-	for _, v := range cnt {
-		if v != 0 {
-			return false
+	if n == nil {
+		return "null"
+	}
+	return strconv.Itoa(n.Val)
+}
+
+// listJudger compares linked lists node by node.
+type listJudger struct{}
+
+func (listJudger) Judge(input []string, output, actualOutput string) JudgeResult {
+	a, err1 := goutils.DeserializeListNode(output)
+	b, err2 := goutils.DeserializeListNode(actualOutput)
+	if err1 != nil || err2 != nil {
+		return failed(fmt.Sprintf("expected %q, got %q", output, actualOutput))
+	}
+	av, bv := a.Values(), b.Values()
+	for i := 0; i < len(av) && i < len(bv); i++ {
+		if av[i] != bv[i] {
+			return failed(fmt.Sprintf("node at index %d: expected %d, got %d", i, av[i], bv[i]))
 		}
 	}
-	return true
+	if len(av) != len(bv) {
+		return failed(fmt.Sprintf("expected %d nodes, got %d", len(av), len(bv)))
+	}
+	return accepted()
 }
 
 type floatJudger struct{}
@@ -149,7 +283,7 @@ func newSystemDesignJudger(q *leetcode.QuestionData) *systemDesignJudger {
 	for _, m := range q.MetaData.Methods {
 		// NOTE: if two functions both return a slice, we can't distinguish them
 		//  We just compare both function results ignoring order.
-		judgers[m.Name] = getJudger(q, m.Return.Type, true)
+		judgers[m.Name] = getJudger(q, m.Return.Type, 0)
 	}
 	return &systemDesignJudger{judgers}
 }
@@ -181,28 +315,176 @@ func (s systemDesignJudger) Judge(input []string, output, actualOutput string) J
 	return accepted()
 }
 
+// tableJudger compares the result tables of database questions.
+// Column names are compared case-insensitively, cells are compared by their normalized values,
+// so that `1.00` equals `1`. A JSON null is NULL, it never equals a string, even "null".
+type tableJudger struct {
+	ignoreOrder bool
+}
+
+func (j tableJudger) Judge(input []string, output, actualOutput string) JudgeResult {
+	expected, err1 := parseSQLTable(output)
+	actual, err2 := parseSQLTable(actualOutput)
+	if err1 != nil || err2 != nil {
+		return failed(fmt.Sprintf("expected %q, got %q", output, actualOutput))
+	}
+
+	if len(expected.Headers) != len(actual.Headers) {
+		return failed(fmt.Sprintf("expected columns %v, got %v", expected.Headers, actual.Headers))
+	}
+	for i := range expected.Headers {
+		if !strings.EqualFold(expected.Headers[i], actual.Headers[i]) {
+			return failed(fmt.Sprintf("expected columns %v, got %v", expected.Headers, actual.Headers))
+		}
+	}
+	if len(expected.Values) != len(actual.Values) {
+		return failed(fmt.Sprintf("expected %d rows, got %d", len(expected.Values), len(actual.Values)))
+	}
+
+	a := make([]string, len(expected.Values))
+	b := make([]string, len(actual.Values))
+	for i := range expected.Values {
+		a[i] = normalizeSQLRow(expected.Values[i])
+		b[i] = normalizeSQLRow(actual.Values[i])
+	}
+	if !j.ignoreOrder {
+		for i := range a {
+			if a[i] != b[i] {
+				return failed(fmt.Sprintf("expected row %s, got %s at index %d", a[i], b[i], i))
+			}
+		}
+		return accepted()
+	}
+
+	cnt := map[string]int{}
+	for _, row := range a {
+		cnt[row]++
+	}
+	for _, row := range b {
+		cnt[row]--
+		if cnt[row] < 0 {
+			return failed(fmt.Sprintf("unexpected row %s", row))
+		}
+	}
+	for _, row := range a {
+		if cnt[row] > 0 {
+			return failed(fmt.Sprintf("missing row %s", row))
+		}
+	}
+	return accepted()
+}
+
+// normalizeSQLRow returns a comparable representation of a row.
+func normalizeSQLRow(row []any) string {
+	cells := make([]string, len(row))
+	for i, v := range row {
+		cells[i] = normalizeSQLValue(v)
+	}
+	return "[" + strings.Join(cells, ",") + "]"
+}
+
+func normalizeSQLValue(v any) string {
+	var s string
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case json.Number:
+		s = v.String()
+	case float64:
+		s = strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case string:
+		s = v
+	default:
+		return fmt.Sprint(v)
+	}
+	// Numbers are compared with a precision of 1e-5, which LeetCode uses for floats.
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return strconv.FormatFloat(math.Round(f*1e5)/1e5, 'f', -1, 64)
+	}
+	return strconv.Quote(s)
+}
+
+// lineJudger compares the stdout of shell questions line by line.
+// Trailing whitespaces of lines and trailing empty lines are ignored.
+type lineJudger struct{}
+
+func (lineJudger) Judge(input []string, output, actualOutput string) JudgeResult {
+	expected, err1 := strconv.Unquote(output)
+	actual, err2 := strconv.Unquote(actualOutput)
+	if err1 != nil || err2 != nil {
+		return failed(fmt.Sprintf("expected %s, got %s", output, actualOutput))
+	}
+	a := splitOutputLines(expected)
+	b := splitOutputLines(actual)
+	for i := 0; i < len(a) || i < len(b); i++ {
+		switch {
+		case i >= len(a):
+			return failed(fmt.Sprintf("unexpected line %d: %q", i+1, b[i]))
+		case i >= len(b):
+			return failed(fmt.Sprintf("missing line %d: %q", i+1, a[i]))
+		case a[i] != b[i]:
+			return failed(fmt.Sprintf("expected %q, got %q at line %d", a[i], b[i], i+1))
+		}
+	}
+	return accepted()
+}
+
+func splitOutputLines(s string) []string {
+	lines := strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t\r")
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
 func GetJudger(q *leetcode.QuestionData) Judger {
 	if q.MetaData.SystemDesign {
 		return newSystemDesignJudger(q)
 	}
+	if q.MetaData.Database {
+		return tableJudger{ignoreOrder: shouldIgnoreOrder(q)}
+	}
+	if q.MetaData.Shell {
+		return lineJudger{}
+	}
 	resultType := q.MetaData.ResultType()
-	return getJudger(q, resultType, true)
+	return getJudger(q, resultType, 0)
 }
 
-func getJudger(q *leetcode.QuestionData, tp string, topLevel bool) Judger {
+// getJudger returns the judger of the type nested at level, 0 is the top level.
+func getJudger(q *leetcode.QuestionData, tp string, level int) Judger {
 	switch tp {
 	case "double":
 		return floatJudger{}
 	case "string":
 		return stringJudger{}
+	case "TreeNode":
+		return treeJudger{}
+	case "ListNode":
+		return listJudger{}
 	default:
 		if strings.HasSuffix(tp, "[]") {
-			// Support top-level slice out-of-order comparison only.
-			ignoreOrder := topLevel && shouldIgnoreOrder(q)
-			subJudger := getJudger(q, tp[:len(tp)-2], false)
-			return newSliceJudger(ignoreOrder, subJudger)
+			subJudger := getJudger(q, tp[:len(tp)-2], level+1)
+			return newSliceJudger(ignoreOrderAt(q, level), subJudger)
 		}
-		// void, bool, int, long, TreeNode, etc.
+		// void, bool, int, long, etc.
 		return stringJudger{}
 	}
 }
+
+// ignoreOrderAt reports whether the slice nested at level should be compared ignoring order.
+// Levels configured by `code.judges[].ignore_order` take precedence over the detection of "in any order",
+// which only applies to the top level.
+func ignoreOrderAt(q *leetcode.QuestionData, level int) bool {
+	for _, j := range config.Get().Code.Judges {
+		if j.IgnoreOrder != nil && isJudgeOf(j, q) {
+			return slices.Contains(j.IgnoreOrder, level)
+		}
+	}
+	return level == 0 && shouldIgnoreOrder(q)
+}
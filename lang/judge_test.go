@@ -0,0 +1,51 @@
+package lang
+
+import "testing"
+
+func TestStructuralJudgers(t *testing.T) {
+	tests := []struct {
+		name     string
+		judger   Judger
+		expected string
+		actual   string
+		info     string
+	}{
+		{"tree equal", treeJudger{}, "[1,2,3,null,5]", "[1,2,3,null,5]", ""},
+		{"tree value", treeJudger{}, "[1,2,3,null,5]", "[1,2,3,null,7]", "node at path root.left.right: expected 5, got 7"},
+		{"tree missing", treeJudger{}, "[1,2,3]", "[1,2]", "node at path root.right: expected 3, got null"},
+		{"tree extra", treeJudger{}, "[1]", "[1,null,2]", "node at path root.right: expected null, got 2"},
+		{"list equal", listJudger{}, "[1,2,3]", "[1, 2, 3]", ""},
+		{"list value", listJudger{}, "[1,2,3]", "[1,4,3]", "node at index 1: expected 2, got 4"},
+		{"list length", listJudger{}, "[1,2,3]", "[1,2]", "expected 3 nodes, got 2"},
+		{
+			"matrix", newSliceJudger(false, newSliceJudger(false, stringJudger{})),
+			"[[1,2],[3,4],[5,6]]", "[[1,2],[3,4],[5,7]]", `expected "6", got "7" at row 2 col 1`,
+		},
+		{
+			"3d", newSliceJudger(false, newSliceJudger(false, newSliceJudger(false, stringJudger{}))),
+			"[[[1],[2]]]", "[[[1],[3]]]", `expected "2", got "3" at index [0][1][0]`,
+		},
+		{
+			"trees", newSliceJudger(false, treeJudger{}),
+			"[[1,2],[1,null,2]]", "[[1,2],[1,null,3]]", "node at path root.right: expected 2, got 3 at index 1",
+		},
+		{
+			"unordered outer only", newSliceJudger(true, newSliceJudger(false, stringJudger{})),
+			`[["a","b"],["c"]]`, `[["c"],["b","a"]]`, `unexpected element ["b","a"] at index 1`,
+		},
+		{
+			"unordered both", newSliceJudger(true, newSliceJudger(true, stringJudger{})),
+			`[["ate","eat","tea"],["nat","tan"],["bat"]]`, `[["bat"],["tan","nat"],["eat","tea","ate"]]`, "",
+		},
+		{
+			"unordered floats", newSliceJudger(true, floatJudger{}),
+			"[1.00000,2.50000]", "[2.5,1.0]", "",
+		},
+	}
+	for _, tt := range tests {
+		r := tt.judger.Judge(nil, tt.expected, tt.actual)
+		if r.IsAccepted() != (tt.info == "") || r.GetInfo() != tt.info {
+			t.Errorf("%s: got %v %q, want %q", tt.name, r.IsAccepted(), r.GetInfo(), tt.info)
+		}
+	}
+}
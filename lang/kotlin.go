@@ -0,0 +1,339 @@
+package lang
+
+import (
+	"fmt"
+	"hash/fnv"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	"github.com/google/shlex"
+
+	"github.com/j178/leetgo/config"
+	"github.com/j178/leetgo/leetcode"
+	kotlinUtils "github.com/j178/leetgo/testutils/kotlin"
+	"github.com/j178/leetgo/utils"
+)
+
+type kotlin struct {
+	baseLang
+}
+
+func (k kotlin) InitWorkspace(outDir string) error {
+	if should, err := k.shouldInit(outDir); err != nil || !should {
+		return err
+	}
+
+	err := fs.WalkDir(
+		kotlinUtils.Sources, kotlinUtils.PackageName, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			content, err := kotlinUtils.Sources.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			return utils.WriteFile(filepath.Join(outDir, filepath.FromSlash(path)), content)
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	err = UpdateDep(k)
+	return err
+}
+
+func (k kotlin) shouldInit(outDir string) (bool, error) {
+	entries, err := kotlinUtils.Sources.ReadDir(kotlinUtils.PackageName)
+	if err != nil {
+		return false, err
+	}
+	for _, entry := range entries {
+		if !utils.IsExist(filepath.Join(outDir, kotlinUtils.PackageName, entry.Name())) {
+			return true, nil
+		}
+	}
+
+	update, err := IsDepUpdateToDate(k)
+	if err != nil {
+		return false, err
+	}
+	return !update, nil
+}
+
+// See java.generateNormalTestCode for why the harness looks up the solution by reflection.
+func (k kotlin) generateNormalTestCode(q *leetcode.QuestionData) (string, error) {
+	const template = `fun main() {
+    val stdin = System.%[1]s.bufferedReader()
+    val method = LeetCodeIO.getMethod(Solution::class.java, "%[2]s", %[5]d)
+    val params = LeetCodeIO.readArgs(stdin, method)
+%[3]s
+    println("\n%[4]s " + LeetCodeIO.serialize(ans, ansType))
+}`
+	var code string
+	if q.MetaData.Return != nil && q.MetaData.Return.Type != "void" {
+		code = "    val ans = LeetCodeIO.invoke(Solution(), method, params)\n"
+		code += "    val ansType = method.genericReturnType"
+	} else {
+		code = "    LeetCodeIO.invoke(Solution(), method, params)\n"
+		if q.MetaData.Output != nil {
+			code += fmt.Sprintf("    val ans = params[%d]\n", q.MetaData.Output.ParamIndex)
+			code += fmt.Sprintf("    val ansType = method.genericParameterTypes[%d]", q.MetaData.Output.ParamIndex)
+		} else {
+			code += "    val ans = null\n"
+			code += "    val ansType = Void.TYPE"
+		}
+	}
+
+	testContent := fmt.Sprintf(
+		template,
+		"`in`",
+		q.MetaData.Name,
+		code,
+		testCaseOutputMark,
+		len(q.MetaData.Params),
+	)
+	if q.MetaData.Manual {
+		testContent = fmt.Sprintf("// %s\n%s", manualWarning, testContent)
+	}
+	return testContent, nil
+}
+
+func (k kotlin) generateSystemDesignTestCode(q *leetcode.QuestionData) (string, error) {
+	const template = `fun main() {
+    val stdin = System.%[1]s.bufferedReader()
+    val ops = LeetCodeIO.splitArray(LeetCodeIO.readLine(stdin))
+    val params = LeetCodeIO.splitArray(LeetCodeIO.readLine(stdin))
+    val output = mutableListOf("null")
+
+    val constructor = LeetCodeIO.getConstructor(%[2]s::class.java)
+    val constructorParams = LeetCodeIO.deserializeArgs(constructor, LeetCodeIO.splitArray(params[0]))
+    val obj = LeetCodeIO.newInstance(constructor, constructorParams)
+
+    for (i in 1 until ops.size) {
+        val op = LeetCodeIO.deserialize(String::class.java, ops[i]) as String
+        val opArgs = LeetCodeIO.splitArray(params[i])
+        val method = LeetCodeIO.getMethod(%[2]s::class.java, op, opArgs.size)
+        val methodParams = LeetCodeIO.deserializeArgs(method, opArgs)
+        val ans = LeetCodeIO.invoke(obj, method, methodParams)
+        output.add(if (method.returnType == Void.TYPE) "null" else LeetCodeIO.serialize(ans, method.genericReturnType))
+    }
+
+    println("\n%[3]s " + LeetCodeIO.joinArray(output))
+}`
+	testContent := fmt.Sprintf(template, "`in`", q.MetaData.ClassName, testCaseOutputMark)
+	if q.MetaData.Manual {
+		testContent = fmt.Sprintf("// %s\n%s", manualWarning, testContent)
+	}
+	return testContent, nil
+}
+
+func (k kotlin) generateTestContent(q *leetcode.QuestionData) (string, error) {
+	if q.MetaData.SystemDesign {
+		return k.generateSystemDesignTestCode(q)
+	}
+	return k.generateNormalTestCode(q)
+}
+
+func (k kotlin) generateCodeFile(
+	q *leetcode.QuestionData,
+	filename string,
+	blocks []config.Block,
+	modifiers []ModifierFunc,
+	separateDescriptionFile bool,
+) (
+	FileOutput,
+	error,
+) {
+	codeHeader := fmt.Sprintf("import %s.*\n", kotlinUtils.PackageName)
+	testContent, err := k.generateTestContent(q)
+	if err != nil {
+		return FileOutput{}, err
+	}
+	blocks = append(
+		[]config.Block{
+			{
+				Name:     beforeBeforeMarker,
+				Template: codeHeader,
+			},
+			{
+				Name:     afterAfterMarker,
+				Template: testContent,
+			},
+		},
+		blocks...,
+	)
+	content, err := k.generateCodeContent(
+		q,
+		blocks,
+		modifiers,
+		separateDescriptionFile,
+	)
+	if err != nil {
+		return FileOutput{}, err
+	}
+	return FileOutput{
+		Filename: filename,
+		Content:  content,
+		Type:     CodeFile | TestFile,
+	}, nil
+}
+
+// getTempJarFile returns the path of the jar compiled from the solution with the compiler command, next to
+// the binaries of other languages. The jar is named after a hash of the command, so changing the compiler or
+// its flags builds a new jar instead of reusing the one built by the old command.
+func getTempJarFile(q *leetcode.QuestionData, lang Lang, compiler []string) (string, error) {
+	execFile, err := getTempBinFile(q, lang, "")
+	if err != nil {
+		return "", err
+	}
+	h := fnv.New32a()
+	for _, arg := range compiler {
+		_, _ = h.Write([]byte(arg))
+		_, _ = h.Write([]byte{0})
+	}
+	return fmt.Sprintf("%s-%08x.jar", strings.TrimSuffix(execFile, filepath.Ext(execFile)), h.Sum32()), nil
+}
+
+// isUpToDate reports whether target exists and is newer than all the sources.
+func isUpToDate(target string, sources ...string) bool {
+	targetInfo, err := os.Stat(target)
+	if err != nil {
+		return false
+	}
+	for _, src := range sources {
+		info, err := os.Stat(src)
+		if err != nil || info.ModTime().After(targetInfo.ModTime()) {
+			return false
+		}
+	}
+	return true
+}
+
+func (k kotlin) buildLocalTest(q *leetcode.QuestionData, outDir string) (*GenerateResult, caseRunner, error) {
+	genResult, err := k.GeneratePaths(q)
+	if err != nil {
+		return nil, nil, fmt.Errorf("generate paths failed: %w", err)
+	}
+	genResult.SetOutDir(outDir)
+
+	testFile := genResult.GetFile(TestFile).GetPath()
+	if !utils.IsExist(testFile) {
+		return nil, nil, fmt.Errorf("file %s not found", utils.RelToCwd(testFile))
+	}
+	cfg := config.Get()
+	compilerFlags, _ := shlex.Split(cfg.Code.Kotlin.Flags)
+	compiler := append([]string{cfg.Code.Kotlin.Kotlinc}, compilerFlags...)
+	jarFile, err := getTempJarFile(q, k, compiler)
+	if err != nil {
+		return nil, nil, fmt.Errorf("generate temporary jar file path failed: %w", err)
+	}
+
+	utilsDir := filepath.Join(outDir, kotlinUtils.PackageName)
+	sources := []string{testFile}
+	entries, err := kotlinUtils.Sources.ReadDir(kotlinUtils.PackageName)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, entry := range entries {
+		sources = append(sources, filepath.Join(utilsDir, entry.Name()))
+	}
+
+	// kotlinc is slow to start, skip compiling if the solution is not changed since the last build.
+	if isUpToDate(jarFile, sources...) {
+		log.Debug("jar is up to date, skip building", "jar", jarFile)
+	} else {
+		args := slices.Concat(compiler, []string{"-include-runtime", "-d", jarFile}, sources)
+		err = buildTest(q, genResult, args)
+		if err != nil {
+			// Don't leave a half-written jar that looks up to date.
+			_ = utils.RemoveIfExist(jarFile)
+			return nil, nil, fmt.Errorf("compilation failed: %w", err)
+		}
+	}
+
+	// Top level functions in `solution.kt` are compiled into class `SolutionKt`.
+	return genResult, newCommandRunner(genResult, []string{"java", "-cp", jarFile, "SolutionKt"}), nil
+}
+
+func (k kotlin) RunLocalTest(q *leetcode.QuestionData, outDir string, targetCase string) (bool, error) {
+	return runLocalTest(k, q, outDir, targetCase)
+}
+
+func (k kotlin) Generate(q *leetcode.QuestionData) (*GenerateResult, error) {
+	filenameTmpl := getFilenameTemplate(q, k)
+	baseFilename, err := q.GetFormattedFilename(k.slug, filenameTmpl)
+	if err != nil {
+		return nil, err
+	}
+	genResult := &GenerateResult{
+		Question: q,
+		Lang:     k,
+		SubDir:   baseFilename,
+	}
+
+	separateDescriptionFile := separateDescriptionFile(k)
+	blocks := getBlocks(k)
+	modifiers, err := getModifiers(k, builtinModifiers)
+	if err != nil {
+		return nil, err
+	}
+	codeFile, err := k.generateCodeFile(q, "solution.kt", blocks, modifiers, separateDescriptionFile)
+	if err != nil {
+		return nil, err
+	}
+	testcaseFile, err := k.generateTestCasesFile(q, "testcases.txt")
+	if err != nil {
+		return nil, err
+	}
+	genResult.AddFile(codeFile)
+	genResult.AddFile(testcaseFile)
+
+	if separateDescriptionFile {
+		docFile, err := k.generateDescriptionFile(q, "question.md")
+		if err != nil {
+			return nil, err
+		}
+		genResult.AddFile(docFile)
+	}
+
+	return genResult, nil
+}
+
+func (k kotlin) GeneratePaths(q *leetcode.QuestionData) (*GenerateResult, error) {
+	filenameTmpl := getFilenameTemplate(q, k)
+	baseFilename, err := q.GetFormattedFilename(k.slug, filenameTmpl)
+	if err != nil {
+		return nil, err
+	}
+	genResult := &GenerateResult{
+		SubDir:   baseFilename,
+		Question: q,
+		Lang:     k,
+	}
+	genResult.AddFile(
+		FileOutput{
+			Filename: "solution.kt",
+			Type:     CodeFile | TestFile,
+		},
+	)
+	genResult.AddFile(
+		FileOutput{
+			Filename: "testcases.txt",
+			Type:     TestCasesFile,
+		},
+	)
+	if separateDescriptionFile(k) {
+		genResult.AddFile(
+			FileOutput{
+				Filename: "question.md",
+				Type:     DocFile,
+			},
+		)
+	}
+	return genResult, nil
+}
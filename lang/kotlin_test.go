@@ -0,0 +1,182 @@
+package lang
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/j178/leetgo/leetcode"
+	kotlinUtils "github.com/j178/leetgo/testutils/kotlin"
+	"github.com/j178/leetgo/utils"
+)
+
+func TestKotlinTestCodeMethodArity(t *testing.T) {
+	q := testQuestion()
+	q.MetaData.Name = "twoSum"
+
+	code, err := kotlinGen.generateTestContent(q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(code, `LeetCodeIO.getMethod(Solution::class.java, "twoSum", 2)`) {
+		t.Errorf("method is not looked up by its parameter count:\n%s", code)
+	}
+
+	q.MetaData = leetcode.MetaData{SystemDesign: true, ClassName: "LRUCache"}
+	code, err = kotlinGen.generateTestContent(q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(code, "LeetCodeIO.getMethod(LRUCache::class.java, op, opArgs.size)") {
+		t.Errorf("method is not looked up by its parameter count:\n%s", code)
+	}
+}
+
+const kotlinTwoSum = `import leetgo.*
+
+class Solution {
+    fun twoSum(nums: IntArray, target: Int): IntArray {
+        val seen = HashMap<Int, Int>()
+        for ((i, n) in nums.withIndex()) {
+            seen[target - n]?.let { return intArrayOf(it, i) }
+            seen[n] = i
+        }
+        return intArrayOf()
+    }
+}
+`
+
+// writeKotlinWorkspace writes the Kotlin utils and the solution of two-sum with its test harness into a new
+// workspace, and returns the workspace and the solution file.
+func writeKotlinWorkspace(t *testing.T, q *leetcode.QuestionData) (string, string) {
+	t.Helper()
+	// Keep the built jars out of the shared temporary directory.
+	t.Setenv("TMPDIR", t.TempDir())
+	outDir := t.TempDir()
+	entries, err := kotlinUtils.Sources.ReadDir(kotlinUtils.PackageName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		path := kotlinUtils.PackageName + "/" + entry.Name()
+		content, err := kotlinUtils.Sources.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := utils.WriteFile(filepath.Join(outDir, filepath.FromSlash(path)), content); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	genResult, err := kotlinGen.GeneratePaths(q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	genResult.SetOutDir(outDir)
+	testContent, err := kotlinGen.generateTestContent(q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	solution := genResult.GetFile(TestFile).GetPath()
+	if err := utils.WriteFile(solution, []byte(kotlinTwoSum+"\n"+testContent+"\n")); err != nil {
+		t.Fatal(err)
+	}
+	return outDir, solution
+}
+
+// TestKotlinBuildReusesJar checks that the jar is only rebuilt when the sources change, with a fake kotlinc
+// that records its runs.
+func TestKotlinBuildReusesJar(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake kotlinc is a shell script")
+	}
+	q := testQuestion()
+	q.MetaData.Name = "twoSum"
+	outDir, solution := writeKotlinWorkspace(t, q)
+
+	binDir := t.TempDir()
+	runs := filepath.Join(binDir, "runs")
+	script := `#!/bin/sh
+echo run >> "` + runs + `"
+while [ $# -gt 0 ]; do
+    if [ "$1" = "-d" ]; then : > "$2"; fi
+    shift
+done
+`
+	if err := os.WriteFile(filepath.Join(binDir, "kotlinc"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	builds := func() int {
+		data, _ := os.ReadFile(runs)
+		return strings.Count(string(data), "run")
+	}
+	for i, want := range []int{1, 1} {
+		if _, _, err := kotlinGen.buildLocalTest(q, outDir); err != nil {
+			t.Fatal(err)
+		}
+		if got := builds(); got != want {
+			t.Errorf("build %d: kotlinc ran %d times, want %d", i+1, got, want)
+		}
+	}
+
+	// Changing the solution rebuilds the jar.
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(solution, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := kotlinGen.buildLocalTest(q, outDir); err != nil {
+		t.Fatal(err)
+	}
+	if got := builds(); got != 2 {
+		t.Errorf("kotlinc ran %d times after the solution changed, want 2", got)
+	}
+}
+
+// TestKotlinLocalTest builds and runs two-sum with the real compiler, when it's installed.
+func TestKotlinLocalTest(t *testing.T) {
+	for _, bin := range []string{"kotlinc", "java"} {
+		if _, err := exec.LookPath(bin); err != nil {
+			t.Skipf("%s not found", bin)
+		}
+	}
+	q := testQuestion()
+	q.MetaData.Name = "twoSum"
+	outDir, _ := writeKotlinWorkspace(t, q)
+
+	_, runner, err := kotlinGen.buildLocalTest(q, outDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := TestCase{Question: q, No: 1, Input: []string{"[2,7,11,15]", "9"}, Output: "[0,1]"}
+	result := runCase(q, c, stringJudger{}, runner, caseLimits{}, time.Minute, time.Minute)
+	if !result.passed {
+		t.Errorf("verdict = %s, want passed\n%s", result.verdict, result.report)
+	}
+}
+
+func TestKotlinJarDependsOnCompiler(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	q := testQuestion()
+	jar := func(compiler ...string) string {
+		path, err := getTempJarFile(q, kotlinGen, compiler)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	base := jar("kotlinc")
+	if jar("kotlinc") != base {
+		t.Error("the same compiler builds different jars")
+	}
+	for _, compiler := range [][]string{{"/opt/kotlin/bin/kotlinc"}, {"kotlinc", "-Werror"}, {"kotlinc-Werror"}} {
+		if jar(compiler...) == base {
+			t.Errorf("%q reuses the jar of kotlinc", compiler)
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package lang
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/dustin/go-humanize"
+
+	"github.com/j178/leetgo/config"
+	"github.com/j178/leetgo/leetcode"
+)
+
+// defaultTimeLimit is used when no time limit is configured at all.
+const defaultTimeLimit = 3 * time.Second
+
+// caseLimits are the resource limits of a local test case, zero memory or output means unlimited.
+type caseLimits struct {
+	time   time.Duration
+	memory uint64
+	output uint64
+}
+
+var warnMemoryLimitOnce sync.Once
+
+// getCaseLimits resolves the limits of the question. Each limit is looked up in the judge entry of the question,
+// then in the config of the language, and finally in the default `code.limits`.
+func getCaseLimits(q *leetcode.QuestionData, lang Lang) (caseLimits, error) {
+	cfg := config.Get()
+	lookup := func(key string, get func(config.Limits) string) string {
+		for _, j := range cfg.Code.Judges {
+			if isJudgeOf(j, q) && get(j.Limits) != "" {
+				return get(j.Limits)
+			}
+		}
+		if ans := getCodeStringConfig(lang, "limits."+key); ans != "" {
+			return ans
+		}
+		return get(cfg.Code.Limits)
+	}
+
+	limits := caseLimits{time: defaultTimeLimit}
+	if s := lookup("time", func(l config.Limits) string { return l.Time }); s != "" {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return limits, fmt.Errorf("invalid time limit %q: %w", s, err)
+		}
+		limits.time = d
+	}
+	var err error
+	if s := lookup("memory", func(l config.Limits) string { return l.Memory }); s != "" {
+		if limits.memory, err = humanize.ParseBytes(s); err != nil {
+			return limits, fmt.Errorf("invalid memory limit %q: %w", s, err)
+		}
+	}
+	if s := lookup("output", func(l config.Limits) string { return l.Output }); s != "" {
+		if limits.output, err = humanize.ParseBytes(s); err != nil {
+			return limits, fmt.Errorf("invalid output limit %q: %w", s, err)
+		}
+	}
+	// Sanitizers use a few times more memory than the program itself for the shadow memory and bookkeeping,
+	// no memory limit makes sense for them.
+	if c, ok := lang.(cpp); ok && hasSanitizer(c.profileFlags(cfg.Code.Cpp.Profile)) {
+		limits.memory = 0
+	}
+	if limits.memory > 0 && !memoryLimitSupported {
+		warnMemoryLimitOnce.Do(
+			func() {
+				log.Warn("memory limit is not supported on this platform, ignored", "limit", humanize.Bytes(limits.memory))
+			},
+		)
+		limits.memory = 0
+	}
+	return limits, nil
+}
+
+// errMemoryLimitExceeded is returned by a caseRunner when the test program was killed for using more memory
+// than the memory limit.
+var errMemoryLimitExceeded = errors.New("memory limit exceeded")
+
+// memoryPollInterval is how often the memory of a running test program is checked.
+const memoryPollInterval = 20 * time.Millisecond
+
+// watchMemory polls the resident memory of the process tree of pid until done is closed, and kills the tree
+// once it goes beyond limit, so a runaway allocation can't exhaust the memory of the machine. It reports whether
+// the tree was killed. The verdict is still judged on the peak RSS after the exit, which catches the peaks
+// between the polls.
+func watchMemory(pid int, limit uint64, done <-chan struct{}) bool {
+	ticker := time.NewTicker(memoryPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return false
+		case <-ticker.C:
+		}
+		pids, rss, err := processTreeRSS(pid)
+		if err != nil || rss <= limit {
+			continue
+		}
+		select {
+		case <-done:
+			// The process has been reaped, its pid may be reused.
+			return false
+		default:
+		}
+		for _, p := range pids {
+			if proc, err := os.FindProcess(p); err == nil {
+				_ = proc.Kill()
+			}
+		}
+		return true
+	}
+}
+
+// errOutputLimitExceeded is returned by a caseRunner when the test program printed more than the output limit.
+var errOutputLimitExceeded = errors.New("output limit exceeded")
+
+// limitedWriter keeps at most limit bytes of the output, and calls onExceed when the output goes beyond it.
+// Writes are never rejected, so the test program is not disturbed before onExceed kills it.
+type limitedWriter struct {
+	strings.Builder
+	limit    uint64
+	onExceed func()
+	exceeded bool
+}
+
+func (w *limitedWriter) Write(p []byte) (int, error) {
+	if w.limit == 0 || uint64(w.Len()+len(p)) <= w.limit {
+		return w.Builder.Write(p)
+	}
+	_, _ = w.Builder.Write(p[:w.limit-uint64(w.Len())])
+	if !w.exceeded {
+		w.exceeded = true
+		w.onExceed()
+	}
+	return len(p), nil
+}
+
+// outOfMemoryMarks are printed by the common runtimes when an allocation fails.
+var outOfMemoryMarks = []string{
+	"bad_alloc",
+	"out of memory",
+	"memory allocation of",
+	"MemoryError",
+	"OutOfMemoryError",
+	"OutOfMemoryException",
+	"Could not reserve enough space",
+	"Cannot allocate memory",
+}
+
+// isOutOfMemory reports whether the test program failed for running out of the memory limit.
+func isOutOfMemory(limits caseLimits, state *os.ProcessState, output string, err error) bool {
+	if limits.memory == 0 {
+		return false
+	}
+	if errors.Is(err, errMemoryLimitExceeded) || peakRSS(state) > limits.memory {
+		return true
+	}
+	if err == nil {
+		return false
+	}
+	for _, mark := range outOfMemoryMarks {
+		if strings.Contains(output, mark) {
+			return true
+		}
+	}
+	return false
+}
+
+// cpuTime returns the CPU time used by the exited process, or 0 if it's unknown.
+func cpuTime(state *os.ProcessState) time.Duration {
+	if state == nil {
+		return 0
+	}
+	return state.UserTime() + state.SystemTime()
+}
+
+// usageString formats the CPU time and peak RSS of the exited process, or returns "" if they are unknown.
+func usageString(state *os.ProcessState) string {
+	if state == nil {
+		return ""
+	}
+	s := fmt.Sprintf("CPU %s", cpuTime(state).Round(time.Millisecond))
+	if rss := peakRSS(state); rss > 0 {
+		s += fmt.Sprintf(", RSS %s", humanize.Bytes(rss))
+	}
+	return s
+}
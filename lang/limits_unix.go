@@ -0,0 +1,108 @@
+//go:build !windows
+
+package lang
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// memoryLimitSupported reports whether the memory of test programs can be watched and judged.
+const memoryLimitSupported = true
+
+// peakRSS returns the peak resident set size of the exited process in bytes, or 0 if it's unknown.
+func peakRSS(state *os.ProcessState) uint64 {
+	if state == nil {
+		return 0
+	}
+	usage, ok := state.SysUsage().(*syscall.Rusage)
+	if !ok || usage.Maxrss <= 0 {
+		return 0
+	}
+	// ru_maxrss is in bytes on macOS, and in kilobytes elsewhere.
+	if runtime.GOOS == "darwin" {
+		return uint64(usage.Maxrss)
+	}
+	return uint64(usage.Maxrss) * 1024
+}
+
+// processTreeRSS returns the process and its descendants, parents first, and their current total resident set
+// size in bytes. Test programs run by e.g. `cargo run` or bash are children of the started process.
+func processTreeRSS(pid int) ([]int, uint64, error) {
+	if runtime.GOOS == "linux" {
+		return procTreeRSS(pid)
+	}
+	return psTreeRSS(pid)
+}
+
+// procTreeRSS reads the process tree from /proc.
+func procTreeRSS(pid int) ([]int, uint64, error) {
+	pageSize := uint64(os.Getpagesize())
+	pids := []int{pid}
+	var total uint64
+	for i := 0; i < len(pids); i++ {
+		statm, err := os.ReadFile(fmt.Sprintf("/proc/%d/statm", pids[i]))
+		if err != nil {
+			if i == 0 {
+				return nil, 0, err
+			}
+			// The child has exited.
+			continue
+		}
+		// The second field is the number of resident pages.
+		if fields := strings.Fields(string(statm)); len(fields) > 1 {
+			pages, _ := strconv.ParseUint(fields[1], 10, 64)
+			total += pages * pageSize
+		}
+		children, _ := filepath.Glob(fmt.Sprintf("/proc/%d/task/*/children", pids[i]))
+		for _, file := range children {
+			data, _ := os.ReadFile(file)
+			for _, f := range strings.Fields(string(data)) {
+				if child, err := strconv.Atoi(f); err == nil {
+					pids = append(pids, child)
+				}
+			}
+		}
+	}
+	return pids, total, nil
+}
+
+// psTreeRSS reads the process tree from ps, for systems without /proc like macOS.
+func psTreeRSS(pid int) ([]int, uint64, error) {
+	out, err := exec.Command("ps", "-A", "-o", "pid=,ppid=,rss=").Output()
+	if err != nil {
+		return nil, 0, err
+	}
+	children := make(map[int][]int)
+	rss := make(map[int]uint64)
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			continue
+		}
+		p, err1 := strconv.Atoi(fields[0])
+		ppid, err2 := strconv.Atoi(fields[1])
+		kb, err3 := strconv.ParseUint(fields[2], 10, 64)
+		if err1 != nil || err2 != nil || err3 != nil {
+			continue
+		}
+		children[ppid] = append(children[ppid], p)
+		rss[p] = kb * 1024
+	}
+	if _, ok := rss[pid]; !ok {
+		return nil, 0, fmt.Errorf("process %d not found", pid)
+	}
+	pids := []int{pid}
+	var total uint64
+	for i := 0; i < len(pids); i++ {
+		total += rss[pids[i]]
+		pids = append(pids, children[pids[i]]...)
+	}
+	return pids, total, nil
+}
@@ -0,0 +1,43 @@
+//go:build !windows
+
+package lang
+
+import (
+	"errors"
+	"os/exec"
+	"testing"
+	"time"
+)
+
+func TestIsOutOfMemoryByPeakRSS(t *testing.T) {
+	cmd := exec.Command("sh", "-c", "true")
+	if err := cmd.Run(); err != nil {
+		t.Skip(err)
+	}
+	rss := peakRSS(cmd.ProcessState)
+	if rss == 0 {
+		t.Skip("peak RSS is not reported")
+	}
+	if !isOutOfMemory(caseLimits{memory: rss - 1}, cmd.ProcessState, "", nil) {
+		t.Errorf("peak RSS %d over the limit %d is not out of memory", rss, rss-1)
+	}
+	if isOutOfMemory(caseLimits{memory: rss}, cmd.ProcessState, "", nil) {
+		t.Errorf("peak RSS %d within the limit %d is out of memory", rss, rss)
+	}
+}
+
+func TestRunCommandKillsRunawayMemory(t *testing.T) {
+	const limit = 64 << 20
+	// Doubles a string until it's killed.
+	cmd := exec.Command("sh", "-c", `x=x; while :; do x="$x$x"; done`)
+	timer := time.AfterFunc(30*time.Second, func() { _ = cmd.Process.Kill() })
+	defer timer.Stop()
+
+	err := runCommand(cmd, limit)
+	if !errors.Is(err, errMemoryLimitExceeded) {
+		t.Fatalf("err = %v, want %v", err, errMemoryLimitExceeded)
+	}
+	if !isOutOfMemory(caseLimits{memory: limit}, cmd.ProcessState, "", err) {
+		t.Error("killed for memory but not out of memory")
+	}
+}
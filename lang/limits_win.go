@@ -0,0 +1,21 @@
+//go:build windows
+
+package lang
+
+import (
+	"errors"
+	"os"
+)
+
+// memoryLimitSupported reports whether the memory of test programs can be watched and judged.
+const memoryLimitSupported = false
+
+// peakRSS returns 0 as the peak resident set size is not reported on Windows.
+func peakRSS(_ *os.ProcessState) uint64 {
+	return 0
+}
+
+// processTreeRSS is not supported on Windows.
+func processTreeRSS(_ int) ([]int, uint64, error) {
+	return nil, 0, errors.New("memory usage of processes is not supported on Windows")
+}
@@ -23,14 +23,16 @@ var (
 			blockCommentEnd:   `"""`,
 		},
 	}
-	pandasGen = baseLang{
-		name:              "Pandas",
-		slug:              "pythondata",
-		shortName:         "py",
-		extension:         ".py",
-		lineComment:       "#",
-		blockCommentStart: `"""`,
-		blockCommentEnd:   `"""`,
+	pandasGen = pandas{
+		baseLang{
+			name:              "Pandas",
+			slug:              "pythondata",
+			shortName:         "py",
+			extension:         ".py",
+			lineComment:       "#",
+			blockCommentStart: `"""`,
+			blockCommentEnd:   `"""`,
+		},
 	}
 	cppGen = cpp{
 		baseLang{
@@ -54,50 +56,61 @@ var (
 			blockCommentEnd:   "*/",
 		},
 	}
-	javaGen = baseLang{
-		name:              "Java",
-		slug:              "java",
-		shortName:         "java",
-		extension:         ".java",
-		lineComment:       "//",
-		blockCommentStart: "/*",
-		blockCommentEnd:   "*/",
+	javaGen = java{
+		baseLang{
+			name:              "Java",
+			slug:              "java",
+			shortName:         "java",
+			extension:         ".java",
+			lineComment:       "//",
+			blockCommentStart: "/*",
+			blockCommentEnd:   "*/",
+		},
 	}
-	cGen = baseLang{
-		name:              "C",
-		slug:              "c",
-		shortName:         "c",
-		extension:         ".c",
-		lineComment:       "//",
-		blockCommentStart: "/*",
-		blockCommentEnd:   "*/",
+	cGen = cLang{
+		baseLang{
+			name:              "C",
+			slug:              "c",
+			shortName:         "c",
+			extension:         ".c",
+			lineComment:       "//",
+			blockCommentStart: "/*",
+			blockCommentEnd:   "*/",
+		},
 	}
-	csharpGen = baseLang{
-		name:              "C#",
-		slug:              "csharp",
-		shortName:         "cs",
-		extension:         ".cs",
-		lineComment:       "//",
-		blockCommentStart: "/*",
-		blockCommentEnd:   "*/",
+	csharpGen = csharp{
+		baseLang{
+			name:              "C#",
+			slug:              "csharp",
+			shortName:         "cs",
+			extension:         ".cs",
+			lineComment:       "//",
+			blockCommentStart: "/*",
+			blockCommentEnd:   "*/",
+		},
 	}
-	jsGen = baseLang{
-		name:              "JavaScript",
-		slug:              "javascript",
-		shortName:         "js",
-		extension:         ".js",
-		lineComment:       "//",
-		blockCommentStart: "/*",
-		blockCommentEnd:   "*/",
+	jsGen = javascript{
+		baseLang: baseLang{
+			name:              "JavaScript",
+			slug:              "javascript",
+			shortName:         "js",
+			extension:         ".js",
+			lineComment:       "//",
+			blockCommentStart: "/*",
+			blockCommentEnd:   "*/",
+		},
 	}
-	tsGen = baseLang{
-		name:              "TypeScript",
-		slug:              "typescript",
-		shortName:         "ts",
-		extension:         ".ts",
-		lineComment:       "//",
-		blockCommentStart: "/*",
-		blockCommentEnd:   "*/",
+	tsGen = javascript{
+		baseLang: baseLang{
+			name:              "TypeScript",
+			slug:              "typescript",
+			shortName:         "ts",
+			extension:         ".ts",
+			lineComment:       "//",
+			blockCommentStart: "/*",
+			blockCommentEnd:   "*/",
+		},
+		typescript: true,
 	}
 	phpGen = baseLang{
 		name:              "PHP",
@@ -126,50 +139,60 @@ var (
 		blockCommentStart: "/*",
 		blockCommentEnd:   "*/",
 	}
-	kotlinGen = baseLang{
-		name:              "Kotlin",
-		slug:              "kotlin",
-		shortName:         "kt",
-		extension:         ".kt",
-		lineComment:       "//",
-		blockCommentStart: "/*",
-		blockCommentEnd:   "*/",
+	kotlinGen = kotlin{
+		baseLang{
+			name:              "Kotlin",
+			slug:              "kotlin",
+			shortName:         "kt",
+			extension:         ".kt",
+			lineComment:       "//",
+			blockCommentStart: "/*",
+			blockCommentEnd:   "*/",
+		},
 	}
-	mysqlGen = baseLang{
-		name:              "MySQL",
-		slug:              "mysql",
-		shortName:         "sql",
-		extension:         ".sql",
-		lineComment:       "--",
-		blockCommentStart: "/*",
-		blockCommentEnd:   "*/",
+	mysqlGen = sqlLang{
+		baseLang{
+			name:              "MySQL",
+			slug:              "mysql",
+			shortName:         "sql",
+			extension:         ".sql",
+			lineComment:       "--",
+			blockCommentStart: "/*",
+			blockCommentEnd:   "*/",
+		},
 	}
-	mssqlGen = baseLang{
-		name:              "MSSQL",
-		slug:              "mssql",
-		shortName:         "sql",
-		extension:         ".sql",
-		lineComment:       "--",
-		blockCommentStart: "/*",
-		blockCommentEnd:   "*/",
+	mssqlGen = sqlLang{
+		baseLang{
+			name:              "MSSQL",
+			slug:              "mssql",
+			shortName:         "sql",
+			extension:         ".sql",
+			lineComment:       "--",
+			blockCommentStart: "/*",
+			blockCommentEnd:   "*/",
+		},
 	}
-	oraclesqlGen = baseLang{
-		name:              "Oracle",
-		slug:              "oraclesql",
-		shortName:         "sql",
-		extension:         ".sql",
-		lineComment:       "--",
-		blockCommentStart: "/*",
-		blockCommentEnd:   "*/",
+	oraclesqlGen = sqlLang{
+		baseLang{
+			name:              "Oracle",
+			slug:              "oraclesql",
+			shortName:         "sql",
+			extension:         ".sql",
+			lineComment:       "--",
+			blockCommentStart: "/*",
+			blockCommentEnd:   "*/",
+		},
 	}
-	bashGen = baseLang{
-		name:              "Bash",
-		slug:              "bash",
-		shortName:         "sh",
-		extension:         ".sh",
-		lineComment:       "#",
-		blockCommentStart: ">>COMMENT",
-		blockCommentEnd:   "\nCOMMENT",
+	bashGen = bash{
+		baseLang{
+			name:              "Bash",
+			slug:              "bash",
+			shortName:         "sh",
+			extension:         ".sh",
+			lineComment:       "#",
+			blockCommentStart: ">>COMMENT",
+			blockCommentEnd:   "\nCOMMENT",
+		},
 	}
 	erlangGen = baseLang{
 		name:        "Erlang",
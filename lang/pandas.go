@@ -0,0 +1,226 @@
+package lang
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/j178/leetgo/config"
+	"github.com/j178/leetgo/constants"
+	"github.com/j178/leetgo/leetcode"
+	"github.com/j178/leetgo/utils"
+)
+
+// pandas runs Pandas questions with the `.venv` created by the Python workspace, the input tables are
+// loaded into DataFrames and the returned DataFrame is judged like the result table of a database question.
+type pandas struct {
+	baseLang
+}
+
+func (p pandas) InitWorkspace(_ string) error {
+	return python3Gen.InitWorkspace(getLangOutDir(python3Gen))
+}
+
+func pandasVenvPython() string {
+	return filepath.Join(getLangOutDir(python3Gen), ".venv", constants.VenvPython)
+}
+
+func (p pandas) generateTestContent(q *leetcode.QuestionData) (string, error) {
+	const template = `if __name__ == "__main__":
+	import inspect
+	import json
+	import sys
+
+	def leetgo_schemas():
+%[1]s
+
+	def normalize(name):
+		return name.replace("_", "").lower()
+
+	def default(o):
+		return o.item() if hasattr(o, "item") else str(o)
+
+	data = json.loads(sys.stdin.readline())
+	schemas = leetgo_schemas()
+	tables = {}
+	for name, columns in data["headers"].items():
+		df = pd.DataFrame(data["rows"].get(name, []), columns=columns)
+		if name in schemas:
+			df = df.astype({k: v for k, v in schemas[name].dtypes.items() if k in df.columns})
+		tables[name] = df
+
+	# Parameters are usually named after the tables, fall back to the order of the tables otherwise.
+	params = list(inspect.signature(%[2]s).parameters)
+	by_name = {normalize(name): df for name, df in tables.items()}
+	if all(normalize(p) in by_name for p in params):
+		args = [by_name[normalize(p)] for p in params]
+	else:
+		args = list(tables.values())[: len(params)]
+
+	ans = %[2]s(*args)
+	if isinstance(ans, pd.Series):
+		ans = ans.to_frame()
+	ans = ans.copy()
+	for col in ans.columns:
+		if pd.api.types.is_datetime64_any_dtype(ans[col]):
+			ans[col] = ans[col].dt.strftime("%%Y-%%m-%%d")
+	values = ans.astype(object).where(ans.notna(), None).values.tolist()
+	headers = [str(c) for c in ans.columns]
+	print("\n%[3]s", json.dumps({"headers": headers, "values": values}, default=default))
+`
+	var schemaCode string
+	for _, line := range q.MetaData.PythonData {
+		schemaCode += "\t\t" + strings.TrimSpace(line) + "\n"
+	}
+	schemaCode += "\t\treturn locals()"
+
+	testContent := fmt.Sprintf(template, schemaCode, q.MetaData.Name, testCaseOutputMark)
+	if q.MetaData.Manual {
+		testContent = fmt.Sprintf("# %s\n%s", manualWarning, testContent)
+	}
+	return testContent, nil
+}
+
+func (p pandas) generateCodeFile(
+	q *leetcode.QuestionData,
+	filename string,
+	blocks []config.Block,
+	modifiers []ModifierFunc,
+	separateDescriptionFile bool,
+) (
+	FileOutput,
+	error,
+) {
+	testContent, err := p.generateTestContent(q)
+	if err != nil {
+		return FileOutput{}, err
+	}
+	blocks = append(
+		[]config.Block{
+			{
+				Name:     afterAfterMarker,
+				Template: testContent,
+			},
+		},
+		blocks...,
+	)
+	content, err := p.generateCodeContent(
+		q,
+		blocks,
+		modifiers,
+		separateDescriptionFile,
+	)
+	if err != nil {
+		return FileOutput{}, err
+	}
+	content = strings.ReplaceAll(content, "\t", "    ")
+	return FileOutput{
+		Filename: filename,
+		Content:  content,
+		Type:     CodeFile | TestFile,
+	}, nil
+}
+
+func (p pandas) generateTestCasesFile(q *leetcode.QuestionData, filename string) (FileOutput, error) {
+	return FileOutput{
+		Filename: filename,
+		Content:  generateTableTestCasesContent(q),
+		Type:     TestCasesFile,
+	}, nil
+}
+
+func (p pandas) buildLocalTest(q *leetcode.QuestionData, outDir string) (*GenerateResult, caseRunner, error) {
+	if !q.MetaData.Database {
+		return nil, nil, fmt.Errorf("%s has no input tables", q.TitleSlug)
+	}
+	genResult, err := p.GeneratePaths(q)
+	if err != nil {
+		return nil, nil, err
+	}
+	genResult.SetOutDir(outDir)
+
+	testFile := genResult.GetFile(TestFile).GetPath()
+	if !utils.IsExist(testFile) {
+		return nil, nil, fmt.Errorf("file %s not found", utils.RelToCwd(testFile))
+	}
+	cmd := []string{pandasVenvPython(), testFile}
+	return genResult, newCommandRunner(genResult, cmd), nil
+}
+
+func (p pandas) RunLocalTest(q *leetcode.QuestionData, outDir string, targetCase string) (bool, error) {
+	return runLocalTest(p, q, outDir, targetCase)
+}
+
+func (p pandas) GeneratePaths(q *leetcode.QuestionData) (*GenerateResult, error) {
+	filenameTmpl := getFilenameTemplate(q, p)
+	baseFilename, err := q.GetFormattedFilename(p.slug, filenameTmpl)
+	if err != nil {
+		return nil, err
+	}
+	genResult := &GenerateResult{
+		SubDir:   baseFilename,
+		Question: q,
+		Lang:     p,
+	}
+	genResult.AddFile(
+		FileOutput{
+			Filename: "solution.py",
+			Type:     CodeFile | TestFile,
+		},
+	)
+	genResult.AddFile(
+		FileOutput{
+			Filename: "testcases.txt",
+			Type:     TestCasesFile,
+		},
+	)
+	if separateDescriptionFile(p) {
+		genResult.AddFile(
+			FileOutput{
+				Filename: "question.md",
+				Type:     DocFile,
+			},
+		)
+	}
+	return genResult, nil
+}
+
+func (p pandas) Generate(q *leetcode.QuestionData) (*GenerateResult, error) {
+	filenameTmpl := getFilenameTemplate(q, p)
+	baseFilename, err := q.GetFormattedFilename(p.slug, filenameTmpl)
+	if err != nil {
+		return nil, err
+	}
+	genResult := &GenerateResult{
+		Question: q,
+		Lang:     p,
+		SubDir:   baseFilename,
+	}
+
+	separateDescriptionFile := separateDescriptionFile(p)
+	blocks := getBlocks(p)
+	modifiers, err := getModifiers(p, builtinModifiers)
+	if err != nil {
+		return nil, err
+	}
+	codeFile, err := p.generateCodeFile(q, "solution.py", blocks, modifiers, separateDescriptionFile)
+	if err != nil {
+		return nil, err
+	}
+	testcaseFile, err := p.generateTestCasesFile(q, "testcases.txt")
+	if err != nil {
+		return nil, err
+	}
+	genResult.AddFile(codeFile)
+	genResult.AddFile(testcaseFile)
+
+	if separateDescriptionFile {
+		docFile, err := p.generateDescriptionFile(q, "question.md")
+		if err != nil {
+			return nil, err
+		}
+		genResult.AddFile(docFile)
+	}
+
+	return genResult, nil
+}
@@ -20,6 +20,7 @@ const leetgoPy = "leetgo_py"
 
 var pyDeps = []string{
 	"sortedcontainers==2.4.0",
+	"pandas==2.2.3",
 	leetgoPy + "==0.2.4",
 }
 
@@ -98,19 +99,37 @@ func (p python) shouldInit(outDir string) (bool, error) {
 	return !update, nil
 }
 
-func (p python) RunLocalTest(q *leetcode.QuestionData, outDir string, targetCase string) (bool, error) {
+func (p python) buildLocalTest(q *leetcode.QuestionData, outDir string) (*GenerateResult, caseRunner, error) {
 	genResult, err := p.GeneratePaths(q)
 	if err != nil {
-		return false, err
+		return nil, nil, err
 	}
 	genResult.SetOutDir(outDir)
 
 	testFile := genResult.GetFile(TestFile).GetPath()
 	if !utils.IsExist(testFile) {
-		return false, fmt.Errorf("file %s not found", utils.RelToCwd(testFile))
+		return nil, nil, fmt.Errorf("file %s not found", utils.RelToCwd(testFile))
 	}
 	cmd := []string{path.Join(outDir, ".venv", constants.VenvPython), testFile}
-	return runTest(q, genResult, cmd, targetCase)
+	return genResult, newCommandRunner(genResult, cmd), nil
+}
+
+func (p python) RunLocalTest(q *leetcode.QuestionData, outDir string, targetCase string) (bool, error) {
+	return runLocalTest(p, q, outDir, targetCase)
+}
+
+func (p python) BuildDebug(q *leetcode.QuestionData, outDir string) (*GenerateResult, *debugTarget, error) {
+	genResult, _, err := p.buildLocalTest(q, outDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	target := &debugTarget{
+		kind:    debugPython,
+		program: path.Join(outDir, ".venv", constants.VenvPython),
+		args:    []string{genResult.GetFile(TestFile).GetPath()},
+		dir:     outDir,
+	}
+	return genResult, target, nil
 }
 
 func toPythonType(typeName string) string {
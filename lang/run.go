@@ -0,0 +1,80 @@
+package lang
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/dustin/go-humanize"
+
+	"github.com/j178/leetgo/leetcode"
+	"github.com/j178/leetgo/utils"
+)
+
+// RunResult is the result of running the solution locally on a custom input.
+type RunResult struct {
+	Output string
+	Stdout string
+	// Usage is the CPU time and memory used by the test program, empty if unknown.
+	Usage string
+}
+
+// RunLocal runs the solution locally on a custom input, the input is not judged because there is no expected output.
+func RunLocal(q *leetcode.QuestionData, input []string) (*RunResult, error) {
+	c := TestCase{Question: q, No: 1, Input: input}
+	if err := c.Check(); err != nil {
+		return nil, fmt.Errorf("invalid input: %w", err)
+	}
+	genResult, runner, err := prepareLocalTest(q)
+	if err != nil {
+		return nil, err
+	}
+	limits, err := getCaseLimits(q, genResult.Lang)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), limits.time+firstRunGrace)
+	defer cancel()
+	out, state, err := runner(ctx, c, limits)
+	output, stdout := extractOutput(out)
+	result := &RunResult{Output: output, Stdout: stdout, Usage: usageString(state)}
+
+	var startErr startError
+	switch {
+	case errors.As(err, &startErr):
+		return nil, fmt.Errorf("failed to start: %w", startErr.error)
+	case errors.Is(err, errOutputLimitExceeded):
+		return result, fmt.Errorf("output limit exceeded: %s", humanize.Bytes(limits.output))
+	case ctx.Err() != nil:
+		return result, fmt.Errorf("time limit exceeded: %s", limits.time)
+	case isOutOfMemory(limits, state, out, err):
+		return result, fmt.Errorf("memory limit exceeded: %s", humanize.Bytes(limits.memory))
+	case err != nil:
+		return result, fmt.Errorf("runtime error: %w", err)
+	}
+	if err := checkOutput(q, input, output); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// AppendTestCase appends the case to the test cases file of the question, unless it's already there.
+func AppendTestCase(q *leetcode.QuestionData, c TestCase) (bool, error) {
+	f, err := GetFileOutput(q, TestCasesFile)
+	if err != nil {
+		return false, err
+	}
+	if path := TestCasesPath(f); !utils.IsExist(path) {
+		return false, fmt.Errorf("%s not found", utils.RelToCwd(path))
+	}
+	tc, err := ParseTestCases(q, f)
+	if err != nil {
+		return false, err
+	}
+	if tc.Contains(c) {
+		return false, nil
+	}
+	tc.AddCase(c)
+	return true, tc.Save()
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"runtime"
 	"sort"
 	"strings"
 
@@ -76,25 +77,49 @@ func (r rust) InitWorkspace(outDir string) error {
 	return err
 }
 
-func (r rust) RunLocalTest(q *leetcode.QuestionData, outDir string, targetCase string) (bool, error) {
+func (r rust) buildLocalTest(q *leetcode.QuestionData, outDir string) (*GenerateResult, caseRunner, error) {
 	genResult, err := r.GeneratePaths(q)
 	if err != nil {
-		return false, fmt.Errorf("generate paths failed: %w", err)
+		return nil, nil, fmt.Errorf("generate paths failed: %w", err)
 	}
 	genResult.SetOutDir(outDir)
 
 	testFile := genResult.GetFile(TestFile).GetPath()
 	if !utils.IsExist(testFile) {
-		return false, fmt.Errorf("file %s not found", utils.RelToCwd(testFile))
+		return nil, nil, fmt.Errorf("file %s not found", utils.RelToCwd(testFile))
 	}
 
 	args := []string{"cargo", "build", "--quiet", "--bin", q.TitleSlug}
 	err = buildTest(q, genResult, args)
 	if err != nil {
-		return false, fmt.Errorf("build failed: %w", err)
+		return nil, nil, fmt.Errorf("build failed: %w", err)
 	}
 
-	return runTest(q, genResult, []string{"cargo", "run", "--quiet", "--bin", q.TitleSlug}, targetCase)
+	return genResult, newCommandRunner(genResult, []string{"cargo", "run", "--quiet", "--bin", q.TitleSlug}), nil
+}
+
+func (r rust) RunLocalTest(q *leetcode.QuestionData, outDir string, targetCase string) (bool, error) {
+	return runLocalTest(r, q, outDir, targetCase)
+}
+
+// BuildDebug reuses the build of the local test, cargo builds with the dev profile by default, which has
+// debug info and no optimizations.
+func (r rust) BuildDebug(q *leetcode.QuestionData, outDir string) (*GenerateResult, *debugTarget, error) {
+	genResult, _, err := r.buildLocalTest(q, outDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	targetDir := os.Getenv("CARGO_TARGET_DIR")
+	if targetDir == "" {
+		targetDir = filepath.Join(outDir, "target")
+	} else if !filepath.IsAbs(targetDir) {
+		targetDir = filepath.Join(outDir, targetDir)
+	}
+	execFile := filepath.Join(targetDir, "debug", q.TitleSlug)
+	if runtime.GOOS == "windows" {
+		execFile += ".exe"
+	}
+	return genResult, &debugTarget{kind: debugNative, program: execFile, dir: outDir}, nil
 }
 
 func toRustType(typeName string) string {
@@ -0,0 +1,56 @@
+package lang
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var (
+	// ==1156==ERROR: AddressSanitizer: heap-buffer-overflow on address 0x60200000001c at pc ...
+	sanitizerErrorRe = regexp.MustCompile(`(?m)^==\d+==ERROR: (\w+Sanitizer): (.+)$`)
+	// #0 0x5565e05a6573 in f(std::vector<int>&, int) /path/solution.cpp:4
+	sanitizerFrameRe = regexp.MustCompile(`(?m)^\s*#\d+ 0x[0-9a-f]+ in .* (\S+):(\d+)(?::\d+)?$`)
+	// /path/solution.cpp:8:36: runtime error: signed integer overflow: 1 + 2147483647 cannot be represented ...
+	ubsanErrorRe = regexp.MustCompile(`(?m)^(\S+):(\d+):\d+: runtime error: (.+)$`)
+)
+
+// sanitizerReport finds the report of AddressSanitizer or UndefinedBehaviorSanitizer in the output of a test
+// program, and summarizes it as e.g. "AddressSanitizer: heap-buffer-overflow at solution.cpp:12".
+// It returns "" if there is no report.
+func sanitizerReport(output string) string {
+	if m := sanitizerErrorRe.FindStringSubmatchIndex(output); m != nil {
+		name := output[m[2]:m[3]]
+		kind := output[m[4]:m[5]]
+		// Drop the addresses, e.g. "heap-buffer-overflow on address ..." or "SEGV on unknown address ...".
+		for _, sep := range []string{" on ", " at ", " (pc "} {
+			kind, _, _ = strings.Cut(kind, sep)
+		}
+		summary := name + ": " + kind
+		if loc := firstUserFrame(output[m[1]:]); loc != "" {
+			summary += " at " + loc
+		}
+		return summary
+	}
+	if m := ubsanErrorRe.FindStringSubmatch(output); m != nil {
+		// Keep the kind of undefined behavior only, e.g. "signed integer overflow".
+		kind, _, _ := strings.Cut(m[3], ": ")
+		return fmt.Sprintf("UndefinedBehaviorSanitizer: %s at %s:%s", kind, filepath.Base(m[1]), m[2])
+	}
+	return ""
+}
+
+// firstUserFrame returns the location of the first frame of the stack trace that is not in the standard library
+// or the sanitizer runtime, as "file:line".
+func firstUserFrame(trace string) string {
+	for _, m := range sanitizerFrameRe.FindAllStringSubmatch(trace, -1) {
+		file := filepath.ToSlash(m[1])
+		if strings.HasPrefix(file, "/usr/") || strings.Contains(file, "libsanitizer") ||
+			strings.Contains(file, "compiler-rt") {
+			continue
+		}
+		return filepath.Base(file) + ":" + m[2]
+	}
+	return ""
+}
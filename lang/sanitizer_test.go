@@ -0,0 +1,41 @@
+package lang
+
+import "testing"
+
+func TestSanitizerReport(t *testing.T) {
+	tests := []struct {
+		output string
+		want   string
+	}{
+		{
+			output: `=================================================================
+==1156==ERROR: AddressSanitizer: heap-buffer-overflow on address 0x60200000001c at pc 0x5565e05a6574 bp 0x7ffd66ea9dd0
+READ of size 4 at 0x60200000001c thread T0
+    #0 0x5565e05a6573 in std::vector<int, std::allocator<int> >::operator[](unsigned long) /usr/include/c++/12/bits/stl_vector.h:1123
+    #1 0x5565e05a6573 in Solution::twoSum(std::vector<int, std::allocator<int> >&, int) /leetgo/cpp/0001/solution.h:12
+    #2 0x5565e05a6573 in main /leetgo/cpp/0001/solution.cpp:30
+    #3 0x7f0048a45249  (/lib/x86_64-linux-gnu/libc.so.6+0x27249)
+`,
+			want: "AddressSanitizer: heap-buffer-overflow at solution.h:12",
+		},
+		{
+			output: "==42==ERROR: AddressSanitizer: SEGV on unknown address 0x000000000000 (pc 0x55 bp 0x7f sp 0x7f T0)\n",
+			want:   "AddressSanitizer: SEGV",
+		},
+		{
+			output: `solution.cpp:8:36: runtime error: signed integer overflow: 1 + 2147483647 cannot be represented in type 'int'
+    #0 0x557c80be439e in main /tmp/solution.cpp:8
+`,
+			want: "UndefinedBehaviorSanitizer: signed integer overflow at solution.cpp:8",
+		},
+		{
+			output: "leetgo_output: [0,1]\n",
+			want:   "",
+		},
+	}
+	for _, tt := range tests {
+		if got := sanitizerReport(tt.output); got != tt.want {
+			t.Errorf("sanitizerReport() = %q, want %q", got, tt.want)
+		}
+	}
+}
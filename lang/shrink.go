@@ -0,0 +1,299 @@
+package lang
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/log"
+
+	"github.com/j178/leetgo/leetcode"
+	goutils "github.com/j178/leetgo/testutils/go"
+)
+
+// ExpectedOutputFunc returns the expected output of an input, it tells whether a shrunk input still fails.
+type ExpectedOutputFunc func(input []string) (string, error)
+
+// Running a reference solution locally is cheap, asking LeetCode is not.
+const (
+	maxLocalShrinkProbes  = 1000
+	maxRemoteShrinkProbes = 30
+)
+
+// shrinkCandidates yields smaller variants of the serialized value of the LeetCode type, the most aggressive first.
+// It follows the idea of delta debugging: remove large chunks of arrays, strings and trees first, then smaller
+// ones, and finally shrink the elements one by one.
+func shrinkCandidates(tp string, v string) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		switch tp {
+		case "integer", "long":
+			n, err := strconv.ParseInt(v, 10, 64)
+			if err != nil || n == 0 {
+				return
+			}
+			step := int64(1)
+			if n < 0 {
+				step = -1
+			}
+			for _, m := range []int64{0, n / 2, n - step} {
+				if m != n && !yield(strconv.FormatInt(m, 10)) {
+					return
+				}
+			}
+		case "string":
+			s, err := strconv.Unquote(v)
+			if err != nil {
+				return
+			}
+			for chunk := range removeChunks([]byte(s)) {
+				if !yield(strconv.Quote(string(chunk))) {
+					return
+				}
+			}
+		case "TreeNode":
+			shrinkTree(v, yield)
+		case "ListNode":
+			for c := range shrinkCandidates("integer[]", v) {
+				if !yield(c) {
+					return
+				}
+			}
+		default:
+			elem, ok := strings.CutSuffix(tp, "[]")
+			if !ok {
+				return
+			}
+			elems, err := goutils.SplitArray(v)
+			if err != nil {
+				return
+			}
+			join := func(elems []string) string { return "[" + strings.Join(elems, ",") + "]" }
+			for rest := range removeChunks(elems) {
+				if !yield(join(rest)) {
+					return
+				}
+			}
+			for i, e := range elems {
+				for c := range shrinkCandidates(elem, e) {
+					shrunk := slices.Clone(elems)
+					shrunk[i] = c
+					if !yield(join(shrunk)) {
+						return
+					}
+				}
+			}
+		}
+	}
+}
+
+// removeChunks yields the slice with a chunk removed, from the whole slice down to a single element.
+func removeChunks[T any](s []T) iter.Seq[[]T] {
+	return func(yield func([]T) bool) {
+		for size := len(s); size > 0; size /= 2 {
+			for start := 0; start < len(s); start += size {
+				rest := slices.Concat(s[:start], s[min(start+size, len(s)):])
+				if !yield(rest) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// shrinkTree yields the tree with a subtree removed, or a node replaced by one of its children,
+// and then with a node value shrunk.
+func shrinkTree(v string, yield func(string) bool) {
+	root, err := goutils.DeserializeTreeNode(v)
+	if err != nil || root == nil {
+		return
+	}
+	// Collect the slots holding the nodes in level order, so larger subtrees are tried first.
+	slots := []**goutils.TreeNode{&root}
+	for i := 0; i < len(slots); i++ {
+		node := *slots[i]
+		if node.Left != nil {
+			slots = append(slots, &node.Left)
+		}
+		if node.Right != nil {
+			slots = append(slots, &node.Right)
+		}
+	}
+	for _, slot := range slots {
+		node := *slot
+		for i, replacement := range []*goutils.TreeNode{nil, node.Left, node.Right} {
+			// Replacing a node by a nil child is the same as removing it.
+			if i > 0 && replacement == nil {
+				continue
+			}
+			*slot = replacement
+			ok := yield(root.String())
+			*slot = node
+			if !ok {
+				return
+			}
+		}
+	}
+	for _, slot := range slots {
+		node := *slot
+		val := node.Val
+		for c := range shrinkCandidates("integer", strconv.Itoa(val)) {
+			node.Val, _ = strconv.Atoi(c)
+			ok := yield(root.String())
+			node.Val = val
+			if !ok {
+				return
+			}
+		}
+	}
+}
+
+// ShrinkTestCase minimizes a failed case of the test cases file, and appends the minimized case to the file.
+// The expected outputs of the shrunk inputs come from a local reference solution if there is one
+// (see StressTest), otherwise from remote.
+func ShrinkTestCase(q *leetcode.QuestionData, caseNo int, bruteCommand string, remote ExpectedOutputFunc) (
+	bool,
+	error,
+) {
+	genResult, runner, err := prepareLocalTest(q)
+	if err != nil {
+		return false, err
+	}
+	if q.MetaData.SystemDesign || q.MetaData.Database || q.MetaData.Shell {
+		return false, fmt.Errorf("shrinking is not supported for %s", q.TitleSlug)
+	}
+	testcaseFile := genResult.GetFile(TestCasesFile)
+	if testcaseFile == nil {
+		panic("no test cases file generated")
+	}
+	dir := filepath.Dir(testcaseFile.GetPath())
+
+	expected := remote
+	maxProbes := maxRemoteShrinkProbes
+	if brute, err := getBruteCommand(q, dir, bruteCommand); err == nil {
+		log.Info("using local reference solution", "command", strings.Join(brute, " "))
+		expected = func(input []string) (string, error) {
+			return runBrute(brute, dir, input)
+		}
+		maxProbes = maxLocalShrinkProbes
+	} else if remote == nil {
+		return false, err
+	} else {
+		log.Info("no local reference solution, using expected answers from remote")
+	}
+
+	tc, err := ParseTestCases(q, testcaseFile)
+	if err != nil {
+		return false, err
+	}
+	if caseNo < 0 {
+		caseNo += len(tc.Cases) + 1
+	}
+	if caseNo <= 0 || caseNo > len(tc.Cases) {
+		return false, fmt.Errorf("case %d not found", caseNo)
+	}
+	original := tc.Cases[caseNo-1]
+	judgers, err := getCaseJudgers(q, dir, []TestCase{original})
+	if err != nil {
+		return false, err
+	}
+	limits, err := getCaseLimits(q, genResult.Lang)
+	if err != nil {
+		return false, err
+	}
+
+	types := make([]string, 0, len(q.MetaData.Params))
+	for _, p := range q.MetaData.Params {
+		types = append(types, p.Type)
+	}
+	if original.Timeout > 0 {
+		limits.time = original.Timeout
+	}
+	timeLimit := limits.time + firstRunGrace
+	minimized, err := shrinkInput(
+		types, original.Input, maxProbes, func(input []string) (TestCase, caseResult, error) {
+			c := TestCase{Question: q, No: caseNo, Input: input, Timeout: original.Timeout, Judge: original.Judge}
+			if err := c.Check(); err != nil {
+				return c, caseResult{}, err
+			}
+			c.Output, err = expected(input)
+			if err != nil {
+				return c, caseResult{}, err
+			}
+			result := runCase(q, c, judgers[c.Judge], runner, limits, timeLimit, timeLimit)
+			timeLimit = limits.time
+			return c, result, nil
+		},
+	)
+	if err != nil {
+		return false, err
+	}
+
+	fmt.Println(minimized.report)
+	if !tc.Contains(minimized.c) {
+		minimized.c.Tags = []string{TagShrunk}
+		minimized.c.Note = fmt.Sprintf("minimized from %s", original.Label())
+		tc.AddCase(minimized.c)
+		err = tc.Save()
+		if err != nil {
+			return false, err
+		}
+		log.Info("added the minimized case to the test cases file")
+	}
+	return true, nil
+}
+
+type shrunkCase struct {
+	c      TestCase
+	report string
+}
+
+// shrinkInput greedily shrinks the parameters of the input, as long as the run still fails with the same verdict.
+// Candidates that can't be run, e.g. for violating the constraints of the question, are skipped.
+func shrinkInput(
+	types []string,
+	input []string,
+	maxProbes int,
+	run func(input []string) (TestCase, caseResult, error),
+) (shrunkCase, error) {
+	if len(types) != len(input) {
+		return shrunkCase{}, fmt.Errorf("should have %d arguments, got %d", len(types), len(input))
+	}
+	c, first, err := run(input)
+	if err != nil {
+		return shrunkCase{}, err
+	}
+	if first.passed {
+		return shrunkCase{}, errors.New("the case passed, nothing to shrink")
+	}
+	size := func(input []string) int { return len(strings.Join(input, "\n")) }
+	log.Info("shrinking", "verdict", first.verdict, "size", size(input))
+
+	minimized := shrunkCase{c: c, report: first.report}
+	probes := 0
+	for changed := true; changed && probes < maxProbes; {
+		changed = false
+		for i, tp := range types {
+			for candidate := range shrinkCandidates(tp, minimized.c.Input[i]) {
+				if probes >= maxProbes {
+					break
+				}
+				probes++
+				trial := slices.Clone(minimized.c.Input)
+				trial[i] = candidate
+				c, r, err := run(trial)
+				if err != nil || r.passed || r.verdict != first.verdict {
+					continue
+				}
+				minimized, changed = shrunkCase{c: c, report: r.report}, true
+				log.Debug("shrunk", "size", size(trial), "probes", probes)
+				break
+			}
+		}
+	}
+	log.Info("shrinking finished", "size", size(minimized.c.Input), "probes", probes)
+	return minimized, nil
+}
@@ -0,0 +1,30 @@
+package lang
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestShrinkInput(t *testing.T) {
+	// The "solution" fails when the array contains both 3 and 7, and the tree has at least 3 nodes.
+	run := func(input []string) (TestCase, caseResult, error) {
+		nodes := strings.Count(input[1], ",") + 1 - strings.Count(input[1], "null")
+		failed := strings.Contains(input[0], "3") && strings.Contains(input[0], "7") && nodes >= 3
+		return TestCase{Input: input}, caseResult{passed: !failed, verdict: "Wrong answer"}, nil
+	}
+	input := []string{"[1,5,3,9,8,2,7,4,6]", "[1,2,3,4,5,null,6]"}
+	got, err := shrinkInput([]string{"integer[]", "TreeNode"}, input, 1000, run)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"[3,7]", "[0,0,0]"}
+	if !reflect.DeepEqual(got.c.Input, want) {
+		t.Errorf("shrinkInput() = %v, want %v", got.c.Input, want)
+	}
+
+	_, err = shrinkInput([]string{"integer[]", "TreeNode"}, []string{"[1]", "[1]"}, 1000, run)
+	if err == nil {
+		t.Error("shrinkInput() should fail for a passed case")
+	}
+}
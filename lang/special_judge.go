@@ -0,0 +1,222 @@
+package lang
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/dop251/goja"
+	"github.com/google/shlex"
+
+	"github.com/j178/leetgo/config"
+	"github.com/j178/leetgo/leetcode"
+	"github.com/j178/leetgo/utils"
+)
+
+// Special judges decide the verdict for questions that accept more than one answer, like "return any valid
+// topological order". A special judge receives the input, the expected output and the actual output of a case.
+//
+// A JavaScript special judge defines a `judge` function:
+//
+//	function judge(input, expected, actual) {
+//	  // input is an array of input lines, expected and actual are the raw outputs.
+//	  return {accepted: false, message: "not a valid order"} // or simply return a boolean
+//	}
+//
+// An executable special judge is called with the paths of three files holding the input, the expected output
+// and the actual output. Exiting with 0 means accepted, otherwise what it printed is reported as the reason.
+const (
+	specialJudgeScriptFile  = "judge.js"
+	specialJudgeCommandFile = "judge"
+	specialJudgeTimeout     = 10 * time.Second
+)
+
+// jsJudger runs the `judge` function of a JavaScript special judge.
+type jsJudger struct {
+	// goja.Runtime is not goroutine safe.
+	mu sync.Mutex
+	vm *goja.Runtime
+	fn goja.Callable
+}
+
+func newJSJudger(script string) (*jsJudger, error) {
+	vm := goja.New()
+	_, err := vm.RunString(script)
+	if err != nil {
+		return nil, fmt.Errorf("failed to run judge script: %w", err)
+	}
+	fn, ok := goja.AssertFunction(vm.Get("judge"))
+	if !ok {
+		return nil, errors.New("failed to get judge function")
+	}
+	return &jsJudger{vm: vm, fn: fn}, nil
+}
+
+func (j *jsJudger) Judge(input []string, output, actualOutput string) JudgeResult {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	res, err := j.fn(goja.Undefined(), j.vm.ToValue(input), j.vm.ToValue(output), j.vm.ToValue(actualOutput))
+	if err != nil {
+		return failed(fmt.Sprintf("special judge error: %s", err))
+	}
+	switch v := res.Export().(type) {
+	case bool:
+		if v {
+			return accepted()
+		}
+		return failed("rejected by special judge")
+	case map[string]any:
+		ok, _ := v["accepted"].(bool)
+		if ok {
+			return accepted()
+		}
+		msg, _ := v["message"].(string)
+		if msg == "" {
+			msg = "rejected by special judge"
+		}
+		return failed(msg)
+	default:
+		return failed(fmt.Sprintf("special judge returned an invalid verdict: %v", res))
+	}
+}
+
+// commandJudger runs an executable special judge.
+type commandJudger struct {
+	args []string
+	dir  string
+}
+
+func (j commandJudger) Judge(input []string, output, actualOutput string) JudgeResult {
+	tmpDir, err := os.MkdirTemp("", "leetgo-judge-")
+	if err != nil {
+		return failed(fmt.Sprintf("special judge error: %s", err))
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	files := map[string]string{
+		"input":    utils.EnsureTrailingNewline(strings.Join(input, "\n")),
+		"expected": utils.EnsureTrailingNewline(output),
+		"actual":   utils.EnsureTrailingNewline(actualOutput),
+	}
+	args := append([]string{}, j.args...)
+	for _, name := range []string{"input", "expected", "actual"} {
+		path := filepath.Join(tmpDir, name+".txt")
+		if err := utils.WriteFile(path, []byte(files[name])); err != nil {
+			return failed(fmt.Sprintf("special judge error: %s", err))
+		}
+		args = append(args, path)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), specialJudgeTimeout)
+	defer cancel()
+	buf := new(strings.Builder)
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Dir = j.dir
+	cmd.Stdout = buf
+	cmd.Stderr = buf
+	err = cmd.Run()
+	msg := strings.TrimSpace(buf.String())
+
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		return accepted()
+	case ctx.Err() != nil:
+		return failed("special judge timed out")
+	case errors.As(err, &exitErr):
+		if msg == "" {
+			msg = "rejected by special judge"
+		}
+		return failed(msg)
+	default:
+		return failed(fmt.Sprintf("special judge error: %s", err))
+	}
+}
+
+// getSpecialJudger returns the special judge of the question, or nil if there is none.
+// A `judge.js` or an executable `judge` next to the test cases file takes precedence over the config.
+func getSpecialJudger(q *leetcode.QuestionData, dir string) (Judger, error) {
+	scriptFile := filepath.Join(dir, specialJudgeScriptFile)
+	if utils.IsExist(scriptFile) {
+		log.Debug("using special judge", "file", scriptFile)
+		script, err := os.ReadFile(scriptFile)
+		if err != nil {
+			return nil, err
+		}
+		return newJSJudger(string(script))
+	}
+	if commandFile := findExecutable(dir, specialJudgeCommandFile); commandFile != "" {
+		log.Debug("using special judge", "file", commandFile)
+		return commandJudger{args: []string{commandFile}, dir: dir}, nil
+	}
+
+	for _, j := range config.Get().Code.Judges {
+		if !isJudgeOf(j, q) || (j.Script == "" && j.Command == "") {
+			continue
+		}
+		log.Debug("using special judge from config", "question", j.Question)
+		if j.Script != "" {
+			return newJSJudger(j.Script)
+		}
+		args, err := shlex.Split(j.Command)
+		if err != nil || len(args) == 0 {
+			return nil, fmt.Errorf("invalid judge command: %q", j.Command)
+		}
+		return commandJudger{args: args, dir: dir}, nil
+	}
+	return nil, nil
+}
+
+// getJudgerByName returns the judge a test case chooses with its `judge` option: `exact` compares the outputs
+// as strings, `float` compares them as floating point numbers, `ignore_order` compares arrays or tables ignoring
+// the order. Any other value is run as a command, like `code.judges[].command`.
+func getJudgerByName(q *leetcode.QuestionData, dir string, name string) (Judger, error) {
+	switch name {
+	case "exact":
+		return stringJudger{}, nil
+	case "float":
+		return floatJudger{}, nil
+	case "ignore_order":
+		if q.MetaData.Database {
+			return tableJudger{ignoreOrder: true}, nil
+		}
+		tp := q.MetaData.ResultType()
+		elem, ok := strings.CutSuffix(tp, "[]")
+		if !ok || q.MetaData.SystemDesign || q.MetaData.Shell {
+			return nil, fmt.Errorf("judge ignore_order needs an array output, got %s", tp)
+		}
+		return newSliceJudger(true, getJudger(q, elem, 1)), nil
+	}
+	args, err := shlex.Split(name)
+	if err != nil || len(args) == 0 {
+		return nil, fmt.Errorf("invalid judge command: %q", name)
+	}
+	return commandJudger{args: args, dir: dir}, nil
+}
+
+func isJudgeOf(j config.Judge, q *leetcode.QuestionData) bool {
+	return j.Question == q.QuestionFrontendId || j.Question == q.TitleSlug
+}
+
+// findExecutable finds an executable with the base name, with any extension, in the directory.
+func findExecutable(dir string, base string) string {
+	matches, _ := filepath.Glob(filepath.Join(dir, base+"*"))
+	for _, m := range matches {
+		name := filepath.Base(m)
+		if name != base && !strings.HasPrefix(name, base+".") {
+			continue
+		}
+		if utils.IsExecutable(m) {
+			return m
+		}
+	}
+	return ""
+}
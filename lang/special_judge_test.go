@@ -0,0 +1,38 @@
+package lang
+
+import "testing"
+
+func TestJSJudger(t *testing.T) {
+	const script = `
+function judge(input, expected, actual) {
+  const nums = JSON.parse(input[0]).sort();
+  const got = JSON.parse(actual).sort();
+  if (JSON.stringify(nums) !== JSON.stringify(got)) {
+    return {accepted: false, message: "not a permutation"};
+  }
+  return true;
+}`
+	j, err := newJSJudger(script)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		actual string
+		want   bool
+		info   string
+	}{
+		{"[3,1,2]", true, ""},
+		{"[1,2,3]", true, ""},
+		{"[1,2,2]", false, "not a permutation"},
+	}
+	for _, tt := range tests {
+		r := j.Judge([]string{"[1,2,3]"}, "[1,2,3]", tt.actual)
+		if r.IsAccepted() != tt.want || r.GetInfo() != tt.info {
+			t.Errorf("Judge(%s) = %v %q, want %v %q", tt.actual, r.IsAccepted(), r.GetInfo(), tt.want, tt.info)
+		}
+	}
+
+	if _, err := newJSJudger("function check() {}"); err == nil {
+		t.Error("expected error for script without judge function")
+	}
+}
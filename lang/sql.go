@@ -0,0 +1,651 @@
+package lang
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"maps"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-json"
+	strip "github.com/grokify/html-strip-tags-go"
+	"zombiezen.com/go/sqlite"
+
+	"github.com/j178/leetgo/leetcode"
+	"github.com/j178/leetgo/utils"
+)
+
+// sqlLang runs database questions against an in-process SQLite database.
+// SQLite is not MySQL, but it is close enough for most of the questions. Some MySQL functions
+// that are commonly used in solutions are provided, see registerMySQLFunctions.
+type sqlLang struct {
+	baseLang
+}
+
+// sqlInput is the format of the example testcases of database questions.
+type sqlInput struct {
+	Headers map[string][]string `json:"headers"`
+	Rows    map[string][][]any  `json:"rows"`
+}
+
+// sqlTable is the format LeetCode uses for the output of database questions.
+type sqlTable struct {
+	Headers []string `json:"headers"`
+	Values  [][]any  `json:"values"`
+}
+
+func decodeJSONWithNumber(s string, v any) error {
+	dec := json.NewDecoder(strings.NewReader(s))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
+
+func parseSQLInput(s string) (*sqlInput, error) {
+	var input sqlInput
+	if err := decodeJSONWithNumber(s, &input); err != nil {
+		return nil, err
+	}
+	if len(input.Headers) == 0 {
+		return nil, errors.New("no tables found")
+	}
+	for name, rows := range input.Rows {
+		headers, ok := input.Headers[name]
+		if !ok {
+			return nil, fmt.Errorf("no headers for table %s", name)
+		}
+		for _, row := range rows {
+			if len(row) != len(headers) {
+				return nil, fmt.Errorf("table %s: expected %d columns, got %d", name, len(headers), len(row))
+			}
+		}
+	}
+	return &input, nil
+}
+
+func parseSQLTable(s string) (*sqlTable, error) {
+	var table sqlTable
+	if err := decodeJSONWithNumber(s, &table); err != nil {
+		return nil, err
+	}
+	if table.Headers == nil {
+		return nil, errors.New("no headers found")
+	}
+	for _, row := range table.Values {
+		if len(row) != len(table.Headers) {
+			return nil, fmt.Errorf("expected %d columns, got %d", len(table.Headers), len(row))
+		}
+	}
+	return &table, nil
+}
+
+func (t *sqlTable) String() string {
+	values := t.Values
+	if values == nil {
+		values = [][]any{}
+	}
+	b, _ := json.Marshal(sqlTable{Headers: t.Headers, Values: values})
+	return string(b)
+}
+
+// asciiTable is a table drawn in the question content, like:
+//
+//	+-------------+---------+
+//	| Column Name | Type    |
+//	+-------------+---------+
+//	| id          | int     |
+//	+-------------+---------+
+type asciiTable struct {
+	headers []string
+	rows    [][]string
+}
+
+func splitASCIITableRow(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	line = strings.TrimSuffix(line, "|")
+	cells := strings.Split(line, "|")
+	for i, c := range cells {
+		cells[i] = strings.TrimSpace(c)
+	}
+	return cells
+}
+
+// parseASCIITable parses the table starting at lines[0], returns the table and the number of lines consumed.
+func parseASCIITable(lines []string) (*asciiTable, int) {
+	isBorder := func(line string) bool {
+		line = strings.TrimSpace(line)
+		return strings.HasPrefix(line, "+") && strings.Trim(line, "+-") == ""
+	}
+	isRow := func(line string) bool {
+		return strings.HasPrefix(strings.TrimSpace(line), "|")
+	}
+	if len(lines) < 3 || !isBorder(lines[0]) || !isRow(lines[1]) || !isBorder(lines[2]) {
+		return nil, 0
+	}
+	t := &asciiTable{headers: splitASCIITableRow(lines[1])}
+	i := 3
+	for ; i < len(lines) && isRow(lines[i]); i++ {
+		t.rows = append(t.rows, splitASCIITableRow(lines[i]))
+	}
+	// Skip the bottom border, which is missing when the table is empty.
+	if i < len(lines) && isBorder(lines[i]) {
+		i++
+	}
+	return t, i
+}
+
+func contentLines(q *leetcode.QuestionData) []string {
+	content := q.Content
+	if content == "" {
+		content = q.TranslatedContent
+	}
+	content = html.UnescapeString(strip.StripTags(content))
+	return utils.SplitLines(content)
+}
+
+var (
+	sqlTableNameRe = regexp.MustCompile("^(?:Table|表)\\s*[:：]\\s*`?(\\w+)`?")
+	sqlOutputRe    = regexp.MustCompile(`^(?:Output|输出)\s*[:：]`)
+)
+
+// parseSQLSchema parses the column types of tables described in the question content, like:
+//
+//	Table: Person
+//	+-------------+---------+
+//	| Column Name | Type    |
+//	...
+func parseSQLSchema(q *leetcode.QuestionData) map[string]map[string]string {
+	schema := map[string]map[string]string{}
+	lines := contentLines(q)
+	tableName := ""
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if m := sqlTableNameRe.FindStringSubmatch(line); m != nil {
+			tableName = m[1]
+			continue
+		}
+		if tableName == "" {
+			continue
+		}
+		t, n := parseASCIITable(lines[i:])
+		if t == nil || len(t.headers) != 2 {
+			continue
+		}
+		columns := map[string]string{}
+		for _, row := range t.rows {
+			if len(row) == 2 {
+				columns[row[0]] = strings.ToLower(row[1])
+			}
+		}
+		schema[tableName] = columns
+		tableName = ""
+		i += n - 1
+	}
+	return schema
+}
+
+// parseSQLExampleOutputs parses the output tables of examples in the question content.
+func parseSQLExampleOutputs(q *leetcode.QuestionData) []string {
+	var outputs []string
+	lines := contentLines(q)
+	for i := 0; i < len(lines); i++ {
+		if !sqlOutputRe.MatchString(strings.TrimSpace(lines[i])) {
+			continue
+		}
+		// The table may follow "Output:" on the same line or on the next lines.
+		j := i + 1
+		for j < len(lines) && strings.TrimSpace(lines[j]) == "" {
+			j++
+		}
+		t, n := parseASCIITable(lines[j:])
+		if t == nil {
+			continue
+		}
+		table := sqlTable{Headers: t.headers, Values: [][]any{}}
+		for _, row := range t.rows {
+			values := make([]any, len(row))
+			for k, cell := range row {
+				values[k] = asciiCellValue(cell)
+			}
+			table.Values = append(table.Values, values)
+		}
+		outputs = append(outputs, table.String())
+		i = j + n - 1
+	}
+	return outputs
+}
+
+func asciiCellValue(cell string) any {
+	if strings.EqualFold(cell, "null") {
+		return nil
+	}
+	if _, err := strconv.ParseFloat(cell, 64); err == nil {
+		return json.Number(cell)
+	}
+	return cell
+}
+
+// sqliteColumnType maps a MySQL column type to a SQLite column type, to get the right type affinity.
+func sqliteColumnType(mysqlType string) string {
+	switch {
+	case strings.Contains(mysqlType, "int"):
+		return "INTEGER"
+	case strings.Contains(mysqlType, "decimal"),
+		strings.Contains(mysqlType, "float"),
+		strings.Contains(mysqlType, "double"),
+		strings.Contains(mysqlType, "numeric"):
+		return "REAL"
+	case mysqlType == "":
+		return ""
+	default:
+		return "TEXT"
+	}
+}
+
+// sqlTypeOfValues guesses the column type from the values, for tables not described in the content.
+func sqlTypeOfValues(rows [][]any, col int) string {
+	for _, row := range rows {
+		switch row[col].(type) {
+		case json.Number, bool:
+			return "NUMERIC"
+		case string:
+			return "TEXT"
+		}
+	}
+	return ""
+}
+
+func quoteSQLIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+func bindSQLValue(stmt *sqlite.Stmt, param int, v any) {
+	switch v := v.(type) {
+	case nil:
+		stmt.BindNull(param)
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			stmt.BindInt64(param, i)
+		} else {
+			f, _ := v.Float64()
+			stmt.BindFloat(param, f)
+		}
+	case bool:
+		stmt.BindBool(param, v)
+	case string:
+		stmt.BindText(param, v)
+	default:
+		stmt.BindText(param, fmt.Sprint(v))
+	}
+}
+
+func execSQL(conn *sqlite.Conn, query string, args ...any) error {
+	stmt, _, err := conn.PrepareTransient(query)
+	if err != nil {
+		return err
+	}
+	defer stmt.Finalize()
+	for i, arg := range args {
+		bindSQLValue(stmt, i+1, arg)
+	}
+	_, err = stmt.Step()
+	return err
+}
+
+func loadSQLTables(conn *sqlite.Conn, input *sqlInput, schema map[string]map[string]string) error {
+	for _, name := range slices.Sorted(maps.Keys(input.Headers)) {
+		headers := input.Headers[name]
+		rows := input.Rows[name]
+		columns := make([]string, len(headers))
+		for i, col := range headers {
+			tp := sqliteColumnType(schema[name][col])
+			if tp == "" {
+				tp = sqlTypeOfValues(rows, i)
+			}
+			columns[i] = strings.TrimSpace(quoteSQLIdent(col) + " " + tp)
+		}
+		err := execSQL(conn, fmt.Sprintf("CREATE TABLE %s (%s)", quoteSQLIdent(name), strings.Join(columns, ", ")))
+		if err != nil {
+			return fmt.Errorf("create table %s: %w", name, err)
+		}
+		if len(headers) == 0 {
+			continue
+		}
+		insert := fmt.Sprintf(
+			"INSERT INTO %s VALUES (%s)",
+			quoteSQLIdent(name),
+			strings.TrimSuffix(strings.Repeat("?,", len(headers)), ","),
+		)
+		for _, row := range rows {
+			if err := execSQL(conn, insert, row...); err != nil {
+				return fmt.Errorf("insert into %s: %w", name, err)
+			}
+		}
+	}
+	return nil
+}
+
+func queryTable(stmt *sqlite.Stmt) (*sqlTable, error) {
+	table := &sqlTable{Values: [][]any{}}
+	for i := 0; i < stmt.ColumnCount(); i++ {
+		table.Headers = append(table.Headers, stmt.ColumnName(i))
+	}
+	for {
+		hasRow, err := stmt.Step()
+		if err != nil {
+			return nil, err
+		}
+		if !hasRow {
+			return table, nil
+		}
+		row := make([]any, stmt.ColumnCount())
+		for i := range row {
+			switch stmt.ColumnType(i) {
+			case sqlite.TypeNull:
+				row[i] = nil
+			case sqlite.TypeInteger:
+				row[i] = stmt.ColumnInt64(i)
+			case sqlite.TypeFloat:
+				row[i] = stmt.ColumnFloat(i)
+			default:
+				row[i] = stmt.ColumnText(i)
+			}
+		}
+		table.Values = append(table.Values, row)
+	}
+}
+
+// stripSQLComments removes comments from the query, SQLite doesn't support MySQL's `#` comments.
+func stripSQLComments(query string) string {
+	var sb strings.Builder
+	var quote byte
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			} else if c == '\\' && i+1 < len(query) {
+				sb.WriteByte(c)
+				i++
+				c = query[i]
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '#' || (c == '-' && strings.HasPrefix(query[i:], "--")):
+			for i < len(query) && query[i] != '\n' {
+				i++
+			}
+			c = '\n'
+		case c == '/' && strings.HasPrefix(query[i:], "/*"):
+			end := strings.Index(query[i+2:], "*/")
+			if end < 0 {
+				return sb.String()
+			}
+			i += end + 3
+			c = ' '
+		}
+		sb.WriteByte(c)
+	}
+	return sb.String()
+}
+
+// runSQL runs the statements of the query, returns the result of the last statement that returns rows.
+// For questions that modify tables instead, the modified table with the same columns as the expected output
+// is returned.
+func runSQL(ctx context.Context, c TestCase, query string) (*sqlTable, error) {
+	input, err := parseSQLInput(c.Input[0])
+	if err != nil {
+		return nil, err
+	}
+	conn, err := sqlite.OpenConn(":memory:")
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	conn.SetInterrupt(ctx.Done())
+
+	if err := registerMySQLFunctions(conn); err != nil {
+		return nil, err
+	}
+	if err := loadSQLTables(conn, input, parseSQLSchema(c.Question)); err != nil {
+		return nil, err
+	}
+
+	var result *sqlTable
+	query = strings.Trim(stripSQLComments(query), "; \t\r\n")
+	for query != "" {
+		stmt, trailingBytes, err := conn.PrepareTransient(query)
+		if err != nil {
+			return nil, err
+		}
+		query = strings.Trim(query[len(query)-trailingBytes:], "; \t\r\n")
+		if stmt.ColumnCount() > 0 {
+			result, err = queryTable(stmt)
+		} else {
+			_, err = stmt.Step()
+		}
+		stmt.Finalize()
+		if err != nil {
+			return nil, err
+		}
+	}
+	if result != nil {
+		return result, nil
+	}
+
+	expected, err := parseSQLTable(c.Output)
+	if err != nil {
+		return nil, errors.New("the query returns nothing")
+	}
+	for name, headers := range input.Headers {
+		if slices.EqualFunc(headers, expected.Headers, strings.EqualFold) {
+			stmt, _, err := conn.PrepareTransient("SELECT * FROM " + quoteSQLIdent(name))
+			if err != nil {
+				return nil, err
+			}
+			result, err = queryTable(stmt)
+			stmt.Finalize()
+			return result, err
+		}
+	}
+	return nil, errors.New("the query returns nothing")
+}
+
+func parseSQLDate(v sqlite.Value) (time.Time, bool) {
+	s := v.Text()
+	if len(s) < len(time.DateOnly) {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(time.DateOnly, s[:len(time.DateOnly)])
+	return t, err == nil
+}
+
+// registerMySQLFunctions registers the MySQL date functions that SQLite lacks.
+func registerMySQLFunctions(conn *sqlite.Conn) error {
+	datePart := func(part func(t time.Time) int) *sqlite.FunctionImpl {
+		return &sqlite.FunctionImpl{
+			NArgs:         1,
+			Deterministic: true,
+			Scalar: func(_ sqlite.Context, args []sqlite.Value) (sqlite.Value, error) {
+				t, ok := parseSQLDate(args[0])
+				if !ok {
+					return sqlite.Value{}, nil
+				}
+				return sqlite.IntegerValue(int64(part(t))), nil
+			},
+		}
+	}
+	funcs := map[string]*sqlite.FunctionImpl{
+		"year":  datePart(func(t time.Time) int { return t.Year() }),
+		"month": datePart(func(t time.Time) int { return int(t.Month()) }),
+		"day":   datePart(func(t time.Time) int { return t.Day() }),
+		"datediff": {
+			NArgs:         2,
+			Deterministic: true,
+			Scalar: func(_ sqlite.Context, args []sqlite.Value) (sqlite.Value, error) {
+				t1, ok1 := parseSQLDate(args[0])
+				t2, ok2 := parseSQLDate(args[1])
+				if !ok1 || !ok2 {
+					return sqlite.Value{}, nil
+				}
+				return sqlite.IntegerValue(int64(t1.Sub(t2).Hours() / 24)), nil
+			},
+		},
+	}
+	for name, impl := range funcs {
+		if err := conn.CreateFunction(name, impl); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// generateTableTestCasesContent generates testcases of database questions, the expected outputs are parsed
+// from the examples in the question content.
+func generateTableTestCasesContent(q *leetcode.QuestionData) string {
+	inputs := q.GetExampleTestCases()
+	outputs := parseSQLExampleOutputs(q)
+	tc := TestCases{Question: q}
+	for i, input := range inputs {
+		output := ""
+		if i < len(outputs) {
+			output = outputs[i]
+		}
+		tc.AddCase(
+			TestCase{
+				Input:  []string{input},
+				Output: output,
+			},
+		)
+	}
+	return tc.String()
+}
+
+func (s sqlLang) generateTestCasesFile(q *leetcode.QuestionData, filename string) (FileOutput, error) {
+	return FileOutput{
+		Filename: filename,
+		Content:  generateTableTestCasesContent(q),
+		Type:     TestCasesFile,
+	}, nil
+}
+
+func (s sqlLang) buildLocalTest(q *leetcode.QuestionData, outDir string) (*GenerateResult, caseRunner, error) {
+	if !q.MetaData.Database {
+		return nil, nil, fmt.Errorf("%s is not a database question", q.TitleSlug)
+	}
+	genResult, err := s.GeneratePaths(q)
+	if err != nil {
+		return nil, nil, fmt.Errorf("generate paths failed: %w", err)
+	}
+	genResult.SetOutDir(outDir)
+
+	codeFile := genResult.GetFile(CodeFile)
+	if !utils.IsExist(codeFile.GetPath()) {
+		return nil, nil, fmt.Errorf("file %s not found", utils.RelToCwd(codeFile.GetPath()))
+	}
+	query, err := getSolutionCodeFromFile(codeFile)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	runner := func(ctx context.Context, c TestCase, limits caseLimits) (string, *os.ProcessState, error) {
+		// The query runs in process, only the output limit applies.
+		result, err := runSQL(ctx, c, query)
+		if err != nil {
+			return err.Error(), nil, err
+		}
+		output := fmt.Sprintf("\n%s %s\n", testCaseOutputMark, result)
+		if limits.output > 0 && uint64(len(output)) > limits.output {
+			return "", nil, errOutputLimitExceeded
+		}
+		return output, nil, nil
+	}
+	return genResult, runner, nil
+}
+
+func (s sqlLang) RunLocalTest(q *leetcode.QuestionData, outDir string, targetCase string) (bool, error) {
+	return runLocalTest(s, q, outDir, targetCase)
+}
+
+func (s sqlLang) Generate(q *leetcode.QuestionData) (*GenerateResult, error) {
+	filenameTmpl := getFilenameTemplate(q, s)
+	baseFilename, err := q.GetFormattedFilename(s.slug, filenameTmpl)
+	if err != nil {
+		return nil, err
+	}
+	genResult := &GenerateResult{
+		Question: q,
+		Lang:     s,
+		SubDir:   baseFilename,
+	}
+
+	separateDescriptionFile := separateDescriptionFile(s)
+	blocks := getBlocks(s)
+	modifiers, err := getModifiers(s, builtinModifiers)
+	if err != nil {
+		return nil, err
+	}
+	codeFile, err := s.generateCodeFile(q, "solution.sql", blocks, modifiers, separateDescriptionFile)
+	if err != nil {
+		return nil, err
+	}
+	genResult.AddFile(codeFile)
+	if q.MetaData.Database {
+		testcaseFile, err := s.generateTestCasesFile(q, "testcases.txt")
+		if err != nil {
+			return nil, err
+		}
+		genResult.AddFile(testcaseFile)
+	}
+
+	if separateDescriptionFile {
+		docFile, err := s.generateDescriptionFile(q, "question.md")
+		if err != nil {
+			return nil, err
+		}
+		genResult.AddFile(docFile)
+	}
+
+	return genResult, nil
+}
+
+func (s sqlLang) GeneratePaths(q *leetcode.QuestionData) (*GenerateResult, error) {
+	filenameTmpl := getFilenameTemplate(q, s)
+	baseFilename, err := q.GetFormattedFilename(s.slug, filenameTmpl)
+	if err != nil {
+		return nil, err
+	}
+	genResult := &GenerateResult{
+		SubDir:   baseFilename,
+		Question: q,
+		Lang:     s,
+	}
+	genResult.AddFile(
+		FileOutput{
+			Filename: "solution.sql",
+			Type:     CodeFile,
+		},
+	)
+	genResult.AddFile(
+		FileOutput{
+			Filename: "testcases.txt",
+			Type:     TestCasesFile,
+		},
+	)
+	if separateDescriptionFile(s) {
+		genResult.AddFile(
+			FileOutput{
+				Filename: "question.md",
+				Type:     DocFile,
+			},
+		)
+	}
+	return genResult, nil
+}
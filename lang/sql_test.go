@@ -0,0 +1,45 @@
+package lang
+
+import "testing"
+
+func TestStripSQLComments(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"select 1", "select 1"},
+		{"# comment\nselect 1", "\nselect 1"},
+		{"select 1 -- comment", "select 1 \n"},
+		{"select /* a */ 1", "select   1"},
+		{"select '#not comment'", "select '#not comment'"},
+		{`select "it\"s -- not comment"`, `select "it\"s -- not comment"`},
+	}
+	for _, tt := range tests {
+		if got := stripSQLComments(tt.query); got != tt.want {
+			t.Errorf("stripSQLComments(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestTableJudger(t *testing.T) {
+	expected := `{"headers":["id","name"],"values":[[1,"a"],[2,null]]}`
+	tests := []struct {
+		actual      string
+		ignoreOrder bool
+		want        bool
+	}{
+		{`{"headers":["id","name"],"values":[[1,"a"],[2,null]]}`, false, true},
+		{`{"headers":["ID","Name"],"values":[[1.0,"a"],[2,null]]}`, false, true},
+		{`{"headers":["id","name"],"values":[[1,"a"],[2,"Null"]]}`, false, false},
+		{`{"headers":["id","name"],"values":[[2,null],[1,"a"]]}`, false, false},
+		{`{"headers":["id","name"],"values":[[2,null],[1,"a"]]}`, true, true},
+		{`{"headers":["id"],"values":[[1],[2]]}`, true, false},
+		{`{"headers":["id","name"],"values":[[1,"a"]]}`, true, false},
+	}
+	for _, tt := range tests {
+		j := tableJudger{ignoreOrder: tt.ignoreOrder}
+		if got := j.Judge(nil, expected, tt.actual).IsAccepted(); got != tt.want {
+			t.Errorf("tableJudger{%v}.Judge(%s) = %v, want %v", tt.ignoreOrder, tt.actual, got, tt.want)
+		}
+	}
+}
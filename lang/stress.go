@@ -0,0 +1,221 @@
+package lang
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/google/shlex"
+
+	"github.com/j178/leetgo/config"
+	"github.com/j178/leetgo/leetcode"
+	"github.com/j178/leetgo/utils"
+)
+
+// A brute-force solution of a stress test is a command that reads the input of a case from stdin, one argument
+// per line just like testcases.txt, and prints the output. If it prints a line starting with `output:`,
+// only that line is taken as the output, like the test programs of local tests.
+const (
+	stressBruteFile    = "brute"
+	stressBruteTimeout = 10 * time.Second
+)
+
+func getStressTestConfig(q *leetcode.QuestionData) config.StressTest {
+	for _, t := range config.Get().Code.Stress.Questions {
+		if t.Question == q.QuestionFrontendId || t.Question == q.TitleSlug {
+			return t
+		}
+	}
+	return config.StressTest{}
+}
+
+// getBruteCommand returns the command of the brute-force solution: the given one, the configured one,
+// or an executable `brute` next to the test cases file.
+func getBruteCommand(q *leetcode.QuestionData, dir string, command string) ([]string, error) {
+	if command == "" {
+		command = getStressTestConfig(q).Brute
+	}
+	if command != "" {
+		args, err := shlex.Split(command)
+		if err != nil || len(args) == 0 {
+			return nil, fmt.Errorf("invalid brute-force command: %q", command)
+		}
+		return args, nil
+	}
+	if file := findExecutable(dir, stressBruteFile); file != "" {
+		return []string{file}, nil
+	}
+	return nil, fmt.Errorf(
+		"no brute-force solution found, put an executable %s (e.g. %s.py) in %s or set `code.stress`",
+		stressBruteFile,
+		stressBruteFile,
+		utils.RelToCwd(dir),
+	)
+}
+
+// runBrute runs the brute-force solution on the input and returns its output.
+func runBrute(args []string, dir string, input []string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), stressBruteTimeout)
+	defer cancel()
+	stdout := new(strings.Builder)
+	stderr := new(strings.Builder)
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Dir = dir
+	cmd.Stdin = strings.NewReader(utils.EnsureTrailingNewline(strings.Join(input, "\n")))
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	err := cmd.Run()
+	switch {
+	case ctx.Err() != nil:
+		return "", errors.New("brute-force solution timed out")
+	case err != nil:
+		return "", fmt.Errorf("brute-force solution failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	if output, _ := extractOutput(stdout.String()); output != "" {
+		return output, nil
+	}
+	output := strings.TrimSpace(stdout.String())
+	if output == "" {
+		return "", errors.New("brute-force solution printed nothing")
+	}
+	return output, nil
+}
+
+// getStressConstraints resolves the constraints of all parameters of the question.
+func getStressConstraints(q *leetcode.QuestionData) map[string]paramConstraint {
+	configured := getStressTestConfig(q).Params
+	seeded := parseConstraints(q)
+	constraints := make(map[string]paramConstraint, len(q.MetaData.Params))
+	for _, p := range q.MetaData.Params {
+		constraints[p.Name] = resolveConstraint(configured[p.Name], seeded[p.Name])
+		log.Debug("stress constraint", "param", p.Name, "constraint", fmt.Sprintf("%+v", constraints[p.Name]))
+	}
+	return constraints
+}
+
+// StressTest compares the solution with a brute-force solution on random inputs of the question.
+// It stops at the first mismatch, shrinks the input with the brute-force solution as the reference, and appends
+// the minimized case to the test cases file, with the output of the brute-force solution as the expected output.
+func StressTest(q *leetcode.QuestionData, rounds int, bruteCommand string) (bool, error) {
+	if rounds <= 0 {
+		rounds = config.Get().Code.Stress.Rounds
+	}
+	genResult, runner, err := prepareLocalTest(q)
+	if err != nil {
+		return false, err
+	}
+	return runStressTest(q, genResult, runner, rounds, bruteCommand)
+}
+
+func runStressTest(
+	q *leetcode.QuestionData,
+	genResult *GenerateResult,
+	runner caseRunner,
+	rounds int,
+	bruteCommand string,
+) (bool, error) {
+	if q.MetaData.SystemDesign || q.MetaData.Database || q.MetaData.Shell {
+		return false, fmt.Errorf("stress test is not supported for %s", q.TitleSlug)
+	}
+	testcaseFile := genResult.GetFile(TestCasesFile)
+	if testcaseFile == nil {
+		panic("no test cases file generated")
+	}
+	dir := filepath.Dir(testcaseFile.GetPath())
+	brute, err := getBruteCommand(q, dir, bruteCommand)
+	if err != nil {
+		return false, err
+	}
+	judger, err := getCaseJudger(q, dir)
+	if err != nil {
+		return false, err
+	}
+	limits, err := getCaseLimits(q, genResult.Lang)
+	if err != nil {
+		return false, err
+	}
+
+	constraints := getStressConstraints(q)
+	types := make([]string, 0, len(q.MetaData.Params))
+	for _, p := range q.MetaData.Params {
+		types = append(types, p.Type)
+	}
+	seed := uint64(time.Now().UnixNano())
+	log.Info("running stress test", "question", q.TitleSlug, "rounds", rounds, "brute", strings.Join(brute, " "))
+	log.Debug("stress test", "seed", seed)
+	gen := &inputGenerator{rnd: rand.New(rand.NewPCG(seed, seed))}
+
+	for round := range rounds {
+		gen.scale = float64(round+1) / float64(rounds)
+		input, err := gen.generateInput(q, constraints)
+		if err != nil {
+			return false, err
+		}
+		c := TestCase{Question: q, No: round + 1, Input: input}
+		if err := c.Check(); err != nil {
+			return false, fmt.Errorf("generated an invalid input %q: %w", c.InputString(), err)
+		}
+		c.Output, err = runBrute(brute, dir, input)
+		if err != nil {
+			return false, fmt.Errorf("%w, input: %s", err, strings.Join(input, " "))
+		}
+
+		timeLimit := limits.time
+		if round == 0 {
+			timeLimit += firstRunGrace
+		}
+		result := runCase(q, c, judger, runner, limits, timeLimit, timeLimit)
+		if result.passed {
+			continue
+		}
+
+		// The larger inputs come last, shrink the failed one to a case small enough to debug.
+		failed := shrunkCase{c: c, report: result.report}
+		minimized, err := shrinkInput(
+			types, input, maxLocalShrinkProbes, func(input []string) (TestCase, caseResult, error) {
+				c := TestCase{Question: q, No: round + 1, Input: input}
+				if err := c.Check(); err != nil {
+					return c, caseResult{}, err
+				}
+				c.Output, err = runBrute(brute, dir, input)
+				if err != nil {
+					return c, caseResult{}, err
+				}
+				return c, runCase(q, c, judger, runner, limits, limits.time, limits.time), nil
+			},
+		)
+		if err != nil {
+			log.Warn("failed to shrink the failed case, saving it as it is", "err", err)
+		} else {
+			failed = minimized
+			failed.c.Tags = []string{TagShrunk}
+			failed.c.Note = fmt.Sprintf("minimized from the input of round %d", round+1)
+		}
+		c = failed.c
+
+		fmt.Println(failed.report)
+		tc, err := ParseTestCases(q, testcaseFile)
+		if err != nil {
+			return false, err
+		}
+		if !tc.Contains(c) {
+			c.Tags = append([]string{TagStress}, c.Tags...)
+			tc.AddCase(c)
+			err = tc.Save()
+			if err != nil {
+				return false, err
+			}
+			log.Info("added the failed case to the test cases file", "round", round+1)
+		}
+		return false, nil
+	}
+
+	log.Info("no mismatch found", "rounds", rounds)
+	return true, nil
+}
@@ -0,0 +1,64 @@
+package lang
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/j178/leetgo/leetcode"
+)
+
+func TestStressTestShrinksFailedCase(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the brute-force solution is a shell script")
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "brute"), []byte("#!/bin/sh\necho 0\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	testcasesFile := filepath.Join(dir, "testcases.txt")
+	if err := os.WriteFile(testcasesFile, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	q := testQuestion()
+	q.Content = `<p><strong>Constraints:</strong></p>
+<ul>
+	<li><code>50 &lt;= nums.length &lt;= 100</code></li>
+	<li><code>1 &lt;= nums[i] &lt;= 1000</code></li>
+	<li><code>1 &lt;= target &lt;= 1000</code></li>
+</ul>`
+	q.MetaData.Return = &leetcode.MetaDataReturn{Type: "integer"}
+	genResult := &GenerateResult{Question: q, Lang: golangGen}
+	genResult.AddFile(FileOutput{Filename: "testcases.txt", Type: TestCasesFile})
+	genResult.SetOutDir(dir)
+	// The "solution" is wrong whenever nums is not empty.
+	runner := func(_ context.Context, c TestCase, _ caseLimits) (string, *os.ProcessState, error) {
+		if c.Input[0] == "[]" {
+			return testCaseOutputMark + " 0\n", nil, nil
+		}
+		return testCaseOutputMark + " 1\n", nil, nil
+	}
+
+	// A single round generates the largest input.
+	passed, err := runStressTest(q, genResult, runner, 1, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if passed {
+		t.Fatal("stress test passed")
+	}
+	tc, err := ParseTestCases(q, genResult.GetFile(TestCasesFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tc.Cases) != 1 {
+		t.Fatalf("%d cases saved, want 1", len(tc.Cases))
+	}
+	if got := strings.Join(tc.Cases[0].Input, " "); got != "[0] 0" {
+		t.Errorf("saved input = %q, want the minimized %q", got, "[0] 0")
+	}
+}
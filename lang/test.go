@@ -2,41 +2,109 @@ package lang
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"reflect"
+	"runtime"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/log"
+	"github.com/dustin/go-humanize"
 	"github.com/jedib0t/go-pretty/v6/list"
 
 	"github.com/j178/leetgo/config"
 	"github.com/j178/leetgo/leetcode"
+	"github.com/j178/leetgo/report"
 	goutils "github.com/j178/leetgo/testutils/go"
 	"github.com/j178/leetgo/utils"
 )
 
-func RunLocalTest(q *leetcode.QuestionData, targetCase string) (bool, error) {
-	cfg := config.Get()
-	gen, err := GetGenerator(cfg.Code.Lang)
+// RunLocalTest runs the test cases selected by targetCase locally, prints the results to out,
+// and returns them as a report suite.
+func RunLocalTest(q *leetcode.QuestionData, targetCase string, out io.Writer) (*report.Suite, error) {
+	gen, outDir, err := localTestTarget(q)
+	if err != nil {
+		return nil, err
+	}
+	builder, ok := gen.(localTestBuilder)
+	if !ok {
+		// Languages implementing only LocalTestable print their own results, and report the outcome as a whole.
+		passed, err := gen.(LocalTestable).RunLocalTest(q, outDir, targetCase)
+		if err != nil {
+			return nil, err
+		}
+		verdict := "Accepted"
+		if !passed {
+			verdict = "Wrong Answer"
+		}
+		return &report.Suite{
+			Question: q.TitleSlug,
+			Kind:     report.LocalTest,
+			Cases:    []report.Case{{Name: "All", Verdict: verdict, Passed: passed}},
+		}, nil
+	}
+	genResult, runner, err := builder.buildLocalTest(q, outDir)
+	if err != nil {
+		return nil, err
+	}
+	return runTestCases(q, genResult, targetCase, runner, out)
+}
+
+// runLocalTest implements LocalTestable for the builtin languages, printing the results to stdout.
+func runLocalTest(b localTestBuilder, q *leetcode.QuestionData, outDir string, targetCase string) (bool, error) {
+	genResult, runner, err := b.buildLocalTest(q, outDir)
+	if err != nil {
+		return false, err
+	}
+	suite, err := runTestCases(q, genResult, targetCase, runner, os.Stdout)
 	if err != nil {
 		return false, err
 	}
-	tester, ok := gen.(LocalTestable)
+	return suite.Passed(), nil
+}
+
+// prepareLocalTest builds the test program of the question in the configured language.
+func prepareLocalTest(q *leetcode.QuestionData) (*GenerateResult, caseRunner, error) {
+	gen, outDir, err := localTestTarget(q)
+	if err != nil {
+		return nil, nil, err
+	}
+	builder, ok := gen.(localTestBuilder)
 	if !ok {
-		return false, fmt.Errorf("language %s does not support local test", gen.Slug())
+		return nil, nil, fmt.Errorf("language %s does not support running single test cases", gen.Slug())
+	}
+	return builder.buildLocalTest(q, outDir)
+}
+
+// localTestTarget returns the generator of the configured language and the directory of the generated code,
+// checking that the language supports local test.
+func localTestTarget(q *leetcode.QuestionData) (Lang, string, error) {
+	cfg := config.Get()
+	gen, err := GetGenerator(cfg.Code.Lang)
+	if err != nil {
+		return nil, "", err
+	}
+	if _, ok := gen.(LocalTestable); !ok {
+		return nil, "", fmt.Errorf("language %s does not support local test", gen.Slug())
 	}
 	err = q.Fulfill()
 	if err != nil {
-		return false, fmt.Errorf("failed to get question data: %w", err)
+		return nil, "", fmt.Errorf("failed to get question data: %w", err)
 	}
 	outDir := getOutDir(q, gen)
 	if !utils.IsExist(outDir) {
-		return false, fmt.Errorf("no code generated for %s in language %s", q.TitleSlug, gen.Slug())
+		return nil, "", fmt.Errorf("no code generated for %s in language %s", q.TitleSlug, gen.Slug())
 	}
-
-	return tester.RunLocalTest(q, outDir, targetCase)
+	return gen, outDir, nil
 }
 
 // typeNameToType converts a Go type name to reflect.Type.
@@ -117,6 +185,18 @@ func checkOutput(q *leetcode.QuestionData, input []string, outputLine string) er
 		}
 		return nil
 	}
+	if q.MetaData.Database {
+		if _, err := parseSQLTable(outputLine); err != nil {
+			return fmt.Errorf("invalid output: %s", outputLine)
+		}
+		return nil
+	}
+	if q.MetaData.Shell {
+		if _, err := strconv.Unquote(outputLine); err != nil {
+			return fmt.Errorf("invalid output: %s", outputLine)
+		}
+		return nil
+	}
 	tp := q.MetaData.ResultType()
 	_, err := deserialize(tp, outputLine)
 	if err != nil {
@@ -143,145 +223,396 @@ func buildTest(_ *leetcode.QuestionData, genResult *GenerateResult, args []strin
 	}
 	err := cmd.Run()
 	if err != nil {
-		fmt.Println(config.StdoutStyle.Render(strings.TrimSuffix(buf.String(), "\n")))
+		// Build errors go to stderr, so they don't mix with a report on stdout.
+		fmt.Fprintln(os.Stderr, config.StdoutStyle.Render(strings.TrimSuffix(buf.String(), "\n")))
 		return err
 	}
 	return nil
 }
 
-func runTest(q *leetcode.QuestionData, genResult *GenerateResult, args []string, targetCaseStr string) (bool, error) {
+// caseRunner runs a single test case within the limits and returns everything the solution printed.
+// The state of the exited test program is returned if the case is run in a separate process.
+type caseRunner func(ctx context.Context, c TestCase, limits caseLimits) (string, *os.ProcessState, error)
+
+// startError is returned by a caseRunner when the test program could not be started.
+type startError struct {
+	error
+}
+
+// newCommandRunner returns a runner that feeds each case to the test program through stdin.
+func newCommandRunner(genResult *GenerateResult, args []string) caseRunner {
+	return newEnvCommandRunner(genResult, args, nil)
+}
+
+// newEnvCommandRunner is like newCommandRunner, with env ("KEY=value") added to the environment of the command.
+func newEnvCommandRunner(genResult *GenerateResult, args []string, env []string) caseRunner {
+	return func(ctx context.Context, c TestCase, limits caseLimits) (string, *os.ProcessState, error) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		outputBuf := &limitedWriter{limit: limits.output, onExceed: cancel}
+		cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+		cmd.Dir = genResult.OutDir
+		if len(env) > 0 {
+			cmd.Env = append(os.Environ(), env...)
+		}
+		cmd.Stdin = strings.NewReader(c.InputString())
+		cmd.Stdout = outputBuf
+		cmd.Stderr = outputBuf
+		err := runCommand(cmd, limits.memory)
+		if outputBuf.exceeded {
+			err = errOutputLimitExceeded
+		}
+		return outputBuf.String(), cmd.ProcessState, err
+	}
+}
+
+// runCommand runs the command and waits for it, the error of starting it is wrapped in startError.
+// The command is killed with errMemoryLimitExceeded once its memory goes beyond memory, zero means unlimited.
+func runCommand(cmd *exec.Cmd, memory uint64) error {
+	if err := cmd.Start(); err != nil {
+		return startError{err}
+	}
+	if memory == 0 {
+		return cmd.Wait()
+	}
+	done := make(chan struct{})
+	exceeded := make(chan bool, 1)
+	go func() {
+		exceeded <- watchMemory(cmd.Process.Pid, memory, done)
+	}()
+	err := cmd.Wait()
+	close(done)
+	if <-exceeded {
+		return errMemoryLimitExceeded
+	}
+	return err
+}
+
+// Give more time for the first runs.
+// On macOS, first time execution of a binary may be slow due to the system's security check.
+const firstRunGrace = 3 * time.Second
+
+// testJobs returns the number of test cases to run concurrently.
+func testJobs() int {
+	jobs := config.Get().Code.Jobs
+	if jobs <= 0 {
+		jobs = runtime.NumCPU()
+	}
+	return jobs
+}
+
+type caseResult struct {
+	passed  bool
+	verdict string
+	report  string
+	record  report.Case
+}
+
+// runTestCases runs the test cases selected by targetCaseStr with the runner, judges and prints the results to out.
+// Cases are run by `code.jobs` workers concurrently, the results are still printed in the order of cases.
+func runTestCases(
+	q *leetcode.QuestionData,
+	genResult *GenerateResult,
+	targetCaseStr string,
+	runner caseRunner,
+	out io.Writer,
+) (*report.Suite, error) {
 	testcaseFile := genResult.GetFile(TestCasesFile)
 	if testcaseFile == nil {
 		panic("no test cases file generated")
 	}
 	tc, err := ParseTestCases(q, testcaseFile)
 	if err != nil {
-		return false, err
+		return nil, err
 	}
 	if len(tc.Cases) == 0 {
-		return false, fmt.Errorf("no test cases found")
+		return nil, fmt.Errorf("no test cases found")
 	}
-	caseRange, err := ParseRange(targetCaseStr, len(tc.Cases))
+	caseRange, err := tc.SelectCases(targetCaseStr)
 	if err != nil {
-		return false, err
+		return nil, err
 	}
 
-	judger := GetJudger(q)
+	judgers, err := getCaseJudgers(q, filepath.Dir(testcaseFile.GetPath()), tc.Cases)
+	if err != nil {
+		return nil, err
+	}
+	limits, err := getCaseLimits(q, genResult.Lang)
+	if err != nil {
+		return nil, err
+	}
 
-	var ran, passed int
-	for _, c := range tc.Cases {
-		func() {
-			l := list.NewWriter()
-			l.SetStyle(list.StyleBulletCircle)
-			defer func() {
-				fmt.Println(l.Render())
-			}()
-			if !caseRange.Contains(c.No) {
-				l.AppendItem(fmt.Sprintf("Case %d:    %s", c.No, config.SkippedStyle.Render("Skipped")))
-				return
-			}
-			if !c.HasOutput() {
-				l.AppendItem(fmt.Sprintf("Case %d:    %s", c.No, config.SkippedStyle.Render("Skipped: no output")))
-				return
-			}
-			ran++
-			timeout := 3 * time.Second
-			if ran == 1 {
-				// Give more time for the first run.
-				// On macOS, first time execution of a binary may be slow due to the system's security check.
-				timeout += 3 * time.Second
-			}
-			ctx, cancel := context.WithTimeout(context.Background(), timeout)
-			defer cancel()
-
-			outputBuf := new(strings.Builder)
-			cmd := exec.CommandContext(ctx, args[0], args[1:]...)
-			cmd.Dir = genResult.OutDir
-			cmd.Stdin = strings.NewReader(c.InputString())
-			cmd.Stdout = outputBuf
-			cmd.Stderr = outputBuf
-			err = cmd.Start()
-			if err != nil {
-				l.AppendItem(
-					fmt.Sprintf(
-						"Case %d:    %s",
-						c.No,
-						config.ErrorStyle.Render("Failed to start:", err.Error()),
-					),
-				)
-				return
-			}
-			err = cmd.Wait()
+	jobs := min(testJobs(), len(tc.Cases))
+	// Wall time is not a fair measure when the workers compete for CPUs, so the deadline is extended
+	// accordingly, and the time limit is checked by exceedsTimeLimit instead.
+	wallScale := func(d time.Duration) time.Duration { return d }
+	if jobs > 1 {
+		cpus := runtime.NumCPU()
+		wallScale = func(d time.Duration) time.Duration {
+			return d * time.Duration((jobs+cpus-1)/cpus) * 3 / 2
+		}
+	}
+	log.Debug("running test cases", "jobs", jobs)
 
-			actualOutput, stdout := extractOutput(outputBuf.String())
-			mayAppendStdout := func() {
-				if stdout != "" {
-					out := config.StdoutStyle.Render(utils.TruncateString(stdout, 1000))
-					l.AppendItem(fmt.Sprintf("Stdout:     %s", out))
+	results := make([]caseResult, len(tc.Cases))
+	next := make(chan int)
+	done := make(chan int)
+	var started atomic.Int32
+	var wg sync.WaitGroup
+	for range jobs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				c := tc.Cases[i]
+				switch {
+				case !caseRange.Contains(c.No):
+					results[i] = skippedCase(c, "Skipped")
+				case !c.HasOutput():
+					results[i] = skippedCase(c, "Skipped: no output")
+				default:
+					timeout := limits.time
+					if c.Timeout > 0 {
+						timeout = c.Timeout
+					}
+					if started.Add(1) <= int32(jobs) {
+						timeout += firstRunGrace
+					}
+					results[i] = runCase(q, c, judgers[c.Judge], runner, limits, timeout, wallScale(timeout))
 				}
-			}
-			if ctx.Err() != nil {
-				l.AppendItem(fmt.Sprintf("Case %d:    %s", c.No, config.ErrorStyle.Render("Time limit exceeded")))
-				l.Indent()
-				l.AppendItem(
-					fmt.Sprintf(
-						"Input:      %s",
-						utils.TruncateString(strings.ReplaceAll(c.InputString(), "\n", "↩ "), 100),
-					),
-				)
-				mayAppendStdout()
-				l.UnIndent()
-				return
-			}
-			if err != nil {
-				l.AppendItem(fmt.Sprintf("Case %d:    %s", c.No, config.ErrorStyle.Render("Runtime error")))
-				l.Indent()
-				l.AppendItem(
-					fmt.Sprintf(
-						"Input:      %s",
-						utils.TruncateString(strings.ReplaceAll(c.InputString(), "\n", "↩ "), 100),
-					),
-				)
-				mayAppendStdout()
-				l.UnIndent()
-				return
-			}
-			err = checkOutput(q, c.Input, actualOutput)
-			if err != nil {
-				l.AppendItem(fmt.Sprintf("Case %d:    %s", c.No, config.ErrorStyle.Render("Invalid output")))
-				l.Indent()
-				l.AppendItem(
-					fmt.Sprintf(
-						"Input:      %s",
-						utils.TruncateString(strings.ReplaceAll(c.InputString(), "\n", "↩ "), 100),
-					),
-				)
-				l.AppendItem(fmt.Sprintf("Output:     %s", utils.TruncateString(actualOutput, 100)))
-				mayAppendStdout()
-				l.UnIndent()
-				return
-			}
-
-			if r := judger.Judge(c.Input, c.Output, actualOutput); r.IsAccepted() {
-				passed++
-				l.AppendItem(fmt.Sprintf("Case %d:    %s", c.No, config.PassedStyle.Render("Passed")))
-			} else {
-				l.AppendItem(fmt.Sprintf("Case %d:    %s", c.No, config.FailedStyle.Render("Wrong answer")))
-				l.Indent()
-				l.AppendItem(fmt.Sprintf("Reason:     %s", r.GetInfo()))
-				l.AppendItem(
-					fmt.Sprintf(
-						"Input:      %s",
-						utils.TruncateString(strings.ReplaceAll(c.InputString(), "\n", "↩ "), 100),
-					),
-				)
-				l.AppendItem(fmt.Sprintf("Output:     %s", utils.TruncateString(actualOutput, 100)))
-				l.AppendItem(fmt.Sprintf("Expected:   %s", utils.TruncateString(c.Output, 100)))
-				mayAppendStdout()
-				l.UnIndent()
+				done <- i
 			}
 		}()
 	}
+	go func() {
+		for i := range tc.Cases {
+			next <- i
+		}
+		close(next)
+		wg.Wait()
+		close(done)
+	}()
 
-	return passed == ran, nil
+	// Print a result as soon as all the cases before it are finished.
+	suite := &report.Suite{Question: q.TitleSlug, Kind: report.LocalTest}
+	finished := make([]bool, len(tc.Cases))
+	printed := 0
+	for i := range done {
+		finished[i] = true
+		for ; printed < len(tc.Cases) && finished[printed]; printed++ {
+			r := results[printed]
+			_, _ = fmt.Fprintln(out, r.report)
+			suite.Cases = append(suite.Cases, r.record)
+			suite.Runtime += r.record.Runtime
+			suite.Memory = max(suite.Memory, r.record.Memory)
+		}
+	}
+
+	return suite, nil
+}
+
+// getCaseJudger returns the special judge of the question if there is one, or the built-in judger.
+func getCaseJudger(q *leetcode.QuestionData, dir string) (Judger, error) {
+	judger, err := getSpecialJudger(q, dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load special judge: %w", err)
+	}
+	if judger == nil {
+		judger = GetJudger(q)
+	}
+	return judger, nil
+}
+
+// getCaseJudgers returns the judgers of the cases by their `judge` option, the empty option is for the judge
+// of the question.
+func getCaseJudgers(q *leetcode.QuestionData, dir string, cases []TestCase) (map[string]Judger, error) {
+	judger, err := getCaseJudger(q, dir)
+	if err != nil {
+		return nil, err
+	}
+	judgers := map[string]Judger{"": judger}
+	for _, c := range cases {
+		if _, ok := judgers[c.Judge]; ok {
+			continue
+		}
+		judgers[c.Judge], err = getJudgerByName(q, dir, c.Judge)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", c.Label(), err)
+		}
+	}
+	return judgers, nil
+}
+
+func skippedCase(c TestCase, reason string) caseResult {
+	l := list.NewWriter()
+	l.SetStyle(list.StyleBulletCircle)
+	l.AppendItem(fmt.Sprintf("%s:    %s", c.Label(), config.SkippedStyle.Render(reason)))
+	return caseResult{
+		verdict: "Skipped",
+		report:  l.Render(),
+		record: report.Case{
+			Name:    c.Label(),
+			Verdict: reason,
+			Skipped: true,
+			Input:   strings.Join(c.Input, "\n"),
+		},
+	}
+}
+
+// exceedsTimeLimit reports whether a test program that used cpu time in wall time ran beyond the time limit.
+// The CPU time is summed over all the threads, so it's divided by the parallelism of the program, cpu/wall when
+// it's more than 1: JIT compilers and garbage collectors running beside the solution must not make it a TLE.
+// Otherwise the CPU time is used as is, since the wall time is stretched by the other workers.
+func exceedsTimeLimit(cpu, wall, limit time.Duration) bool {
+	return min(cpu, wall) > limit
+}
+
+// runCase runs a single case within the limits. If the deadline of wall time is extended beyond the time limit,
+// the time used by the test program is checked by exceedsTimeLimit instead.
+func runCase(
+	q *leetcode.QuestionData,
+	c TestCase,
+	judger Judger,
+	runner caseRunner,
+	limits caseLimits,
+	timeLimit time.Duration,
+	wallLimit time.Duration,
+) (result caseResult) {
+	l := list.NewWriter()
+	l.SetStyle(list.StyleBulletCircle)
+	result.record = report.Case{
+		Name:     c.Label(),
+		Input:    strings.Join(c.Input, "\n"),
+		Expected: c.Output,
+	}
+	defer func() {
+		result.report = l.Render()
+		result.record.Verdict = result.verdict
+		result.record.Passed = result.passed
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), wallLimit)
+	defer cancel()
+
+	start := time.Now()
+	out, state, err := runner(ctx, c, limits)
+	wall := time.Since(start)
+	var startErr startError
+	if errors.As(err, &startErr) {
+		result.verdict = "Failed to start"
+		result.record.Message = startErr.Error()
+		l.AppendItem(
+			fmt.Sprintf(
+				"%s:    %s",
+				c.Label(),
+				config.ErrorStyle.Render("Failed to start:", startErr.Error()),
+			),
+		)
+		return result
+	}
+
+	caseLine := func(style lipgloss.Style, verdict string) string {
+		result.verdict = verdict
+		result.record.Runtime = float64(cpuTime(state).Microseconds()) / 1000
+		result.record.Memory = peakRSS(state)
+		line := fmt.Sprintf("%s:    %s", c.Label(), style.Render(verdict))
+		if usage := usageString(state); usage != "" {
+			line += "    " + config.StdoutStyle.Render(usage)
+		}
+		return line
+	}
+	actualOutput, stdout := extractOutput(out)
+	result.record.Actual = actualOutput
+	result.record.Stdout = stdout
+	appendInput := func() {
+		l.AppendItem(
+			fmt.Sprintf(
+				"Input:      %s",
+				utils.TruncateString(strings.ReplaceAll(c.InputString(), "\n", "↩ "), 100),
+			),
+		)
+		if c.Note != "" {
+			l.AppendItem(fmt.Sprintf("Note:       %s", c.Note))
+		}
+	}
+	mayAppendStdout := func() {
+		if stdout != "" {
+			out := config.StdoutStyle.Render(utils.TruncateString(stdout, 1000))
+			l.AppendItem(fmt.Sprintf("Stdout:     %s", out))
+		}
+	}
+	if errors.Is(err, errOutputLimitExceeded) {
+		l.AppendItem(caseLine(config.ErrorStyle, "Output limit exceeded"))
+		result.record.Message = "limit: " + humanize.Bytes(limits.output)
+		l.Indent()
+		appendInput()
+		l.AppendItem(fmt.Sprintf("Limit:      %s", humanize.Bytes(limits.output)))
+		l.UnIndent()
+		return result
+	}
+	if ctx.Err() != nil || (wallLimit > timeLimit && exceedsTimeLimit(cpuTime(state), wall, timeLimit)) {
+		l.AppendItem(caseLine(config.ErrorStyle, "Time limit exceeded"))
+		result.record.Message = "limit: " + timeLimit.String()
+		l.Indent()
+		appendInput()
+		mayAppendStdout()
+		l.UnIndent()
+		return result
+	}
+	if isOutOfMemory(limits, state, out, err) {
+		l.AppendItem(caseLine(config.ErrorStyle, "Memory limit exceeded"))
+		result.record.Message = "limit: " + humanize.Bytes(limits.memory)
+		l.Indent()
+		appendInput()
+		l.AppendItem(fmt.Sprintf("Limit:      %s", humanize.Bytes(limits.memory)))
+		mayAppendStdout()
+		l.UnIndent()
+		return result
+	}
+	// UndefinedBehaviorSanitizer may be set to report and go on, so the report is checked even without an error.
+	if summary := sanitizerReport(out); summary != "" {
+		l.AppendItem(caseLine(config.ErrorStyle, fmt.Sprintf("Runtime error (%s)", summary)))
+		result.record.Message = summary
+		l.Indent()
+		appendInput()
+		mayAppendStdout()
+		l.UnIndent()
+		return result
+	}
+	if err != nil {
+		l.AppendItem(caseLine(config.ErrorStyle, "Runtime error"))
+		result.record.Message = err.Error()
+		l.Indent()
+		appendInput()
+		mayAppendStdout()
+		l.UnIndent()
+		return result
+	}
+	err = checkOutput(q, c.Input, actualOutput)
+	if err != nil {
+		l.AppendItem(caseLine(config.ErrorStyle, "Invalid output"))
+		result.record.Message = err.Error()
+		l.Indent()
+		appendInput()
+		l.AppendItem(fmt.Sprintf("Output:     %s", utils.TruncateString(actualOutput, 100)))
+		mayAppendStdout()
+		l.UnIndent()
+		return result
+	}
+
+	if r := judger.Judge(c.Input, c.Output, actualOutput); r.IsAccepted() {
+		result.passed = true
+		l.AppendItem(caseLine(config.PassedStyle, "Passed"))
+	} else {
+		l.AppendItem(caseLine(config.FailedStyle, "Wrong answer"))
+		result.record.Message = r.GetInfo()
+		l.Indent()
+		l.AppendItem(fmt.Sprintf("Reason:     %s", r.GetInfo()))
+		appendInput()
+		l.AppendItem(fmt.Sprintf("Output:     %s", utils.TruncateString(actualOutput, 100)))
+		l.AppendItem(fmt.Sprintf("Expected:   %s", utils.TruncateString(c.Output, 100)))
+		mayAppendStdout()
+		l.UnIndent()
+	}
+	return result
 }
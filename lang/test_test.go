@@ -0,0 +1,61 @@
+package lang
+
+import (
+	"os/exec"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/j178/leetgo/leetcode"
+)
+
+func TestExceedsTimeLimit(t *testing.T) {
+	tests := []struct {
+		name      string
+		cpu, wall time.Duration
+		exceeded  bool
+	}{
+		{"single thread", 900 * time.Millisecond, 950 * time.Millisecond, false},
+		{"single thread too slow", 1500 * time.Millisecond, 1600 * time.Millisecond, true},
+		// Competing with other workers stretches the wall time, not the CPU time.
+		{"waiting for CPUs", 900 * time.Millisecond, 3 * time.Second, false},
+		// JIT and GC threads use more CPU time than the wall time.
+		{"multithreaded", 3 * time.Second, 800 * time.Millisecond, false},
+		{"multithreaded too slow", 6 * time.Second, 1500 * time.Millisecond, true},
+	}
+	for _, tt := range tests {
+		if got := exceedsTimeLimit(tt.cpu, tt.wall, time.Second); got != tt.exceeded {
+			t.Errorf("%s: exceeded = %v, want %v", tt.name, got, tt.exceeded)
+		}
+	}
+}
+
+func TestRunCaseMultithreaded(t *testing.T) {
+	if runtime.GOOS == "windows" || runtime.NumCPU() < 4 {
+		t.Skip("needs a shell and 4 CPUs")
+	}
+	// Four busy processes, the CPU time is about four times the wall time.
+	script := `busy() { i=0; while [ $i -lt 100000 ]; do i=$((i+1)); done; }
+busy & busy & busy & busy & wait
+echo "` + testCaseOutputMark + ` 1"`
+	cmd := exec.Command("sh", "-c", script)
+	start := time.Now()
+	if err := cmd.Run(); err != nil {
+		t.Fatal(err)
+	}
+	wall := time.Since(start)
+	if cpuTime(cmd.ProcessState) < 3*wall {
+		t.Skipf("not run in parallel, CPU %s in %s", cpuTime(cmd.ProcessState), wall)
+	}
+
+	genResult := &GenerateResult{OutDir: t.TempDir()}
+	runner := newCommandRunner(genResult, []string{"sh", "-c", script})
+	c := TestCase{No: 1, Input: []string{}, Output: "1"}
+	// The time limit is below the CPU time but well above the wall time, as if scaled for --jobs.
+	limit := 2 * wall
+	q := &leetcode.QuestionData{MetaData: leetcode.MetaData{Return: &leetcode.MetaDataReturn{Type: "integer"}}}
+	result := runCase(q, c, stringJudger{}, runner, caseLimits{time: limit}, limit, 10*limit)
+	if !result.passed {
+		t.Errorf("verdict = %s, want passed\n%s", result.verdict, result.report)
+	}
+}
@@ -3,10 +3,12 @@ package lang
 import (
 	"errors"
 	"fmt"
+	"os"
 	"reflect"
 	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/j178/leetgo/leetcode"
 	goutils "github.com/j178/leetgo/testutils/go"
@@ -18,6 +20,15 @@ type TestCase struct {
 	No       int
 	Input    []string
 	Output   string
+
+	// Options that can only be set in a structured test cases file.
+	Name string
+	Tags []string
+	// Timeout overrides the time limit of the question.
+	Timeout time.Duration
+	// Judge overrides the judge of the question, see getJudgerByName.
+	Judge string
+	Note  string
 }
 
 func (c *TestCase) Check() error {
@@ -61,6 +72,43 @@ func (c *TestCase) Check() error {
 		return nil
 	}
 
+	if q.MetaData.Database {
+		// Database questions have a single input line holding all the tables, and output a table:
+		// input:
+		// {"headers": {"Person": ["personId", "email"]}, "rows": {"Person": [[1, "a@b.com"]]}}
+		// output:
+		// {"headers": ["email"], "values": [["a@b.com"]]}
+		if len(c.Input) != narg {
+			return fmt.Errorf("should have %d arguments, got %d", narg, len(c.Input))
+		}
+		if _, err := parseSQLInput(c.Input[0]); err != nil {
+			return fmt.Errorf("cannot parse %s as tables: %w", c.Input[0], err)
+		}
+		if c.HasOutput() {
+			if _, err := parseSQLTable(c.Output); err != nil {
+				return fmt.Errorf("cannot parse %s as a table: %w", c.Output, err)
+			}
+		}
+		return nil
+	}
+
+	if q.MetaData.Shell {
+		// Shell questions have the content of the input file as input, and the expected stdout as output,
+		// both are quoted strings.
+		if len(c.Input) != narg {
+			return fmt.Errorf("should have %d arguments, got %d", narg, len(c.Input))
+		}
+		if _, err := strconv.Unquote(c.Input[0]); err != nil {
+			return fmt.Errorf("cannot parse %s as a string", c.Input[0])
+		}
+		if c.HasOutput() {
+			if _, err := strconv.Unquote(c.Output); err != nil {
+				return fmt.Errorf("cannot parse %s as a string", c.Output)
+			}
+		}
+		return nil
+	}
+
 	resultType := q.MetaData.ResultType()
 	if len(c.Input) != narg {
 		return fmt.Errorf("should have %d arguments, got %d", narg, len(c.Input))
@@ -80,6 +128,14 @@ func (c *TestCase) Check() error {
 	return nil
 }
 
+// Label returns the number of the case, with its name if it has one.
+func (c *TestCase) Label() string {
+	if c.Name != "" {
+		return fmt.Sprintf("Case %d (%s)", c.No, c.Name)
+	}
+	return fmt.Sprintf("Case %d", c.No)
+}
+
 func (c *TestCase) InputString() string {
 	return utils.EnsureTrailingNewline(strings.Join(c.Input, "\n"))
 }
@@ -91,6 +147,8 @@ func (c *TestCase) HasOutput() bool {
 type TestCases struct {
 	Cases    []TestCase
 	Question *leetcode.QuestionData
+	// path is the file the cases are parsed from.
+	path string
 }
 
 func (tc *TestCases) AddCase(c TestCase) {
@@ -155,13 +213,39 @@ func (tc *TestCases) UpdateOutputs(answers []string) (bool, error) {
 	return updated, nil
 }
 
+// ParseTestCases parses the test cases file in use, see TestCasesPath.
 func ParseTestCases(q *leetcode.QuestionData, f *FileOutput) (TestCases, error) {
-	tc := TestCases{Question: q}
+	tc := TestCases{Question: q, path: TestCasesPath(f)}
 
-	content, err := f.GetContent()
+	var content string
+	var err error
+	if tc.path == f.GetPath() {
+		content, err = f.GetContent()
+	} else {
+		var b []byte
+		b, err = os.ReadFile(tc.path)
+		content = string(b)
+	}
+	if err != nil {
+		return tc, err
+	}
+	if format := testCasesFormat(tc.path); format != TestCasesFormatText {
+		err = tc.parseStructured(format, []byte(content))
+	} else {
+		err = tc.parseText(content)
+	}
 	if err != nil {
 		return tc, err
 	}
+
+	if err := tc.Check(); err != nil {
+		return tc, fmt.Errorf("invalid test case: %w", err)
+	}
+
+	return tc, nil
+}
+
+func (tc *TestCases) parseText(content string) error {
 	var (
 		inputLines    []string
 		output        string
@@ -194,7 +278,7 @@ func ParseTestCases(q *leetcode.QuestionData, f *FileOutput) (TestCases, error)
 			inputLines = append(inputLines, line)
 		case outputStarted:
 			if len(output) > 0 {
-				return tc, errors.New("invalid test case: output should be a single line")
+				return errors.New("invalid test case: output should be a single line")
 			}
 			output = line
 		}
@@ -207,12 +291,7 @@ func ParseTestCases(q *leetcode.QuestionData, f *FileOutput) (TestCases, error)
 			},
 		)
 	}
-
-	if err := tc.Check(); err != nil {
-		return tc, fmt.Errorf("invalid test case: %w", err)
-	}
-
-	return tc, nil
+	return nil
 }
 
 type Range struct {
@@ -0,0 +1,438 @@
+package lang
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-json"
+	"gopkg.in/yaml.v3"
+
+	"github.com/j178/leetgo/leetcode"
+	"github.com/j178/leetgo/utils"
+)
+
+// Besides the `input:`/`output:` format of testcases.txt, test cases can be written in YAML or JSON, in
+// testcases.yaml (or .yml, .json) next to testcases.txt, which takes precedence over testcases.txt. Each case
+// may have a name, tags, a timeout, a judge and a note:
+//
+//	# testcases.yaml
+//	- name: two equal numbers
+//	  tags: [failed-submission]
+//	  input:
+//	    - [3, 3]
+//	    - 6
+//	  output: [0, 1]
+//	  timeout: 2s
+//	  judge: ignore_order
+//	  note: the same element can't be used twice
+//
+// Inputs and outputs are written as YAML or JSON values, they are converted to the LeetCode format, which is JSON.
+const (
+	TestCasesFormatText = "txt"
+	TestCasesFormatYAML = "yaml"
+	TestCasesFormatJSON = "json"
+)
+
+// TestCasesFormats are the formats a test cases file can be written in.
+var TestCasesFormats = []string{TestCasesFormatText, TestCasesFormatYAML, TestCasesFormatJSON}
+
+// Tags of test cases that tell where they come from.
+const (
+	TagExample          = "example"
+	TagFailedSubmission = "failed-submission"
+	TagStress           = "stress"
+	TagShrunk           = "shrunk"
+)
+
+// Extensions of the structured test cases files in the order of precedence.
+var structuredTestCasesExts = []string{".yaml", ".yml", ".json"}
+
+func testCasesFormat(path string) string {
+	switch filepath.Ext(path) {
+	case ".yaml", ".yml":
+		return TestCasesFormatYAML
+	case ".json":
+		return TestCasesFormatJSON
+	default:
+		return TestCasesFormatText
+	}
+}
+
+// TestCasesPaths returns the paths the test cases of the generated test cases file may be read from,
+// in the order of precedence.
+func TestCasesPaths(f *FileOutput) []string {
+	txtPath := f.GetPath()
+	base := strings.TrimSuffix(txtPath, filepath.Ext(txtPath))
+	paths := make([]string, 0, len(structuredTestCasesExts)+1)
+	for _, ext := range structuredTestCasesExts {
+		paths = append(paths, base+ext)
+	}
+	return append(paths, txtPath)
+}
+
+// TestCasesPath returns the path of the test cases file in use: a structured one if there is one,
+// otherwise the generated testcases.txt.
+func TestCasesPath(f *FileOutput) string {
+	paths := TestCasesPaths(f)
+	for _, path := range paths {
+		if utils.IsExist(path) {
+			return path
+		}
+	}
+	return paths[len(paths)-1]
+}
+
+// caseValue is an input or output value in a structured test cases file. It's kept in the LeetCode format,
+// and written as a YAML or JSON value.
+type caseValue string
+
+func (v caseValue) MarshalJSON() ([]byte, error) {
+	if json.Valid([]byte(v)) {
+		buf := new(bytes.Buffer)
+		if err := json.Compact(buf, []byte(v)); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+	return json.Marshal(string(v))
+}
+
+func (v *caseValue) UnmarshalJSON(data []byte) error {
+	buf := new(bytes.Buffer)
+	if err := json.Compact(buf, data); err != nil {
+		return err
+	}
+	*v = caseValue(buf.String())
+	return nil
+}
+
+func (v caseValue) MarshalYAML() (any, error) {
+	// JSON values are valid YAML flow values, parsing them as YAML keeps the scalars as they are.
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(v), &doc); err != nil || len(doc.Content) == 0 {
+		return string(v), nil
+	}
+	node := doc.Content[0]
+	setFlowStyle(node)
+	return node, nil
+}
+
+func setFlowStyle(node *yaml.Node) {
+	if node.Kind == yaml.SequenceNode || node.Kind == yaml.MappingNode {
+		node.Style = yaml.FlowStyle
+	}
+	for _, n := range node.Content {
+		setFlowStyle(n)
+	}
+}
+
+func (v *caseValue) UnmarshalYAML(node *yaml.Node) error {
+	s, err := yamlToJSON(node)
+	if err != nil {
+		return err
+	}
+	*v = caseValue(s)
+	return nil
+}
+
+// yamlToJSON converts a YAML value to compact JSON, keeping the numbers as they are written.
+func yamlToJSON(node *yaml.Node) (string, error) {
+	switch node.Kind {
+	case yaml.AliasNode:
+		return yamlToJSON(node.Alias)
+	case yaml.ScalarNode:
+		switch node.ShortTag() {
+		case "!!int", "!!float":
+			return yamlNumberToJSON(node)
+		case "!!bool":
+			// YAML 1.2 only has true and false, which are the same in JSON.
+			return node.Value, nil
+		case "!!null":
+			return "null", nil
+		default:
+			b, err := json.Marshal(node.Value)
+			return string(b), err
+		}
+	case yaml.SequenceNode, yaml.MappingNode:
+		elems := make([]string, 0, len(node.Content))
+		for i := 0; i < len(node.Content); i++ {
+			elem, err := yamlToJSON(node.Content[i])
+			if err != nil {
+				return "", err
+			}
+			if node.Kind == yaml.MappingNode {
+				i++
+				if i >= len(node.Content) {
+					return "", fmt.Errorf("line %d: invalid mapping", node.Line)
+				}
+				value, err := yamlToJSON(node.Content[i])
+				if err != nil {
+					return "", err
+				}
+				elem += ":" + value
+			}
+			elems = append(elems, elem)
+		}
+		if node.Kind == yaml.MappingNode {
+			return "{" + strings.Join(elems, ",") + "}", nil
+		}
+		return "[" + strings.Join(elems, ",") + "]", nil
+	default:
+		return "", fmt.Errorf("line %d: unsupported value", node.Line)
+	}
+}
+
+// yamlNumberToJSON converts a YAML number to JSON. Numbers written as JSON numbers are kept as they are,
+// e.g. `2.50` and integers beyond int64, the others like `0x1F` and `1_000` are converted through strconv.
+func yamlNumberToJSON(node *yaml.Node) (string, error) {
+	if json.Valid([]byte(node.Value)) {
+		return node.Value, nil
+	}
+	if node.ShortTag() == "!!int" {
+		var v int64
+		if err := node.Decode(&v); err != nil {
+			return "", fmt.Errorf("line %d: invalid integer %s", node.Line, node.Value)
+		}
+		return strconv.FormatInt(v, 10), nil
+	}
+	var v float64
+	if err := node.Decode(&v); err != nil {
+		return "", fmt.Errorf("line %d: invalid number %s", node.Line, node.Value)
+	}
+	if math.IsInf(v, 0) || math.IsNaN(v) {
+		return "", fmt.Errorf("line %d: %s can't be represented in JSON", node.Line, node.Value)
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64), nil
+}
+
+// fileCase is a case in a structured test cases file.
+type fileCase struct {
+	Name    string      `json:"name,omitempty" yaml:"name,omitempty"`
+	Tags    []string    `json:"tags,omitempty" yaml:"tags,omitempty,flow"`
+	Input   []caseValue `json:"input" yaml:"input"`
+	Output  caseValue   `json:"output,omitempty" yaml:"output,omitempty"`
+	Timeout string      `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	Judge   string      `json:"judge,omitempty" yaml:"judge,omitempty"`
+	Note    string      `json:"note,omitempty" yaml:"note,omitempty"`
+}
+
+// decodeYAMLCases decodes the cases one by one, so that an invalid value is reported with its case.
+func decodeYAMLCases(content []byte) ([]fileCase, error) {
+	var nodes []yaml.Node
+	if err := yaml.Unmarshal(content, &nodes); err != nil {
+		return nil, err
+	}
+	cases := make([]fileCase, len(nodes))
+	for i := range nodes {
+		if err := nodes[i].Decode(&cases[i]); err != nil {
+			if cases[i].Name != "" {
+				return nil, fmt.Errorf("test case %d (%s): %w", i+1, cases[i].Name, err)
+			}
+			return nil, fmt.Errorf("test case %d: %w", i+1, err)
+		}
+	}
+	return cases, nil
+}
+
+func (tc *TestCases) parseStructured(format string, content []byte) error {
+	var cases []fileCase
+	var err error
+	if format == TestCasesFormatJSON {
+		err = json.Unmarshal(content, &cases)
+	} else {
+		cases, err = decodeYAMLCases(content)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid test cases file: %w", err)
+	}
+
+	names := make(map[string]bool, len(cases))
+	for i, fc := range cases {
+		c := TestCase{
+			Name:  fc.Name,
+			Tags:  fc.Tags,
+			Judge: fc.Judge,
+			Note:  fc.Note,
+		}
+		if c.Name != "" {
+			if names[c.Name] {
+				return fmt.Errorf("invalid test case %d: duplicate name %q", i+1, c.Name)
+			}
+			names[c.Name] = true
+		}
+		for _, v := range fc.Input {
+			c.Input = append(c.Input, string(v))
+		}
+		c.Output = string(fc.Output)
+		if fc.Timeout != "" {
+			c.Timeout, err = time.ParseDuration(fc.Timeout)
+			if err != nil || c.Timeout <= 0 {
+				return fmt.Errorf("invalid test case %d: invalid timeout %q", i+1, fc.Timeout)
+			}
+		}
+		tc.AddCase(c)
+	}
+	return nil
+}
+
+// Marshal encodes the test cases in the format. Names, tags and other options of the cases are lost in
+// the txt format.
+func (tc *TestCases) Marshal(format string) ([]byte, error) {
+	if format == TestCasesFormatText {
+		return []byte(tc.String()), nil
+	}
+	cases := make([]fileCase, 0, len(tc.Cases))
+	for _, c := range tc.Cases {
+		fc := fileCase{
+			Name:   c.Name,
+			Tags:   c.Tags,
+			Output: caseValue(c.Output),
+			Judge:  c.Judge,
+			Note:   c.Note,
+		}
+		for _, v := range c.Input {
+			fc.Input = append(fc.Input, caseValue(v))
+		}
+		if c.Timeout > 0 {
+			fc.Timeout = c.Timeout.String()
+		}
+		cases = append(cases, fc)
+	}
+	switch format {
+	case TestCasesFormatJSON:
+		// One case per line, indenting would spread the values of the cases over too many lines.
+		buf := bytes.NewBufferString("[\n")
+		for i, c := range cases {
+			b, err := json.Marshal(c)
+			if err != nil {
+				return nil, err
+			}
+			buf.WriteString("  ")
+			buf.Write(b)
+			if i != len(cases)-1 {
+				buf.WriteString(",")
+			}
+			buf.WriteString("\n")
+		}
+		buf.WriteString("]\n")
+		return buf.Bytes(), nil
+	case TestCasesFormatYAML:
+		buf := new(bytes.Buffer)
+		enc := yaml.NewEncoder(buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(cases); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	default:
+		return nil, fmt.Errorf("unknown test cases format: %s", format)
+	}
+}
+
+// Save writes the test cases back to the file they were parsed from.
+func (tc *TestCases) Save() error {
+	if tc.path == "" {
+		return errors.New("test cases are not parsed from a file")
+	}
+	content, err := tc.Marshal(testCasesFormat(tc.path))
+	if err != nil {
+		return err
+	}
+	return utils.WriteFile(tc.path, content)
+}
+
+// hasOptions reports whether any case has options that can't be written in the txt format.
+func (tc *TestCases) hasOptions() bool {
+	for _, c := range tc.Cases {
+		if c.Name != "" || len(c.Tags) > 0 || c.Timeout > 0 || c.Judge != "" || c.Note != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// ConvertTestCases converts the test cases file of the question to the format, and removes the old file.
+// Cases converted from the txt format that are examples of the question are tagged `example`.
+func ConvertTestCases(q *leetcode.QuestionData, format string) (string, error) {
+	if !slices.Contains(TestCasesFormats, format) {
+		return "", fmt.Errorf("unknown test cases format: %s, available: %s", format, strings.Join(TestCasesFormats, ", "))
+	}
+	f, err := GetFileOutput(q, TestCasesFile)
+	if err != nil {
+		return "", err
+	}
+	tc, err := ParseTestCases(q, f)
+	if err != nil {
+		return "", err
+	}
+	oldFormat := testCasesFormat(tc.path)
+	if oldFormat == format {
+		return "", fmt.Errorf("%s is already in %s format", utils.RelToCwd(tc.path), format)
+	}
+	if format == TestCasesFormatText && tc.hasOptions() {
+		return "", fmt.Errorf("%s has names or options of cases that the txt format can't keep", utils.RelToCwd(tc.path))
+	}
+	if oldFormat == TestCasesFormatText {
+		tc.tagExamples()
+	}
+
+	newPath := f.GetPath()
+	if format != TestCasesFormatText {
+		newPath = strings.TrimSuffix(newPath, filepath.Ext(newPath)) + "." + format
+	}
+	content, err := tc.Marshal(format)
+	if err != nil {
+		return "", err
+	}
+	if err := utils.WriteFile(newPath, content); err != nil {
+		return "", err
+	}
+	if err := os.Remove(tc.path); err != nil {
+		return "", err
+	}
+	return newPath, nil
+}
+
+// tagExamples tags the cases that are examples of the question.
+func (tc *TestCases) tagExamples() {
+	examples := tc.Question.GetExampleTestCases()
+	narg := max(tc.Question.MetaData.NArg(), 1)
+	for i := range tc.Cases {
+		c := &tc.Cases[i]
+		for j := 0; j+narg <= len(examples); j += narg {
+			if slices.Equal(c.Input, examples[j:j+narg]) && !slices.Contains(c.Tags, TagExample) {
+				c.Tags = append(c.Tags, TagExample)
+				break
+			}
+		}
+	}
+}
+
+// SelectCases parses a comma separated list of index ranges (see ParseRange) and case names.
+func (tc *TestCases) SelectCases(expr string) (*Range, error) {
+	if r, err := ParseRange(expr, len(tc.Cases)); err == nil {
+		return r, nil
+	}
+	r := &Range{max: len(tc.Cases)}
+	for _, part := range strings.Split(expr, ",") {
+		if i := slices.IndexFunc(tc.Cases, func(c TestCase) bool { return c.Name == part }); i >= 0 {
+			r.ranges = append(r.ranges, [2]int{i + 1, i + 1})
+			continue
+		}
+		pr, err := ParseRange(part, len(tc.Cases))
+		if err != nil {
+			return nil, fmt.Errorf("invalid range or case name: %s", strconv.Quote(part))
+		}
+		r.whole = r.whole || pr.whole
+		r.ranges = append(r.ranges, pr.ranges...)
+	}
+	return r, nil
+}
@@ -0,0 +1,145 @@
+package lang
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/j178/leetgo/leetcode"
+)
+
+func testQuestion() *leetcode.QuestionData {
+	return &leetcode.QuestionData{
+		TitleSlug: "two-sum",
+		MetaData: leetcode.MetaData{
+			Params: []leetcode.MetaDataParam{
+				{Name: "nums", Type: "integer[]"},
+				{Name: "target", Type: "integer"},
+			},
+			Return: &leetcode.MetaDataReturn{Type: "integer[]"},
+		},
+	}
+}
+
+func TestStructuredTestCases(t *testing.T) {
+	q := testQuestion()
+	tc := TestCases{Question: q}
+	tc.AddCase(TestCase{Input: []string{"[2,7,11,15]", "9"}, Output: "[0,1]"})
+	tc.AddCase(
+		TestCase{
+			Input:   []string{"[3,3]", "6"},
+			Output:  "[0,1]",
+			Name:    "same numbers",
+			Tags:    []string{TagFailedSubmission},
+			Timeout: 2 * time.Second,
+			Judge:   "ignore_order",
+			Note:    "can't use the same element twice",
+		},
+	)
+	tc.AddCase(TestCase{Input: []string{"[1,2]", "3"}})
+
+	for _, format := range []string{TestCasesFormatYAML, TestCasesFormatJSON} {
+		content, err := tc.Marshal(format)
+		if err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+		parsed := TestCases{Question: q}
+		if err := parsed.parseStructured(format, content); err != nil {
+			t.Fatalf("%s: %v\n%s", format, err, content)
+		}
+		if !reflect.DeepEqual(parsed.Cases, tc.Cases) {
+			t.Errorf("%s: round trip mismatch:\n%s\ngot %+v", format, content, parsed.Cases)
+		}
+	}
+}
+
+func TestParseYAMLTestCases(t *testing.T) {
+	content := `
+- name: native values
+  input:
+    - [1, 2.50, -3]
+    - 3
+  output: ["a", b, null, true]
+- input: [[], 0]
+`
+	tc := TestCases{Question: testQuestion()}
+	if err := tc.parseStructured(TestCasesFormatYAML, []byte(content)); err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{{"[1,2.50,-3]", "3"}, {"[]", "0"}}
+	for i, c := range tc.Cases {
+		if !reflect.DeepEqual(c.Input, want[i]) {
+			t.Errorf("case %d: input = %q, want %q", i+1, c.Input, want[i])
+		}
+	}
+	if got := tc.Cases[0].Output; got != `["a","b",null,true]` {
+		t.Errorf("output = %q", got)
+	}
+
+	dup := "- {name: a, input: [[1], 1]}\n- {name: a, input: [[2], 2]}\n"
+	if err := (&TestCases{Question: testQuestion()}).parseStructured(TestCasesFormatYAML, []byte(dup)); err == nil {
+		t.Error("duplicate names should be rejected")
+	}
+}
+
+func TestSelectCases(t *testing.T) {
+	tc := TestCases{Question: testQuestion()}
+	tc.AddCase(TestCase{Input: []string{"[1]", "1"}})
+	tc.AddCase(TestCase{Input: []string{"[2]", "2"}, Name: "two"})
+	tc.AddCase(TestCase{Input: []string{"[3]", "3"}, Name: "three"})
+	tc.AddCase(TestCase{Input: []string{"[4]", "4"}})
+
+	tests := []struct {
+		expr string
+		want []int
+	}{
+		{"1-2", []int{1, 2}},
+		{"three", []int{3}},
+		{"two,-1", []int{2, 4}},
+	}
+	for _, tt := range tests {
+		r, err := tc.SelectCases(tt.expr)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.expr, err)
+		}
+		var got []int
+		for i := 1; i <= len(tc.Cases); i++ {
+			if r.Contains(i) {
+				got = append(got, i)
+			}
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+	if _, err := tc.SelectCases("four"); err == nil {
+		t.Error("unknown name should be rejected")
+	}
+}
+
+func TestYAMLNumbers(t *testing.T) {
+	content := `
+- input: [[0x1F, 1_000, 0o17, +5, .5, 1e3], 12345678901234567890]
+  output: 2.50
+`
+	tc := TestCases{Question: testQuestion()}
+	if err := tc.parseStructured(TestCasesFormatYAML, []byte(content)); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"[31,1000,15,5,0.5,1e3]", "12345678901234567890"}
+	if got := tc.Cases[0].Input; !reflect.DeepEqual(got, want) {
+		t.Errorf("input = %q, want %q", got, want)
+	}
+	if got := tc.Cases[0].Output; got != "2.50" {
+		t.Errorf("output = %q, want 2.50", got)
+	}
+
+	for _, v := range []string{".inf", "-.Inf", ".nan"} {
+		content := "- input: [[1], 1]\n- name: bad\n  input: [[" + v + "], 1]\n"
+		err := (&TestCases{Question: testQuestion()}).parseStructured(TestCasesFormatYAML, []byte(content))
+		if err == nil || !strings.Contains(err.Error(), "test case 2 (bad)") {
+			t.Errorf("%s: err = %v, want an error of test case 2 (bad)", v, err)
+		}
+	}
+}
@@ -2,11 +2,14 @@ package leetcode
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"errors"
 	"fmt"
 	"io"
+	"math/rand/v2"
 	"net/http"
+	"os"
 	"sort"
 	"strings"
 	"time"
@@ -23,43 +26,10 @@ import (
 	"github.com/j178/leetgo/utils"
 )
 
-var (
-	ErrPaidOnlyQuestion  = errors.New("this is paid only question, you need to subscribe to LeetCode Premium")
-	ErrQuestionNotFound  = errors.New("no such question")
-	ErrContestNotStarted = errors.New("contest has not started")
-)
-
-type UnexpectedStatusCode struct {
-	Code int
-	Body string
-}
-
-func (e UnexpectedStatusCode) IsError() bool {
-	return e.Code != 0
-}
-
-func (e UnexpectedStatusCode) Error() string {
-	body := "<empty body>"
-	if len(e.Body) > 500 {
-		body = e.Body[:500] + "..."
-	}
-	return fmt.Sprintf("[%d %s] %s", e.Code, http.StatusText(e.Code), body)
-}
-
-func NewUnexpectedStatusCode(code int, body []byte) UnexpectedStatusCode {
-	err := UnexpectedStatusCode{Code: code}
-	switch code {
-	case http.StatusTooManyRequests:
-		err.Body = "LeetCode limited you access rate, you may be submitting too frequently"
-	case http.StatusForbidden:
-		err.Body = "Access is forbidden, your cookies may have expired or LeetCode has restricted its API access"
-	default:
-		err.Body = utils.BytesToString(body)
-	}
-	return err
-}
-
 type Client interface {
+	ContextClient
+	// WithContext returns a copy of the client whose requests are canceled with ctx.
+	WithContext(ctx context.Context) Client
 	BaseURI() string
 	Inspect(typ string) (map[string]any, error)
 	Login(username, password string) (*http.Response, error)
@@ -76,6 +46,8 @@ type Client interface {
 	)
 	SubmitCode(q *QuestionData, lang string, code string) (string, error)
 	CheckResult(interpretId string) (CheckResult, error)
+	GetSubmissions(slug string, offset int, limit int) (SubmissionList, error)
+	GetSubmissionDetail(id string) (*SubmissionDetail, error)
 	GetUpcomingContests() ([]*Contest, error)
 	GetContest(contestSlug string) (*Contest, error)
 	GetContestQuestionData(contestSlug string, questionSlug string) (*QuestionData, error)
@@ -85,19 +57,65 @@ type Client interface {
 }
 
 type cnClient struct {
+	contextMethods
 	opt  Options
 	http *sling.Sling
 }
 
 type Options struct {
-	debug bool
-	cred  CredentialsProvider
+	ctx       context.Context
+	debug     bool
+	cred      CredentialsProvider
+	site      config.LeetcodeSite
+	transport http.RoundTripper
+}
+
+// ClientOption customizes the client created by NewClient.
+type ClientOption func(*Options)
+
+// WithSite makes the client talk to the site instead of the one in the config.
+func WithSite(site config.LeetcodeSite) ClientOption {
+	return func(o *Options) {
+		o.site = site
+	}
 }
 
-func NewClient(cred CredentialsProvider) Client {
+// WithTransport sends the requests of the client through the transport, e.g. a Recorder or the transport of
+// a fake server in tests.
+func WithTransport(transport http.RoundTripper) ClientOption {
+	return func(o *Options) {
+		o.transport = transport
+	}
+}
+
+// WithContext makes the requests of the client canceled with ctx, including the waits between retries.
+func WithContext(ctx context.Context) ClientOption {
+	return func(o *Options) {
+		o.ctx = ctx
+	}
+}
+
+// NewClient creates a client of the LeetCode site in the config. If the LEETGO_RECORD environment variable
+// is set, the exchanges with the site are recorded to the fixture file it points to.
+func NewClient(cred CredentialsProvider, options ...ClientOption) Client {
 	opts := Options{
+		ctx:   context.Background(),
 		cred:  cred,
 		debug: config.Debug,
+		site:  config.Get().LeetCode.Site,
+	}
+	for _, o := range options {
+		o(&opts)
+	}
+	var transport http.RoundTripper = &http.Transport{
+		// Disable http2
+		TLSNextProto: make(map[string]func(authority string, c *tls.Conn) http.RoundTripper),
+	}
+	if opts.transport != nil {
+		transport = opts.transport
+	}
+	if file := os.Getenv("LEETGO_RECORD"); file != "" {
+		transport = NewRecorder(file, opts.site, transport)
 	}
 
 	httpClient := sling.New()
@@ -117,19 +135,16 @@ func NewClient(cred CredentialsProvider) Client {
 	httpClient.Client(
 		&http.Client{
 			CheckRedirect: nonFollowRedirect,
-			Transport: &http.Transport{
-				// Disable http2
-				TLSNextProto: make(map[string]func(authority string, c *tls.Conn) http.RoundTripper),
-			},
+			Transport:     transport,
 		},
 	)
 
-	cfg := config.Get()
-	if cfg.LeetCode.Site == config.LeetCodeCN {
+	if opts.site == config.LeetCodeCN {
 		c := &cnClient{
 			http: httpClient,
 			opt:  opts,
 		}
+		c.contextMethods = contextMethods{c}
 		c.http.Base(c.BaseURI())
 		c.http.Add("Referer", c.BaseURI())
 		c.http.Add("Origin", string(config.LeetCodeCN))
@@ -146,6 +161,7 @@ func NewClient(cred CredentialsProvider) Client {
 				opt:  opts,
 			},
 		}
+		c.contextMethods = contextMethods{c}
 		c.http.Base(c.BaseURI())
 		c.http.Add("Referer", c.BaseURI())
 		c.http.Add("Origin", string(config.LeetCodeUS))
@@ -194,18 +210,29 @@ const (
 	problemsApiTagsPath   = "/problems/api/tags/"
 )
 
+func (c *cnClient) WithContext(ctx context.Context) Client {
+	cc := *c
+	cc.opt.ctx = ctx
+	return &cc
+}
+
 func (c *cnClient) send(req *http.Request, authType authType, result any) (*http.Response, error) {
+	authenticated := false
 	switch authType {
 	case withoutAuth:
 	case withAuth:
 		if err := c.opt.cred.AddCredentials(req); err != nil {
 			log.Warn("add credentials failed, continue requesting without credentials", "err", err)
+		} else {
+			authenticated = true
 		}
 	case requireAuth:
 		if err := c.opt.cred.AddCredentials(req); err != nil {
 			return nil, err
 		}
+		authenticated = true
 	}
+	req = req.WithContext(c.opt.ctx)
 
 	if c.opt.debug {
 		bodyStr := []byte("<empty>")
@@ -216,36 +243,41 @@ func (c *cnClient) send(req *http.Request, authType authType, result any) (*http
 		log.Debug("request", "method", req.Method, "url", req.URL.String(), "body", utils.BytesToString(bodyStr))
 	}
 
+	attempt := 0
 	err := retry.Do(
 		func() error {
-			var (
-				err     error
-				respErr UnexpectedStatusCode
-			)
-			_, err = c.http.Do(req, result, &respErr)
+			// The body is consumed by the previous attempt.
+			if attempt > 0 && req.GetBody != nil {
+				body, err := req.GetBody()
+				if err != nil {
+					return retry.Unrecoverable(err)
+				}
+				req.Body = body
+			}
+			attempt++
+
+			var respErr UnexpectedStatusCode
+			resp, err := c.http.Do(req, result, &respErr)
 			if err != nil {
 				return err
 			}
+			// Responses without body are not decoded.
+			if !respErr.IsError() && (resp.StatusCode < 200 || resp.StatusCode > 299) {
+				respErr = newResponseError(resp, nil)
+			}
 			if respErr.IsError() {
-				return respErr
+				return respErr.typed(authenticated)
 			}
 			return nil
 		},
-		retry.RetryIf(
-			func(err error) bool {
-				// Do not retry on 429
-				var e UnexpectedStatusCode
-				if errors.As(err, &e) && e.Code == http.StatusTooManyRequests {
-					return false
-				}
-				return true
-			},
-		),
-		retry.Attempts(3),
+		retry.RetryIf(isRetryable),
+		retry.Attempts(retryAttempts),
+		retry.DelayType(retryDelay),
+		retry.Context(c.opt.ctx),
 		retry.LastErrorOnly(true),
 		retry.OnRetry(
 			func(n uint, err error) {
-				log.Warn("retry", "url", req.URL.String(), "attempt", n, "error", err)
+				log.Warn("retry", "url", req.URL.String(), "attempt", n+1, "error", err)
 			},
 		),
 	)
@@ -253,6 +285,52 @@ func (c *cnClient) send(req *http.Request, authType authType, result any) (*http
 	return nil, err
 }
 
+const (
+	retryAttempts  = 4
+	retryBaseDelay = 500 * time.Millisecond
+	retryMaxDelay  = 10 * time.Second
+	// Rate limits asking to wait longer than this are returned to the caller instead of waited out.
+	maxRetryAfter = 30 * time.Second
+)
+
+// isRetryable tells if a failed request may succeed when sent again: network errors, server errors
+// and rate limits are retried, other errors are not going to change by retrying.
+func isRetryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var (
+		rateLimited    RateLimitedError
+		sessionExpired SessionExpiredError
+		cloudflare     CloudflareError
+		graphQL        GraphQLError
+		status         UnexpectedStatusCode
+	)
+	switch {
+	case errors.As(err, &rateLimited):
+		return rateLimited.RetryAfter <= maxRetryAfter
+	case errors.As(err, &sessionExpired), errors.As(err, &cloudflare), errors.As(err, &graphQL):
+		return false
+	case errors.As(err, &status):
+		return status.Code >= http.StatusInternalServerError
+	}
+	return true
+}
+
+// retryDelay waits as long as Retry-After asks, or backs off exponentially with jitter, so that the retries
+// of clients failed at the same time don't hit LeetCode at the same time again.
+func retryDelay(n uint, err error, _ *retry.Config) time.Duration {
+	var rateLimited RateLimitedError
+	if errors.As(err, &rateLimited) && rateLimited.RetryAfter > 0 {
+		return rateLimited.RetryAfter
+	}
+	d := retryMaxDelay
+	if n < 16 {
+		d = min(retryBaseDelay<<n, retryMaxDelay)
+	}
+	return d/2 + rand.N(d/2)
+}
+
 //nolint:unused
 func (c *cnClient) graphqlGet(req graphqlRequest, result any) (*http.Response, error) {
 	type params struct {
@@ -370,7 +448,7 @@ func (c *cnClient) Login(username, password string) (*http.Response, error) {
 			"variables":     nil,
 		},
 	).Request()
-	resp, err := c.http.Do(req, nil, nil)
+	resp, err := c.http.Do(req.WithContext(c.opt.ctx), nil, nil)
 	if err != nil {
 		return resp, err
 	}
@@ -395,7 +473,7 @@ func (c *cnClient) Login(username, password string) (*http.Response, error) {
 	if err != nil {
 		return nil, err
 	}
-	resp, err = c.http.Do(req, nil, nil)
+	resp, err = c.http.Do(req.WithContext(c.opt.ctx), nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -448,6 +526,10 @@ func (c *cnClient) getQuestionData(slug string, query string, authType authType)
 		}, &resp,
 	)
 	if err != nil {
+		var graphQLErr GraphQLError
+		if errors.As(err, &graphQLErr) && graphQLErr.notFound() {
+			return nil, fmt.Errorf("%w: %w", ErrQuestionNotFound, err)
+		}
 		return nil, err
 	}
 	q := resp.Data.Question
@@ -455,7 +537,7 @@ func (c *cnClient) getQuestionData(slug string, query string, authType authType)
 		return nil, ErrQuestionNotFound
 	}
 	if q.IsPaidOnly && q.Content == "" {
-		return nil, ErrPaidOnlyQuestion
+		return nil, PaidOnlyError{Slug: slug}
 	}
 	return &q, nil
 }
@@ -542,7 +624,11 @@ func (c *cnClient) GetAllQuestions() ([]*QuestionData, error) {
 	var qs []*QuestionData
 	var respErr UnexpectedStatusCode
 	dec := progressDecoder{smartDecoder{LogResponse: false}, tracker}
-	_, err = c.http.New().Get(url).ResponseDecoder(dec).Receive(&qs, &respErr)
+	req, err := c.http.New().Get(url).Request()
+	if err != nil {
+		return nil, err
+	}
+	_, err = c.http.New().ResponseDecoder(dec).Do(req.WithContext(c.opt.ctx), &qs, &respErr)
 	if err != nil {
 		return nil, err
 	}
@@ -678,7 +764,7 @@ func (c *cnClient) GetContestQuestionData(contestSlug string, questionSlug strin
 	if err != nil {
 		var e UnexpectedStatusCode
 		if errors.As(err, &e) && e.Code == 302 {
-			return nil, ErrPaidOnlyQuestion
+			return nil, PaidOnlyError{Slug: questionSlug, Response: e}
 		}
 		return nil, err
 	}
@@ -808,6 +894,109 @@ func (c *cnClient) CheckResult(submissionId string) (
 	return &r, err
 }
 
+func (c *cnClient) GetSubmissions(slug string, offset int, limit int) (SubmissionList, error) {
+	query := `
+query submissionList($offset: Int!, $limit: Int!, $lastKey: String, $questionSlug: String!) {
+  submissionList(offset: $offset, limit: $limit, lastKey: $lastKey, questionSlug: $questionSlug) {
+    hasNext
+    submissions {
+      id
+      title
+      statusDisplay
+      lang
+      langName
+      runtime
+      memory
+      timestamp
+    }
+  }
+}`
+	var resp gjson.Result
+	_, err := c.graphqlPost(
+		graphqlRequest{
+			query:         query,
+			operationName: "submissionList",
+			variables: map[string]any{
+				"offset":       offset,
+				"limit":        limit,
+				"lastKey":      nil,
+				"questionSlug": slug,
+			},
+			authType: requireAuth,
+		}, &resp,
+	)
+	if err != nil {
+		return SubmissionList{}, err
+	}
+	list := resp.Get("data.submissionList")
+	if !list.Exists() || list.Type == gjson.Null {
+		return SubmissionList{}, errors.New("failed to get submissions, you may need to login again")
+	}
+	result := SubmissionList{HasMore: list.Get("hasNext").Bool()}
+	for _, s := range list.Get("submissions").Array() {
+		result.Submissions = append(
+			result.Submissions, &Submission{
+				Id:            s.Get("id").String(),
+				Title:         s.Get("title").String(),
+				StatusDisplay: s.Get("statusDisplay").String(),
+				Lang:          s.Get("lang").String(),
+				LangName:      s.Get("langName").String(),
+				Runtime:       s.Get("runtime").String(),
+				Memory:        s.Get("memory").String(),
+				// The timestamp is a string of seconds.
+				Timestamp: s.Get("timestamp").Int(),
+			},
+		)
+	}
+	return result, nil
+}
+
+func (c *cnClient) GetSubmissionDetail(id string) (*SubmissionDetail, error) {
+	query := `
+query mySubmissionDetail($id: ID!) {
+  submissionDetail(submissionId: $id) {
+    id
+    code
+    runtime
+    memory
+    statusDisplay
+    timestamp
+    lang
+    question {
+      questionId
+      titleSlug
+    }
+  }
+}`
+	var resp gjson.Result
+	_, err := c.graphqlPost(
+		graphqlRequest{
+			query:         query,
+			operationName: "mySubmissionDetail",
+			variables:     map[string]any{"id": id},
+			authType:      requireAuth,
+		}, &resp,
+	)
+	if err != nil {
+		return nil, err
+	}
+	detail := resp.Get("data.submissionDetail")
+	if detail.Type == gjson.Null || !detail.Exists() {
+		return nil, fmt.Errorf("submission %s not found", id)
+	}
+	return &SubmissionDetail{
+		Id:            detail.Get("id").String(),
+		QuestionId:    detail.Get("question.questionId").String(),
+		TitleSlug:     detail.Get("question.titleSlug").String(),
+		StatusDisplay: detail.Get("statusDisplay").String(),
+		Lang:          detail.Get("lang").String(),
+		Runtime:       detail.Get("runtime").String(),
+		Memory:        detail.Get("memory").String(),
+		Timestamp:     detail.Get("timestamp").Int(),
+		Code:          detail.Get("code").String(),
+	}, nil
+}
+
 func (c *cnClient) GetUpcomingContests() ([]*Contest, error) {
 	query := `
 {
@@ -0,0 +1,145 @@
+package leetcode
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// ContextClient has the variants of the methods of Client that take a context, which bounds all the requests
+// of the call, including the waits between retries. Calling a variant is the same as calling the method on
+// WithContext(ctx).
+type ContextClient interface {
+	InspectContext(ctx context.Context, typ string) (map[string]any, error)
+	LoginContext(ctx context.Context, username, password string) (*http.Response, error)
+	GetUserStatusContext(ctx context.Context) (*UserStatus, error)
+	GetQuestionDataContext(ctx context.Context, slug string) (*QuestionData, error)
+	GetAllQuestionsContext(ctx context.Context) ([]*QuestionData, error)
+	GetTodayQuestionContext(ctx context.Context) (*QuestionData, error)
+	GetQuestionOfDateContext(ctx context.Context, date time.Time) (*QuestionData, error)
+	GetQuestionsByFilterContext(ctx context.Context, f QuestionFilter, limit int, skip int) (QuestionList, error)
+	GetQuestionTagsContext(ctx context.Context) ([]QuestionTag, error)
+	RunCodeContext(ctx context.Context, q *QuestionData, lang string, code string, dataInput string) (
+		*InterpretSolutionResult,
+		error,
+	)
+	SubmitCodeContext(ctx context.Context, q *QuestionData, lang string, code string) (string, error)
+	CheckResultContext(ctx context.Context, interpretId string) (CheckResult, error)
+	GetSubmissionsContext(ctx context.Context, slug string, offset int, limit int) (SubmissionList, error)
+	GetSubmissionDetailContext(ctx context.Context, id string) (*SubmissionDetail, error)
+	GetUpcomingContestsContext(ctx context.Context) ([]*Contest, error)
+	GetContestContext(ctx context.Context, contestSlug string) (*Contest, error)
+	GetContestQuestionDataContext(ctx context.Context, contestSlug string, questionSlug string) (*QuestionData, error)
+	RegisterContestContext(ctx context.Context, slug string) error
+	UnregisterContestContext(ctx context.Context, slug string) error
+	GetStreakCounterContext(ctx context.Context) (StreakCounter, error)
+}
+
+// contextMethods implements ContextClient for the clients, by calling the methods of the client bound to
+// the context. It holds the outermost client, so that the overridden methods of usClient are called.
+type contextMethods struct {
+	client Client
+}
+
+func (m contextMethods) InspectContext(ctx context.Context, typ string) (map[string]any, error) {
+	return m.client.WithContext(ctx).Inspect(typ)
+}
+
+func (m contextMethods) LoginContext(ctx context.Context, username, password string) (*http.Response, error) {
+	return m.client.WithContext(ctx).Login(username, password)
+}
+
+func (m contextMethods) GetUserStatusContext(ctx context.Context) (*UserStatus, error) {
+	return m.client.WithContext(ctx).GetUserStatus()
+}
+
+func (m contextMethods) GetQuestionDataContext(ctx context.Context, slug string) (*QuestionData, error) {
+	return m.client.WithContext(ctx).GetQuestionData(slug)
+}
+
+func (m contextMethods) GetAllQuestionsContext(ctx context.Context) ([]*QuestionData, error) {
+	return m.client.WithContext(ctx).GetAllQuestions()
+}
+
+func (m contextMethods) GetTodayQuestionContext(ctx context.Context) (*QuestionData, error) {
+	return m.client.WithContext(ctx).GetTodayQuestion()
+}
+
+func (m contextMethods) GetQuestionOfDateContext(ctx context.Context, date time.Time) (*QuestionData, error) {
+	return m.client.WithContext(ctx).GetQuestionOfDate(date)
+}
+
+func (m contextMethods) GetQuestionsByFilterContext(
+	ctx context.Context,
+	f QuestionFilter,
+	limit int,
+	skip int,
+) (QuestionList, error) {
+	return m.client.WithContext(ctx).GetQuestionsByFilter(f, limit, skip)
+}
+
+func (m contextMethods) GetQuestionTagsContext(ctx context.Context) ([]QuestionTag, error) {
+	return m.client.WithContext(ctx).GetQuestionTags()
+}
+
+func (m contextMethods) RunCodeContext(
+	ctx context.Context,
+	q *QuestionData,
+	lang string,
+	code string,
+	dataInput string,
+) (*InterpretSolutionResult, error) {
+	return m.client.WithContext(ctx).RunCode(q, lang, code, dataInput)
+}
+
+func (m contextMethods) SubmitCodeContext(ctx context.Context, q *QuestionData, lang string, code string) (
+	string,
+	error,
+) {
+	return m.client.WithContext(ctx).SubmitCode(q, lang, code)
+}
+
+func (m contextMethods) CheckResultContext(ctx context.Context, interpretId string) (CheckResult, error) {
+	return m.client.WithContext(ctx).CheckResult(interpretId)
+}
+
+func (m contextMethods) GetSubmissionsContext(
+	ctx context.Context,
+	slug string,
+	offset int,
+	limit int,
+) (SubmissionList, error) {
+	return m.client.WithContext(ctx).GetSubmissions(slug, offset, limit)
+}
+
+func (m contextMethods) GetSubmissionDetailContext(ctx context.Context, id string) (*SubmissionDetail, error) {
+	return m.client.WithContext(ctx).GetSubmissionDetail(id)
+}
+
+func (m contextMethods) GetUpcomingContestsContext(ctx context.Context) ([]*Contest, error) {
+	return m.client.WithContext(ctx).GetUpcomingContests()
+}
+
+func (m contextMethods) GetContestContext(ctx context.Context, contestSlug string) (*Contest, error) {
+	return m.client.WithContext(ctx).GetContest(contestSlug)
+}
+
+func (m contextMethods) GetContestQuestionDataContext(
+	ctx context.Context,
+	contestSlug string,
+	questionSlug string,
+) (*QuestionData, error) {
+	return m.client.WithContext(ctx).GetContestQuestionData(contestSlug, questionSlug)
+}
+
+func (m contextMethods) RegisterContestContext(ctx context.Context, slug string) error {
+	return m.client.WithContext(ctx).RegisterContest(slug)
+}
+
+func (m contextMethods) UnregisterContestContext(ctx context.Context, slug string) error {
+	return m.client.WithContext(ctx).UnregisterContest(slug)
+}
+
+func (m contextMethods) GetStreakCounterContext(ctx context.Context) (StreakCounter, error) {
+	return m.client.WithContext(ctx).GetStreakCounter()
+}
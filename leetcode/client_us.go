@@ -1,6 +1,7 @@
 package leetcode
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
@@ -20,6 +21,12 @@ type usClient struct {
 	cnClient
 }
 
+func (c *usClient) WithContext(ctx context.Context) Client {
+	cc := *c
+	cc.opt.ctx = ctx
+	return &cc
+}
+
 func (c *usClient) BaseURI() string {
 	return string(config.LeetCodeUS) + "/"
 }
@@ -203,7 +210,7 @@ func (c *usClient) GetContestQuestionData(contestSlug string, questionSlug strin
 	if err != nil {
 		var e UnexpectedStatusCode
 		if errors.As(err, &e) && e.Code == 302 {
-			return nil, ErrPaidOnlyQuestion
+			return nil, PaidOnlyError{Slug: questionSlug, Response: e}
 		}
 		return nil, err
 	}
@@ -224,7 +231,7 @@ func (c *usClient) GetUpcomingContests() ([]*Contest, error) {
 	var html []byte
 	req, _ := c.http.New().Get("/contest/").Request()
 	// Cannot c.send() here, sending with cookies can be easily rejected.
-	_, err := c.http.Do(req, &html, nil)
+	_, err := c.http.Do(req.WithContext(c.opt.ctx), &html, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -365,3 +372,69 @@ func (c *usClient) GetQuestionTags() ([]QuestionTag, error) {
 func (c *usClient) GetStreakCounter() (StreakCounter, error) {
 	return StreakCounter{}, errors.ErrUnsupported
 }
+
+// statusDisplays are the verdicts of status codes, leetcode.com only returns the code in submission details.
+var statusDisplays = map[int]string{
+	int(Accepted):            "Accepted",
+	int(WrongAnswer):         "Wrong Answer",
+	int(MemoryLimitExceeded): "Memory Limit Exceeded",
+	int(OutputLimitExceeded): "Output Limit Exceeded",
+	int(TimeLimitExceeded):   "Time Limit Exceeded",
+	int(RuntimeError):        "Runtime Error",
+	int(CompileError):        "Compile Error",
+}
+
+func (c *usClient) GetSubmissionDetail(id string) (*SubmissionDetail, error) {
+	submissionId, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid submission id: %s", id)
+	}
+	query := `
+query submissionDetails($submissionId: Int!) {
+  submissionDetails(submissionId: $submissionId) {
+    runtimeDisplay
+    memoryDisplay
+    code
+    timestamp
+    statusCode
+    lang {
+      name
+    }
+    question {
+      questionId
+      titleSlug
+    }
+  }
+}`
+	var resp gjson.Result
+	_, err = c.graphqlPost(
+		graphqlRequest{
+			query:         query,
+			operationName: "submissionDetails",
+			variables:     map[string]any{"submissionId": submissionId},
+			authType:      requireAuth,
+		}, &resp,
+	)
+	if err != nil {
+		return nil, err
+	}
+	detail := resp.Get("data.submissionDetails")
+	if detail.Type == gjson.Null || !detail.Exists() {
+		return nil, fmt.Errorf("submission %s not found", id)
+	}
+	status := statusDisplays[int(detail.Get("statusCode").Int())]
+	if status == "" {
+		status = "Unknown"
+	}
+	return &SubmissionDetail{
+		Id:            id,
+		QuestionId:    detail.Get("question.questionId").String(),
+		TitleSlug:     detail.Get("question.titleSlug").String(),
+		StatusDisplay: status,
+		Lang:          detail.Get("lang.name").String(),
+		Runtime:       detail.Get("runtimeDisplay").String(),
+		Memory:        detail.Get("memoryDisplay").String(),
+		Timestamp:     detail.Get("timestamp").Int(),
+		Code:          detail.Get("code").String(),
+	}, nil
+}
@@ -80,6 +80,11 @@ func (d smartDecoder) Decode(resp *http.Response, v interface{}) error {
 
 	ty := reflect.TypeOf(v)
 	ele := reflect.ValueOf(v).Elem()
+	if ty.Elem() != errorType && strings.HasPrefix(resp.Request.URL.Path, graphQLPath) {
+		if err := graphQLErrors(data); err != nil {
+			return err
+		}
+	}
 	switch ty.Elem() {
 	case gjsonType:
 		if d.path == "" {
@@ -92,7 +97,7 @@ func (d smartDecoder) Decode(resp *http.Response, v interface{}) error {
 	case stringType:
 		ele.SetString(utils.BytesToString(data))
 	case errorType:
-		ele.Set(reflect.ValueOf(NewUnexpectedStatusCode(resp.StatusCode, data)))
+		ele.Set(reflect.ValueOf(newResponseError(resp, data)))
 	default:
 		return json.Unmarshal(data, v)
 	}
@@ -0,0 +1,224 @@
+package leetcode
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tidwall/gjson"
+
+	"github.com/j178/leetgo/utils"
+)
+
+var (
+	ErrPaidOnlyQuestion  = errors.New("this is paid only question, you need to subscribe to LeetCode Premium")
+	ErrQuestionNotFound  = errors.New("no such question")
+	ErrContestNotStarted = errors.New("contest has not started")
+)
+
+type UnexpectedStatusCode struct {
+	Code int
+	Body string
+
+	retryAfter time.Duration
+	cloudflare bool
+}
+
+func (e UnexpectedStatusCode) IsError() bool {
+	return e.Code != 0
+}
+
+func (e UnexpectedStatusCode) Error() string {
+	body := e.Body
+	if body == "" {
+		body = "<empty body>"
+	} else if len(body) > 500 {
+		body = e.Body[:500] + "..."
+	}
+	return fmt.Sprintf("[%d %s] %s", e.Code, http.StatusText(e.Code), body)
+}
+
+func (e UnexpectedStatusCode) status() string {
+	return fmt.Sprintf("[%d %s]", e.Code, http.StatusText(e.Code))
+}
+
+func NewUnexpectedStatusCode(code int, body []byte) UnexpectedStatusCode {
+	err := UnexpectedStatusCode{Code: code}
+	switch code {
+	case http.StatusTooManyRequests:
+		err.Body = "LeetCode limited you access rate, you may be submitting too frequently"
+	case http.StatusForbidden:
+		err.Body = "Access is forbidden, your cookies may have expired or LeetCode has restricted its API access"
+	default:
+		err.Body = utils.BytesToString(body)
+	}
+	return err
+}
+
+// newResponseError is NewUnexpectedStatusCode that also keeps what the typed errors need from the response.
+func newResponseError(resp *http.Response, body []byte) UnexpectedStatusCode {
+	err := NewUnexpectedStatusCode(resp.StatusCode, body)
+	err.retryAfter = parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+	err.cloudflare = isCloudflareChallenge(resp, body)
+	return err
+}
+
+// typed returns the typed error of the cause of the status code if known, or the error itself otherwise.
+// authenticated tells whether the request was sent with credentials.
+func (e UnexpectedStatusCode) typed(authenticated bool) error {
+	switch {
+	case e.cloudflare:
+		return CloudflareError{e}
+	case e.Code == http.StatusTooManyRequests:
+		return RateLimitedError{e, e.retryAfter}
+	case e.Code == http.StatusUnauthorized, e.Code == http.StatusForbidden && authenticated:
+		return SessionExpiredError{e}
+	}
+	return e
+}
+
+// PaidOnlyError means the question is only available to LeetCode Premium subscribers. It wraps
+// ErrPaidOnlyQuestion, so errors.Is(err, ErrPaidOnlyQuestion) still works.
+type PaidOnlyError struct {
+	Slug string
+	// Response is the response that denied the question, or a zero value if the question was recognized as
+	// paid only from its data.
+	Response UnexpectedStatusCode
+}
+
+func (e PaidOnlyError) Error() string {
+	msg := fmt.Sprintf("%s is a paid only question, you need to subscribe to LeetCode Premium", e.Slug)
+	if e.Response.IsError() {
+		msg = e.Response.status() + " " + msg
+	}
+	return msg
+}
+
+func (e PaidOnlyError) Unwrap() []error {
+	if e.Response.IsError() {
+		return []error{ErrPaidOnlyQuestion, e.Response}
+	}
+	return []error{ErrPaidOnlyQuestion}
+}
+
+// SessionExpiredError means LeetCode rejected the credentials, they need to be updated.
+type SessionExpiredError struct {
+	UnexpectedStatusCode
+}
+
+func (e SessionExpiredError) Error() string {
+	return e.status() + " your LeetCode session has expired, please update your credentials"
+}
+
+func (e SessionExpiredError) Unwrap() error {
+	return e.UnexpectedStatusCode
+}
+
+// CloudflareError means the request was blocked by a challenge of Cloudflare, which is meant to be solved in a
+// browser.
+type CloudflareError struct {
+	UnexpectedStatusCode
+}
+
+func (e CloudflareError) Error() string {
+	return e.status() +
+		" blocked by the Cloudflare challenge of LeetCode, try again later or update the cookies from your browser"
+}
+
+func (e CloudflareError) Unwrap() error {
+	return e.UnexpectedStatusCode
+}
+
+// RateLimitedError means LeetCode limited the access rate. RetryAfter is the time to wait before the next request
+// if LeetCode tells it, or 0 otherwise.
+type RateLimitedError struct {
+	UnexpectedStatusCode
+	RetryAfter time.Duration
+}
+
+func (e RateLimitedError) Error() string {
+	msg := e.status() + " LeetCode limited your access rate, you may be sending requests too frequently"
+	if e.RetryAfter > 0 {
+		msg += fmt.Sprintf(", retry after %s", e.RetryAfter)
+	}
+	return msg
+}
+
+func (e RateLimitedError) Unwrap() error {
+	return e.UnexpectedStatusCode
+}
+
+// GraphQLError is the `errors` of a GraphQL response that has no data.
+type GraphQLError struct {
+	Messages []string
+}
+
+func (e GraphQLError) Error() string {
+	return "graphql: " + strings.Join(e.Messages, "; ")
+}
+
+// notFound reports whether the errors say the requested object doesn't exist, like
+// "That question does not exist!" of leetcode.com and "问题不存在" of leetcode.cn.
+func (e GraphQLError) notFound() bool {
+	for _, msg := range e.Messages {
+		msg = strings.ToLower(msg)
+		if strings.Contains(msg, "does not exist") || strings.Contains(msg, "not found") ||
+			strings.Contains(msg, "不存在") {
+			return true
+		}
+	}
+	return false
+}
+
+// graphQLErrors returns the errors of a GraphQL response as a GraphQLError. Some responses carry errors of a few
+// fields along with the data of the others, they are not treated as errors.
+func graphQLErrors(body []byte) error {
+	errs := gjson.GetBytes(body, "errors").Array()
+	if len(errs) == 0 {
+		return nil
+	}
+	hasData := false
+	gjson.GetBytes(body, "data").ForEach(
+		func(_, v gjson.Result) bool {
+			hasData = v.Type != gjson.Null
+			return !hasData
+		},
+	)
+	if hasData {
+		return nil
+	}
+	e := GraphQLError{}
+	for _, err := range errs {
+		e.Messages = append(e.Messages, err.Get("message").String())
+	}
+	return e
+}
+
+// parseRetryAfter parses the Retry-After header, which is either seconds or an HTTP date.
+func parseRetryAfter(v string, now time.Time) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		return max(time.Duration(secs)*time.Second, 0)
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return max(t.Sub(now), 0)
+	}
+	return 0
+}
+
+func isCloudflareChallenge(resp *http.Response, body []byte) bool {
+	if resp.Header.Get("Cf-Mitigated") == "challenge" {
+		return true
+	}
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusServiceUnavailable {
+		return false
+	}
+	return strings.HasPrefix(resp.Header.Get("Content-Type"), "text/html") &&
+		(bytes.Contains(body, []byte("challenge-platform")) || bytes.Contains(body, []byte("<title>Just a moment...")))
+}
@@ -260,6 +260,40 @@ type QuestionTag struct {
 	TypeTransName  string `json:"typeTransName"`
 }
 
+// Submission is an entry of the submission history of a question.
+type Submission struct {
+	Id            string
+	Title         string
+	StatusDisplay string // Accepted, Wrong Answer, ...
+	Lang          string // slug of the language, e.g. golang, cpp
+	LangName      string
+	Runtime       string // e.g. 4 ms
+	Memory        string // e.g. 6.2 MB
+	Timestamp     int64
+}
+
+func (s *Submission) Accepted() bool {
+	return s.StatusDisplay == "Accepted"
+}
+
+type SubmissionList struct {
+	Submissions []*Submission
+	HasMore     bool
+}
+
+// SubmissionDetail is a submission with its code.
+type SubmissionDetail struct {
+	Id            string
+	QuestionId    string
+	TitleSlug     string
+	StatusDisplay string
+	Lang          string
+	Runtime       string
+	Memory        string
+	Timestamp     int64
+	Code          string
+}
+
 type StreakCounter struct {
 	Today          string `json:"today"`
 	StreakCount    int    `json:"streakCount"`
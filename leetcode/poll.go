@@ -0,0 +1,84 @@
+package leetcode
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// PollOptions controls how WaitResult polls the result of a run or a submission.
+type PollOptions struct {
+	// Timeout is the longest time to wait for the result, 0 means waiting until the context is done.
+	Timeout time.Duration
+	// Interval is the interval between the first polls, it's doubled after each poll up to MaxInterval.
+	Interval    time.Duration
+	MaxInterval time.Duration
+}
+
+var DefaultPollOptions = PollOptions{
+	Timeout:     2 * time.Minute,
+	Interval:    500 * time.Millisecond,
+	MaxInterval: 5 * time.Second,
+}
+
+// StuckResultError means the result was still not judged when WaitResult gave up.
+type StuckResultError struct {
+	Id string
+	// State is the last state of the result, PENDING or STARTED.
+	State  string
+	Waited time.Duration
+}
+
+func (e StuckResultError) Error() string {
+	return fmt.Sprintf(
+		"result of %s is still %s after %s, LeetCode may be busy, check it on the website later",
+		e.Id,
+		e.State,
+		e.Waited.Round(time.Second),
+	)
+}
+
+// WaitResult polls the result of a run or a submission until it's judged, with the polls backing off
+// exponentially. It returns a StuckResultError if the result is not judged within opts.Timeout, or the error
+// of ctx if ctx is done first.
+func WaitResult(ctx context.Context, c Client, id string, opts PollOptions) (CheckResult, error) {
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultPollOptions.Interval
+	}
+	opts.MaxInterval = max(opts.MaxInterval, opts.Interval)
+	pollCtx := ctx
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		pollCtx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	state := "PENDING"
+	stuck := func(err error) error {
+		// The deadline of WaitResult is hit, not the one of the caller.
+		if ctx.Err() == nil && pollCtx.Err() != nil {
+			return StuckResultError{Id: id, State: state, Waited: time.Since(start)}
+		}
+		return err
+	}
+	interval := opts.Interval
+	for {
+		result, err := c.CheckResultContext(pollCtx, id)
+		if err != nil {
+			return nil, stuck(err)
+		}
+		state = result.GetState()
+		if state == "SUCCESS" {
+			return result, nil
+		}
+		timer := time.NewTimer(interval)
+		select {
+		case <-pollCtx.Done():
+			timer.Stop()
+			return nil, stuck(pollCtx.Err())
+		case <-timer.C:
+		}
+		interval = min(interval*2, opts.MaxInterval)
+	}
+}
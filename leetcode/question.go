@@ -160,6 +160,13 @@ type MetaData struct {
 	Constructor  MetaDataConstructor `json:"constructor"`
 	Methods      []MetaDataMethod    `json:"methods"`
 	Manual       bool                `json:"manual"`
+	// Database questions have no params, their inputs are tables.
+	Database bool `json:"database"`
+	// Statements that create empty DataFrames with the schema of the input tables, used by Pandas questions, e.g.
+	// "Person = pd.DataFrame([], columns=['personId', 'email']).astype({'personId':'Int64', 'email':'object'})"
+	PythonData []string `json:"pythondata"`
+	// Shell questions have no params, their input is a file read by the script.
+	Shell bool `json:"shell"`
 }
 
 type metaDataNoMethods MetaData
@@ -210,6 +217,9 @@ func (m *MetaData) NArg() int {
 	if m.SystemDesign {
 		return 2
 	}
+	if m.Database || m.Shell {
+		return 1
+	}
 	return len(m.Params)
 }
 
@@ -0,0 +1,288 @@
+package leetcode
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/goccy/go-json"
+
+	"github.com/j178/leetgo/config"
+	"github.com/j178/leetgo/utils"
+)
+
+// Fixture is a list of HTTP exchanges with a LeetCode site, recorded by Recorder and served by the fake server
+// in the leetcodetest package.
+type Fixture struct {
+	Site      config.LeetcodeSite `json:"site"`
+	Exchanges []Exchange          `json:"exchanges"`
+}
+
+// Exchange is a request and the response to it.
+type Exchange struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is a request without headers, credentials are never recorded: the secret fields of the body,
+// e.g. the password of a login, are redacted by scrubBody.
+type RecordedRequest struct {
+	Method string `json:"method"`
+	// URI is the path and the query of the request, e.g. /graphql or /submissions/detail/1/check/.
+	URI string `json:"uri"`
+	// JSON is the body of the request if it's a JSON, otherwise Body is.
+	JSON json.RawMessage `json:"json,omitempty"`
+	Body string          `json:"body,omitempty"`
+}
+
+// RecordedResponse is a response, its body is always decompressed.
+type RecordedResponse struct {
+	Status int               `json:"status"`
+	Header map[string]string `json:"header,omitempty"`
+	JSON   json.RawMessage   `json:"json,omitempty"`
+	Body   string            `json:"body,omitempty"`
+}
+
+// secretFields are the fields of form and JSON request bodies that are redacted in fixtures.
+var secretFields = map[string]bool{
+	"login":               true,
+	"password":            true,
+	"csrfmiddlewaretoken": true,
+	"csrftoken":           true,
+}
+
+const redacted = "REDACTED"
+
+// scrubBody redacts the secret fields of a form or JSON body, other bodies are returned unchanged.
+func scrubBody(body []byte) []byte {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return body
+	}
+	var v any
+	if err := json.Unmarshal(body, &v); err == nil {
+		if !scrubJSON(v) {
+			return body
+		}
+		scrubbed, err := json.Marshal(v)
+		if err != nil {
+			return body
+		}
+		return scrubbed
+	}
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		return body
+	}
+	found := false
+	for k := range form {
+		if secretFields[k] {
+			form.Set(k, redacted)
+			found = true
+		}
+	}
+	if !found {
+		return body
+	}
+	return []byte(form.Encode())
+}
+
+// scrubJSON redacts the secret fields in v in place, and reports whether there were any.
+func scrubJSON(v any) bool {
+	found := false
+	switch v := v.(type) {
+	case map[string]any:
+		for k, e := range v {
+			if _, isString := e.(string); isString && secretFields[k] {
+				v[k] = redacted
+				found = true
+			} else if scrubJSON(e) {
+				found = true
+			}
+		}
+	case []any:
+		for _, e := range v {
+			if scrubJSON(e) {
+				found = true
+			}
+		}
+	}
+	return found
+}
+
+// Only these response headers are recorded, the others are either irrelevant or sensitive, e.g. Set-Cookie.
+var recordedHeaders = []string{"Content-Type", "Location", "Retry-After", "Cf-Mitigated"}
+
+// LoadFixture reads a fixture file.
+func LoadFixture(path string) (*Fixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var f Fixture
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("invalid fixture %s: %w", path, err)
+	}
+	return &f, nil
+}
+
+// Save writes the fixture to a file.
+func (f *Fixture) Save(path string) error {
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	return utils.WriteFile(path, append(data, '\n'))
+}
+
+// Key returns the key of the request that the fake server matches incoming requests with.
+func (r RecordedRequest) Key() string {
+	body := []byte(r.JSON)
+	if len(body) == 0 {
+		body = []byte(r.Body)
+	}
+	return RequestKey(r.Method, r.URI, body)
+}
+
+var graphqlOperationRe = regexp.MustCompile(`^\s*(?:query|mutation)\s+(\w+)`)
+
+// RequestKey identifies a request by its method, URI and body, with the secret fields redacted. The query text of a GraphQL request is left out,
+// so that reformatting a query doesn't invalidate the fixtures, the operation name and the variables are kept.
+func RequestKey(method, uri string, body []byte) string {
+	key := method + " " + uri
+	// Recorded bodies are scrubbed, so are the bodies of incoming requests to match them.
+	body = scrubBody(body)
+	if len(bytes.TrimSpace(body)) == 0 {
+		return key
+	}
+	var graphql struct {
+		Query         *string        `json:"query"`
+		OperationName string         `json:"operationName"`
+		Variables     map[string]any `json:"variables"`
+	}
+	if err := json.Unmarshal(body, &graphql); err == nil && graphql.Query != nil {
+		op := graphql.OperationName
+		if op == "" {
+			// Some queries are sent without the operation name, take the name in the query.
+			if m := graphqlOperationRe.FindStringSubmatch(*graphql.Query); m != nil {
+				op = m[1]
+			}
+		}
+		vars, _ := json.Marshal(graphql.Variables)
+		return fmt.Sprintf("%s %s %s", key, op, vars)
+	}
+	// Compact JSON bodies, their formatting doesn't matter.
+	var v any
+	if err := json.Unmarshal(body, &v); err == nil {
+		body, _ = json.Marshal(v)
+	}
+	return key + " " + string(body)
+}
+
+// Recorder is an http.RoundTripper that records the exchanges going through it to a fixture file,
+// which is rewritten after every exchange. It's enabled in NewClient by the LEETGO_RECORD environment
+// variable, or plugged in with WithTransport.
+type Recorder struct {
+	mu        sync.Mutex
+	file      string
+	fixture   Fixture
+	transport http.RoundTripper
+}
+
+// NewRecorder returns a recorder that sends requests through transport, and records them to a new fixture file.
+func NewRecorder(file string, site config.LeetcodeSite, transport http.RoundTripper) *Recorder {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return &Recorder{
+		file:      file,
+		fixture:   Fixture{Site: site, Exchanges: []Exchange{}},
+		transport: transport,
+	}
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		reqBody, err = io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	resp, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := readDecompressed(resp)
+	if err != nil {
+		return nil, err
+	}
+	// The body is handed over decompressed.
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Set("Content-Length", strconv.Itoa(len(respBody)))
+	resp.ContentLength = int64(len(respBody))
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	ex := Exchange{
+		Request:  RecordedRequest{Method: req.Method, URI: req.URL.RequestURI()},
+		Response: RecordedResponse{Status: resp.StatusCode},
+	}
+	ex.Request.JSON, ex.Request.Body = splitJSONBody(scrubBody(reqBody))
+	ex.Response.JSON, ex.Response.Body = splitJSONBody(respBody)
+	for _, h := range recordedHeaders {
+		if v := resp.Header.Get(h); v != "" {
+			if ex.Response.Header == nil {
+				ex.Response.Header = make(map[string]string)
+			}
+			ex.Response.Header[h] = v
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.fixture.Exchanges = append(r.fixture.Exchanges, ex)
+	if err := r.fixture.Save(r.file); err != nil {
+		return nil, fmt.Errorf("failed to save fixture: %w", err)
+	}
+	return resp, nil
+}
+
+func readDecompressed(resp *http.Response) ([]byte, error) {
+	defer resp.Body.Close()
+	body := resp.Body
+	if strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		gz, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		body = gz
+	}
+	return io.ReadAll(body)
+}
+
+// splitJSONBody returns the body as a JSON if it's one, or as a string otherwise.
+func splitJSONBody(body []byte) (json.RawMessage, string) {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil, ""
+	}
+	if json.Valid(body) {
+		var buf bytes.Buffer
+		if err := json.Compact(&buf, body); err == nil {
+			return buf.Bytes(), ""
+		}
+	}
+	return nil, string(body)
+}
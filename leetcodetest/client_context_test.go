@@ -0,0 +1,46 @@
+package leetcodetest
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/j178/leetgo/leetcode"
+)
+
+// Every method of Client that talks to LeetCode has a variant taking a context.
+func TestContextVariants(t *testing.T) {
+	client := reflect.TypeOf((*leetcode.Client)(nil)).Elem()
+	ctxType := reflect.TypeOf((*context.Context)(nil)).Elem()
+	for i := 0; i < client.NumMethod(); i++ {
+		m := client.Method(i)
+		if m.Name == "WithContext" || m.Name == "BaseURI" || strings.HasSuffix(m.Name, "Context") {
+			continue
+		}
+		v, ok := client.MethodByName(m.Name + "Context")
+		if !ok {
+			t.Errorf("%s has no context variant", m.Name)
+			continue
+		}
+		want := []reflect.Type{ctxType}
+		for j := 0; j < m.Type.NumIn(); j++ {
+			want = append(want, m.Type.In(j))
+		}
+		var got []reflect.Type
+		for j := 0; j < v.Type.NumIn(); j++ {
+			got = append(got, v.Type.In(j))
+		}
+		if !reflect.DeepEqual(got, want) || !reflect.DeepEqual(results(v.Type), results(m.Type)) {
+			t.Errorf("%s%s doesn't match %s%s", v.Name, v.Type, m.Name, m.Type)
+		}
+	}
+}
+
+func results(f reflect.Type) []reflect.Type {
+	var out []reflect.Type
+	for i := 0; i < f.NumOut(); i++ {
+		out = append(out, f.Out(i))
+	}
+	return out
+}
@@ -0,0 +1,191 @@
+package leetcodetest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+
+	"github.com/j178/leetgo/config"
+	"github.com/j178/leetgo/leetcode"
+)
+
+var twoSum = map[string]any{"question": map[string]any{"titleSlug": "two-sum", "title": "Two Sum", "content": "..."}}
+
+// failed returns the exchange answered with the status, headers and body instead.
+func failed(ex leetcode.Exchange, status int, header map[string]string, body string) leetcode.Exchange {
+	ex.Response = leetcode.RecordedResponse{Status: status, Header: header, Body: body}
+	return ex
+}
+
+func checkResult(status int, header map[string]string) leetcode.Exchange {
+	return leetcode.Exchange{
+		Request:  leetcode.RecordedRequest{Method: http.MethodGet, URI: "/submissions/detail/42/check/"},
+		Response: leetcode.RecordedResponse{Status: status, Header: header},
+	}
+}
+
+func served(s *Server) int {
+	n := 0
+	for _, v := range s.Requests() {
+		n += v
+	}
+	return n
+}
+
+func TestRetryServerError(t *testing.T) {
+	s := NewServer(t, config.LeetCodeUS)
+	ok := GraphQL("questionData", map[string]any{"titleSlug": "two-sum"}, twoSum)
+	s.Add(failed(ok, http.StatusBadGateway, nil, "bad gateway"), ok)
+
+	q, err := s.Client(nil).GetQuestionData("two-sum")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.Title != "Two Sum" {
+		t.Errorf("title = %q", q.Title)
+	}
+	if n := served(s); n != 2 {
+		t.Errorf("served %d requests, want 2", n)
+	}
+}
+
+func TestRateLimited(t *testing.T) {
+	s := NewServer(t, config.LeetCodeUS)
+	ok := GraphQL("questionData", map[string]any{"titleSlug": "two-sum"}, twoSum)
+	s.Add(failed(ok, http.StatusTooManyRequests, map[string]string{"Retry-After": "120"}, ""))
+
+	_, err := s.Client(nil).GetQuestionData("two-sum")
+	var rateLimited leetcode.RateLimitedError
+	if !errors.As(err, &rateLimited) {
+		t.Fatalf("err = %v, want RateLimitedError", err)
+	}
+	if rateLimited.RetryAfter != 2*time.Minute {
+		t.Errorf("retry after = %s, want 2m", rateLimited.RetryAfter)
+	}
+	// Waiting that long is left to the caller.
+	if n := served(s); n != 1 {
+		t.Errorf("served %d requests, want 1", n)
+	}
+}
+
+func TestCloudflareChallenge(t *testing.T) {
+	s := NewServer(t, config.LeetCodeUS)
+	ok := GraphQL("questionData", map[string]any{"titleSlug": "two-sum"}, twoSum)
+	header := map[string]string{"Content-Type": "text/html; charset=UTF-8", "Cf-Mitigated": "challenge"}
+	s.Add(failed(ok, http.StatusForbidden, header, "<title>Just a moment...</title>"))
+
+	_, err := s.Client(nil).GetQuestionData("two-sum")
+	if !errors.As(err, &leetcode.CloudflareError{}) {
+		t.Fatalf("err = %v, want CloudflareError", err)
+	}
+	if n := served(s); n != 1 {
+		t.Errorf("served %d requests, want 1", n)
+	}
+}
+
+func TestSessionExpired(t *testing.T) {
+	s := NewServer(t, config.LeetCodeCN, &leetcode.Fixture{Exchanges: []leetcode.Exchange{checkResult(403, nil)}})
+	c := s.Client(leetcode.NewCookiesAuth("session", "csrftoken", ""))
+
+	_, err := c.CheckResult("42")
+	if !errors.As(err, &leetcode.SessionExpiredError{}) {
+		t.Fatalf("err = %v, want SessionExpiredError", err)
+	}
+	// The status code is still available.
+	var status leetcode.UnexpectedStatusCode
+	if !errors.As(err, &status) || status.Code != http.StatusForbidden {
+		t.Errorf("status = %v, want 403", status.Code)
+	}
+}
+
+func TestPaidOnlyContestQuestion(t *testing.T) {
+	s := NewServer(t, config.LeetCodeUS)
+	s.Add(
+		leetcode.Exchange{
+			Request: leetcode.RecordedRequest{Method: http.MethodGet, URI: "/contest/weekly-contest-1/problems/premium/"},
+			Response: leetcode.RecordedResponse{
+				Status: http.StatusFound,
+				Header: map[string]string{"Location": "/subscribe/"},
+			},
+		},
+	)
+
+	_, err := s.Client(leetcode.NewCookiesAuth("session", "csrftoken", "")).
+		GetContestQuestionData("weekly-contest-1", "premium")
+	var paidOnly leetcode.PaidOnlyError
+	if !errors.As(err, &paidOnly) {
+		t.Fatalf("err = %v, want PaidOnlyError", err)
+	}
+	if paidOnly.Slug != "premium" || paidOnly.Response.Code != http.StatusFound {
+		t.Errorf("err = %+v", paidOnly)
+	}
+	if !errors.Is(err, leetcode.ErrPaidOnlyQuestion) {
+		t.Errorf("err = %v, want %v", err, leetcode.ErrPaidOnlyQuestion)
+	}
+}
+
+func TestGraphQLError(t *testing.T) {
+	s := NewServer(t, config.LeetCodeUS)
+	ex := GraphQL("questionData", map[string]any{"titleSlug": "nope"}, nil)
+	ex.Response.JSON, _ = json.Marshal(
+		map[string]any{
+			"errors": []any{map[string]any{"message": "That question does not exist!"}},
+			"data":   map[string]any{"question": nil},
+		},
+	)
+	s.Add(ex)
+
+	_, err := s.Client(nil).GetQuestionData("nope")
+	var graphQLErr leetcode.GraphQLError
+	if !errors.As(err, &graphQLErr) {
+		t.Fatalf("err = %v, want GraphQLError", err)
+	}
+	if len(graphQLErr.Messages) != 1 || graphQLErr.Messages[0] != "That question does not exist!" {
+		t.Errorf("messages = %q", graphQLErr.Messages)
+	}
+	if !errors.Is(err, leetcode.ErrQuestionNotFound) {
+		t.Errorf("err = %v, want %v", err, leetcode.ErrQuestionNotFound)
+	}
+}
+
+func TestGraphQLErrorNotNotFound(t *testing.T) {
+	s := NewServer(t, config.LeetCodeUS)
+	ex := GraphQL("questionData", map[string]any{"titleSlug": "two-sum"}, nil)
+	ex.Response.JSON, _ = json.Marshal(
+		map[string]any{
+			"errors": []any{map[string]any{"message": "Cannot query field \"foo\" on type \"QuestionNode\"."}},
+			"data":   nil,
+		},
+	)
+	s.Add(ex)
+
+	_, err := s.Client(nil).GetQuestionData("two-sum")
+	if !errors.As(err, &leetcode.GraphQLError{}) {
+		t.Fatalf("err = %v, want GraphQLError", err)
+	}
+	if errors.Is(err, leetcode.ErrQuestionNotFound) {
+		t.Errorf("err = %v, want not %v", err, leetcode.ErrQuestionNotFound)
+	}
+}
+
+func TestContextCanceled(t *testing.T) {
+	s := NewServer(t, config.LeetCodeUS)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := s.Client(nil).WithContext(ctx).GetQuestionData("two-sum")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want %v", err, context.Canceled)
+	}
+	_, err = s.Client(nil).GetQuestionDataContext(ctx, "two-sum")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want %v", err, context.Canceled)
+	}
+	if n := served(s); n != 0 {
+		t.Errorf("served %d requests, want 0", n)
+	}
+}
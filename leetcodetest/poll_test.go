@@ -0,0 +1,63 @@
+package leetcodetest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/j178/leetgo/config"
+	"github.com/j178/leetgo/leetcode"
+)
+
+var fastPolling = leetcode.PollOptions{Timeout: time.Second, Interval: time.Millisecond, MaxInterval: 10 * time.Millisecond}
+
+func pendingResult(state string) leetcode.Exchange {
+	ex := checkResult(http.StatusOK, nil)
+	ex.Response.JSON = []byte(`{"state":"` + state + `"}`)
+	return ex
+}
+
+func TestWaitResult(t *testing.T) {
+	s := NewServer(t, config.LeetCodeCN, LoadFixture(t, "testdata/cn.json"))
+	c := s.Client(leetcode.NewCookiesAuth("session", "csrftoken", ""))
+
+	r, err := leetcode.WaitResult(context.Background(), c, "42", fastPolling)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.GetState() != "SUCCESS" {
+		t.Errorf("state = %s, want SUCCESS", r.GetState())
+	}
+}
+
+func TestWaitResultStuck(t *testing.T) {
+	s := NewServer(t, config.LeetCodeCN)
+	s.Add(pendingResult("PENDING"), pendingResult("STARTED"))
+	c := s.Client(leetcode.NewCookiesAuth("session", "csrftoken", ""))
+
+	opts := fastPolling
+	opts.Timeout = 100 * time.Millisecond
+	_, err := leetcode.WaitResult(context.Background(), c, "42", opts)
+	var stuck leetcode.StuckResultError
+	if !errors.As(err, &stuck) {
+		t.Fatalf("err = %v, want StuckResultError", err)
+	}
+	if stuck.State != "STARTED" || stuck.Waited < opts.Timeout {
+		t.Errorf("stuck = %+v", stuck)
+	}
+}
+
+func TestWaitResultCanceled(t *testing.T) {
+	s := NewServer(t, config.LeetCodeCN)
+	s.Add(pendingResult("PENDING"))
+	c := s.Client(leetcode.NewCookiesAuth("session", "csrftoken", ""))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := leetcode.WaitResult(ctx, c, "42", fastPolling)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
@@ -0,0 +1,170 @@
+// Package leetcodetest provides a fake LeetCode server for testing code built on the leetcode package
+// without network access. The server serves the exchanges of fixtures recorded by leetcode.Recorder,
+// e.g. by running leetgo with LEETGO_RECORD=fixture.json.
+package leetcodetest
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/goccy/go-json"
+
+	"github.com/j178/leetgo/config"
+	"github.com/j178/leetgo/leetcode"
+)
+
+// Server is a fake LeetCode server of a site, it answers the requests with the recorded responses of the same
+// requests. Repeated requests, e.g. polling the result of a submission, are answered by the recorded responses
+// in order, the last one is repeated once they are used up. Unknown requests fail the test.
+type Server struct {
+	*httptest.Server
+	t    testing.TB
+	site config.LeetcodeSite
+
+	mu        sync.Mutex
+	exchanges map[string][]leetcode.Exchange
+	served    map[string]int
+}
+
+// NewServer starts a fake server of the site serving the fixtures, it's closed when the test finishes.
+func NewServer(t testing.TB, site config.LeetcodeSite, fixtures ...*leetcode.Fixture) *Server {
+	t.Helper()
+	s := &Server{
+		t:         t,
+		site:      site,
+		exchanges: make(map[string][]leetcode.Exchange),
+		served:    make(map[string]int),
+	}
+	for _, f := range fixtures {
+		if f.Site != "" && f.Site != site {
+			t.Fatalf("fixture of %s can't be served as %s", f.Site, site)
+		}
+		s.Add(f.Exchanges...)
+	}
+	s.Server = httptest.NewServer(s)
+	t.Cleanup(s.Close)
+	return s
+}
+
+// LoadFixture reads a fixture file, it fails the test on errors.
+func LoadFixture(t testing.TB, path string) *leetcode.Fixture {
+	t.Helper()
+	f, err := leetcode.LoadFixture(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+// Add adds exchanges to serve, after the ones of the same requests.
+func (s *Server) Add(exchanges ...leetcode.Exchange) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, ex := range exchanges {
+		key := ex.Request.Key()
+		s.exchanges[key] = append(s.exchanges[key], ex)
+	}
+}
+
+// Site returns the site the server pretends to be.
+func (s *Server) Site() config.LeetcodeSite {
+	return s.site
+}
+
+// Transport returns a transport that sends the requests for the real site to the server.
+func (s *Server) Transport() http.RoundTripper {
+	target, _ := url.Parse(s.URL)
+	return &redirectTransport{target: target, base: s.Server.Client().Transport}
+}
+
+// Client returns a client of the site whose requests are served by the server.
+func (s *Server) Client(cred leetcode.CredentialsProvider) leetcode.Client {
+	if cred == nil {
+		cred = leetcode.NonAuth()
+	}
+	return leetcode.NewClient(cred, leetcode.WithSite(s.site), leetcode.WithTransport(s.Transport()))
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	key := leetcode.RequestKey(r.Method, r.URL.RequestURI(), body)
+
+	s.mu.Lock()
+	exchanges := s.exchanges[key]
+	n := s.served[key]
+	s.served[key]++
+	s.mu.Unlock()
+
+	if len(exchanges) == 0 {
+		s.t.Errorf("leetcodetest: no recorded response for %s", key)
+		http.Error(w, fmt.Sprintf("no recorded response for %s", key), http.StatusNotFound)
+		return
+	}
+	resp := exchanges[min(n, len(exchanges)-1)].Response
+	for k, v := range resp.Header {
+		w.Header().Set(k, v)
+	}
+	if resp.JSON != nil && w.Header().Get("Content-Type") == "" {
+		w.Header().Set("Content-Type", "application/json")
+	}
+	w.WriteHeader(resp.Status)
+	if resp.JSON != nil {
+		_, _ = w.Write(resp.JSON)
+	} else {
+		_, _ = io.WriteString(w, resp.Body)
+	}
+}
+
+// Requests returns how many times each request has been served, keyed by leetcode.RequestKey.
+func (s *Server) Requests() map[string]int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	served := make(map[string]int, len(s.served))
+	for k, v := range s.served {
+		served[k] = v
+	}
+	return served
+}
+
+// redirectTransport sends the requests to the target, keeping their paths.
+type redirectTransport struct {
+	target *url.URL
+	base   http.RoundTripper
+}
+
+func (t *redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = t.target.Scheme
+	req.URL.Host = t.target.Host
+	req.Host = t.target.Host
+	return t.base.RoundTrip(req)
+}
+
+// GraphQL returns an exchange of a GraphQL request to /graphql, answered with the data, for writing
+// exchanges by hand instead of recording them.
+func GraphQL(operationName string, variables map[string]any, data any) leetcode.Exchange {
+	if variables == nil {
+		variables = map[string]any{}
+	}
+	req, _ := json.Marshal(
+		map[string]any{
+			"query":         "",
+			"operationName": operationName,
+			"variables":     variables,
+		},
+	)
+	resp, _ := json.Marshal(map[string]any{"data": data})
+	return leetcode.Exchange{
+		Request:  leetcode.RecordedRequest{Method: http.MethodPost, URI: "/graphql", JSON: req},
+		Response: leetcode.RecordedResponse{Status: http.StatusOK, JSON: resp},
+	}
+}
@@ -0,0 +1,129 @@
+package leetcodetest
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/goccy/go-json"
+
+	"github.com/j178/leetgo/config"
+	"github.com/j178/leetgo/leetcode"
+)
+
+func TestServerCN(t *testing.T) {
+	s := NewServer(t, config.LeetCodeCN, LoadFixture(t, "testdata/cn.json"))
+	c := s.Client(leetcode.NewCookiesAuth("session", "csrftoken", ""))
+
+	user, err := c.GetUserStatus()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Username != "leetgo" {
+		t.Errorf("username = %q", user.Username)
+	}
+	q, err := c.GetQuestionData("two-sum")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.TranslatedTitle != "两数之和" {
+		t.Errorf("translated title = %q", q.TranslatedTitle)
+	}
+
+	// The recorded results are served in order, and the last one is repeated.
+	for _, want := range []string{"PENDING", "SUCCESS", "SUCCESS"} {
+		r, err := c.CheckResult("42")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.GetState() != want {
+			t.Errorf("state = %s, want %s", r.GetState(), want)
+		}
+	}
+}
+
+func TestServerUS(t *testing.T) {
+	s := NewServer(t, config.LeetCodeUS, LoadFixture(t, "testdata/us.json"))
+	c := s.Client(nil)
+
+	q, err := c.GetQuestionData("two-sum")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.Title != "Two Sum" || q.Difficulty != "Easy" {
+		t.Errorf("question = %+v", q)
+	}
+	_, err = c.GetQuestionData("premium")
+	if !errors.Is(err, leetcode.ErrPaidOnlyQuestion) {
+		t.Errorf("err = %v, want %v", err, leetcode.ErrPaidOnlyQuestion)
+	}
+	var paidOnly leetcode.PaidOnlyError
+	if !errors.As(err, &paidOnly) || paidOnly.Slug != "premium" {
+		t.Errorf("err = %v, want PaidOnlyError of premium", err)
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	data := map[string]any{"question": map[string]any{"titleSlug": "two-sum", "title": "Two Sum", "content": "..."}}
+	live := NewServer(t, config.LeetCodeUS)
+	live.Add(GraphQL("questionData", map[string]any{"titleSlug": "two-sum"}, data))
+
+	file := filepath.Join(t.TempDir(), "fixture.json")
+	recorder := leetcode.NewRecorder(file, config.LeetCodeUS, live.Transport())
+	c := leetcode.NewClient(leetcode.NonAuth(), leetcode.WithSite(config.LeetCodeUS), leetcode.WithTransport(recorder))
+	if _, err := c.GetQuestionData("two-sum"); err != nil {
+		t.Fatal(err)
+	}
+
+	replay := NewServer(t, config.LeetCodeUS, LoadFixture(t, file))
+	q, err := replay.Client(nil).GetQuestionData("two-sum")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.Title != "Two Sum" {
+		t.Errorf("title = %q", q.Title)
+	}
+}
+
+func TestRecorderScrubsLogin(t *testing.T) {
+	live := NewServer(t, config.LeetCodeCN)
+	global := GraphQL("nojGlobalData", nil, map[string]any{})
+	global.Request.JSON = json.RawMessage(`{"query":"","operationName":"nojGlobalData","variables":null}`)
+	global.Response.Header = map[string]string{"Set-Cookie": "csrftoken=secret-csrf; Path=/"}
+	login := leetcode.Exchange{
+		Request: leetcode.RecordedRequest{
+			Method: http.MethodPost,
+			URI:    "/accounts/login/",
+			Body:   "csrfmiddlewaretoken=REDACTED&login=REDACTED&password=REDACTED",
+		},
+		Response: leetcode.RecordedResponse{Status: http.StatusOK},
+	}
+	live.Add(global, login)
+
+	file := filepath.Join(t.TempDir(), "fixture.json")
+	recorder := leetcode.NewRecorder(file, config.LeetCodeCN, live.Transport())
+	c := leetcode.NewClient(leetcode.NonAuth(), leetcode.WithSite(config.LeetCodeCN), leetcode.WithTransport(recorder))
+	if _, err := c.Login("leetgo-user", "hunter2"); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"leetgo-user", "hunter2", "secret-csrf"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("fixture contains %q:\n%s", secret, data)
+		}
+	}
+	// The scrubbed fixture still answers the login, the cookie has to be given as Set-Cookie isn't recorded.
+	replay := NewServer(t, config.LeetCodeCN)
+	replay.Add(global)
+	replay.Add(LoadFixture(t, file).Exchanges...)
+	if _, err := replay.Client(nil).Login("someone", "else"); err != nil {
+		t.Fatal(err)
+	}
+}
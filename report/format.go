@@ -0,0 +1,196 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/goccy/go-json"
+	"gopkg.in/yaml.v3"
+)
+
+// Formats are the supported report formats.
+var Formats = []string{"json", "junit", "tap"}
+
+// Write writes the suites in the format.
+func Write(w io.Writer, format string, suites []*Suite) error {
+	switch format {
+	case "json":
+		return writeJSON(w, suites)
+	case "junit":
+		return writeJUnit(w, suites)
+	case "tap":
+		return writeTAP(w, suites)
+	default:
+		return fmt.Errorf("unknown report format: %s, available: %s", format, strings.Join(Formats, ", "))
+	}
+}
+
+func writeJSON(w io.Writer, suites []*Suite) error {
+	passed := true
+	for _, s := range suites {
+		passed = passed && s.Passed()
+	}
+	if suites == nil {
+		suites = []*Suite{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(
+		struct {
+			Passed bool     `json:"passed"`
+			Suites []*Suite `json:"suites"`
+		}{passed, suites},
+	)
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr,omitempty"`
+	Type    string `xml:"type,attr,omitempty"`
+	Text    string `xml:",chardata"`
+}
+
+type junitCase struct {
+	Name       string          `xml:"name,attr"`
+	Classname  string          `xml:"classname,attr"`
+	Time       string          `xml:"time,attr,omitempty"`
+	Properties []junitProperty `xml:"properties>property,omitempty"`
+	Failure    *junitMessage   `xml:"failure,omitempty"`
+	Skipped    *junitMessage   `xml:"skipped,omitempty"`
+	SystemOut  string          `xml:"system-out,omitempty"`
+}
+
+type junitSuite struct {
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Skipped  int         `xml:"skipped,attr"`
+	Time     string      `xml:"time,attr,omitempty"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+type junitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Name     string       `xml:"name,attr"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Skipped  int          `xml:"skipped,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+// seconds formats milliseconds as seconds, which JUnit uses for time.
+func seconds(ms float64) string {
+	if ms == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%.3f", ms/1000)
+}
+
+// details renders the fields of a failed case as plain text.
+func (c *Case) details() string {
+	var sb strings.Builder
+	for _, f := range []struct{ name, value string }{
+		{"Message", c.Message},
+		{"Input", c.Input},
+		{"Expected", c.Expected},
+		{"Actual", c.Actual},
+	} {
+		if f.value != "" {
+			fmt.Fprintf(&sb, "%s:\n%s\n", f.name, f.value)
+		}
+	}
+	return sb.String()
+}
+
+func writeJUnit(w io.Writer, suites []*Suite) error {
+	root := junitSuites{Name: "leetgo"}
+	for _, s := range suites {
+		failed, skipped := s.Count()
+		js := junitSuite{
+			Name:     s.Name(),
+			Tests:    len(s.Cases),
+			Failures: failed,
+			Skipped:  skipped,
+			Time:     seconds(s.Runtime),
+		}
+		for _, c := range s.Cases {
+			jc := junitCase{
+				Name:      c.Name,
+				Classname: s.Question,
+				Time:      seconds(c.Runtime),
+				SystemOut: c.Stdout,
+			}
+			if c.Memory > 0 {
+				jc.Properties = append(jc.Properties, junitProperty{"memory_bytes", fmt.Sprint(c.Memory)})
+			}
+			switch {
+			case c.Skipped:
+				jc.Skipped = &junitMessage{Message: c.Verdict}
+			case !c.Passed:
+				jc.Failure = &junitMessage{Message: c.Verdict, Type: c.Verdict, Text: c.details()}
+			}
+			js.Cases = append(js.Cases, jc)
+		}
+		root.Tests += js.Tests
+		root.Failures += js.Failures
+		root.Skipped += js.Skipped
+		root.Suites = append(root.Suites, js)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(root); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func writeTAP(w io.Writer, suites []*Suite) error {
+	total := 0
+	for _, s := range suites {
+		total += len(s.Cases)
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "TAP version 13\n1..%d\n", total)
+	n := 0
+	for _, s := range suites {
+		fmt.Fprintf(&sb, "# %s\n", s.Name())
+		for _, c := range s.Cases {
+			n++
+			status := "ok"
+			if !c.Passed && !c.Skipped {
+				status = "not ok"
+			}
+			fmt.Fprintf(&sb, "%s %d - %s: %s", status, n, s.Name(), c.Name)
+			if c.Skipped {
+				fmt.Fprintf(&sb, " # SKIP %s", c.Verdict)
+			}
+			sb.WriteString("\n")
+			if c.Passed || c.Skipped {
+				continue
+			}
+			// Diagnostics of a failed case are a YAML block.
+			diag, err := yaml.Marshal(c)
+			if err != nil {
+				return err
+			}
+			sb.WriteString("  ---\n")
+			for _, line := range strings.Split(strings.TrimSuffix(string(diag), "\n"), "\n") {
+				sb.WriteString("  " + line + "\n")
+			}
+			sb.WriteString("  ...\n")
+		}
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
@@ -0,0 +1,160 @@
+// Package report collects the results of local tests, remote tests and submissions in a structured form,
+// and writes them in machine-readable formats for CI: JSON, JUnit XML and TAP.
+package report
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/j178/leetgo/leetcode"
+)
+
+// Kinds of suites.
+const (
+	LocalTest  = "local test"
+	RemoteTest = "remote test"
+	Submit     = "submit"
+)
+
+// Case is the result of a single test case.
+type Case struct {
+	Name     string  `json:"name" yaml:"-"`
+	Verdict  string  `json:"verdict" yaml:"verdict"`
+	Passed   bool    `json:"passed" yaml:"-"`
+	Skipped  bool    `json:"skipped" yaml:"-"`
+	Message  string  `json:"message,omitempty" yaml:"message,omitempty"`
+	Input    string  `json:"input,omitempty" yaml:"input,omitempty"`
+	Expected string  `json:"expected,omitempty" yaml:"expected,omitempty"`
+	Actual   string  `json:"actual,omitempty" yaml:"actual,omitempty"`
+	Stdout   string  `json:"stdout,omitempty" yaml:"stdout,omitempty"`
+	Runtime  float64 `json:"runtime_ms,omitempty" yaml:"runtime_ms,omitempty"`
+	Memory   uint64  `json:"memory_bytes,omitempty" yaml:"memory_bytes,omitempty"`
+}
+
+// Suite is the results of a question tested in one way, e.g. the local test of `two-sum`.
+type Suite struct {
+	Question string  `json:"question"`
+	Kind     string  `json:"kind"`
+	Runtime  float64 `json:"runtime_ms,omitempty"`
+	Memory   uint64  `json:"memory_bytes,omitempty"`
+	Cases    []Case  `json:"cases"`
+}
+
+func (s *Suite) Name() string {
+	return s.Question + " (" + s.Kind + ")"
+}
+
+// Passed reports whether none of the cases failed.
+func (s *Suite) Passed() bool {
+	for _, c := range s.Cases {
+		if !c.Passed && !c.Skipped {
+			return false
+		}
+	}
+	return true
+}
+
+// Count returns the number of failed and skipped cases.
+func (s *Suite) Count() (failed int, skipped int) {
+	for _, c := range s.Cases {
+		switch {
+		case c.Skipped:
+			skipped++
+		case !c.Passed:
+			failed++
+		}
+	}
+	return failed, skipped
+}
+
+// ErrorSuite reports an error that prevented the question from being tested.
+func ErrorSuite(q *leetcode.QuestionData, kind string, err error) *Suite {
+	return &Suite{
+		Question: q.TitleSlug,
+		Kind:     kind,
+		Cases:    []Case{{Name: "Error", Verdict: "Error", Message: err.Error()}},
+	}
+}
+
+// parseRuntime parses the runtime reported by LeetCode, like "4 ms", to milliseconds.
+func parseRuntime(s string) float64 {
+	v, _ := strconv.ParseFloat(strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(s), "ms")), 64)
+	return v
+}
+
+// FromRunCheckResult converts the result of a remote test.
+func FromRunCheckResult(q *leetcode.QuestionData, r *leetcode.RunCheckResult) *Suite {
+	s := &Suite{
+		Question: q.TitleSlug,
+		Kind:     RemoteTest,
+		Runtime:  parseRuntime(r.StatusRuntime),
+		Memory:   uint64(max(r.Memory, 0)),
+	}
+	if leetcode.StatusCode(r.StatusCode) == leetcode.CompileError {
+		s.Cases = append(s.Cases, Case{Name: "Compile", Verdict: r.StatusMsg, Message: r.FullCompileError})
+		return s
+	}
+
+	narg := max(q.MetaData.NArg(), 1)
+	lines := strings.Split(strings.TrimSuffix(r.InputData, "\n"), "\n")
+	at := func(l []string, i int) string {
+		if i < len(l) {
+			return l[i]
+		}
+		return ""
+	}
+	for i := 0; i*narg < len(lines); i++ {
+		c := Case{
+			Name:     fmt.Sprintf("Case %d", i+1),
+			Input:    strings.Join(lines[i*narg:min((i+1)*narg, len(lines))], "\n"),
+			Expected: at(r.ExpectedCodeAnswer, i),
+			Actual:   at(r.CodeAnswer, i),
+			Stdout:   at(r.StdOutputList, i),
+		}
+		switch {
+		case i < len(r.CompareResult) && r.CompareResult[i] == '1':
+			c.Verdict, c.Passed = "Accepted", true
+		case i < len(r.CompareResult):
+			c.Verdict = "Wrong Answer"
+		case i == len(r.CompareResult) && !r.Accepted():
+			c.Verdict, c.Message = r.StatusMsg, r.FullRuntimeError
+		default:
+			c.Verdict, c.Skipped = "Not run", true
+		}
+		s.Cases = append(s.Cases, c)
+	}
+	return s
+}
+
+// FromSubmitCheckResult converts the result of a submission, which is reported as a single case.
+func FromSubmitCheckResult(q *leetcode.QuestionData, r *leetcode.SubmitCheckResult) *Suite {
+	c := Case{
+		Name:    "Submission",
+		Verdict: r.StatusMsg,
+		Passed:  r.Accepted(),
+		Runtime: parseRuntime(r.StatusRuntime),
+		Memory:  uint64(max(r.Memory, 0)),
+	}
+	switch leetcode.StatusCode(r.StatusCode) {
+	case leetcode.CompileError:
+		c.Message = r.FullCompileError
+	case leetcode.RuntimeError:
+		c.Message = r.FullRuntimeError
+	default:
+		c.Message = fmt.Sprintf("passed %d/%d cases", r.TotalCorrect, r.TotalTestcases)
+	}
+	if !c.Passed {
+		c.Input = r.LastTestcase
+		c.Expected = r.ExpectedOutput
+		c.Actual = r.CodeOutput
+		c.Stdout = r.StdOutput
+	}
+	return &Suite{
+		Question: q.TitleSlug,
+		Kind:     Submit,
+		Runtime:  c.Runtime,
+		Memory:   c.Memory,
+		Cases:    []Case{c},
+	}
+}
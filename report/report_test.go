@@ -0,0 +1,74 @@
+package report
+
+import (
+	"strings"
+	"testing"
+)
+
+func testSuites() []*Suite {
+	return []*Suite{
+		{
+			Question: "two-sum",
+			Kind:     LocalTest,
+			Cases: []Case{
+				{Name: "Case 1", Verdict: "Passed", Passed: true, Input: "[2,7]\n9", Runtime: 12, Memory: 1024},
+				{
+					Name:     "Case 2",
+					Verdict:  "Wrong answer",
+					Message:  `expected "[0,1]", got "[1,0]"`,
+					Input:    "[3,3]\n6",
+					Expected: "[0,1]",
+					Actual:   "[1,0]",
+				},
+				{Name: "Case 3", Verdict: "Skipped", Skipped: true},
+			},
+		},
+	}
+}
+
+func TestWrite(t *testing.T) {
+	tests := []struct {
+		format string
+		want   []string
+	}{
+		{
+			"json",
+			[]string{`"passed": false`, `"question": "two-sum"`, `"runtime_ms": 12`, `"memory_bytes": 1024`},
+		},
+		{
+			"junit",
+			[]string{
+				`<testsuites name="leetgo" tests="3" failures="1" skipped="1">`,
+				`<testsuite name="two-sum (local test)" tests="3" failures="1" skipped="1">`,
+				`<testcase name="Case 1" classname="two-sum" time="0.012">`,
+				`<failure message="Wrong answer" type="Wrong answer">`,
+				`<skipped message="Skipped"></skipped>`,
+			},
+		},
+		{
+			"tap",
+			[]string{
+				"TAP version 13\n1..3\n# two-sum (local test)\n",
+				"ok 1 - two-sum (local test): Case 1\n",
+				"not ok 2 - two-sum (local test): Case 2\n  ---\n  verdict: Wrong answer\n",
+				"  input: |-\n      [3,3]\n      6\n",
+				"ok 3 - two-sum (local test): Case 3 # SKIP Skipped\n",
+			},
+		},
+	}
+	for _, tt := range tests {
+		var sb strings.Builder
+		if err := Write(&sb, tt.format, testSuites()); err != nil {
+			t.Fatalf("%s: %v", tt.format, err)
+		}
+		for _, want := range tt.want {
+			if !strings.Contains(sb.String(), want) {
+				t.Errorf("%s: %q not found in:\n%s", tt.format, want, sb.String())
+			}
+		}
+	}
+
+	if err := Write(&strings.Builder{}, "xml", nil); err == nil {
+		t.Error("unknown format should be rejected")
+	}
+}
@@ -0,0 +1,365 @@
+#ifndef LC_IO_H
+#define LC_IO_H
+
+#include <ctype.h>
+#include <limits.h>
+#include <math.h>
+#include <stdarg.h>
+#include <stdbool.h>
+#include <stdint.h>
+#include <stdio.h>
+#include <stdlib.h>
+#include <string.h>
+
+/**
+ * Definition for a singly-linked list.
+ */
+struct ListNode {
+    int val;
+    struct ListNode *next;
+};
+
+/**
+ * Definition for a binary tree node.
+ */
+struct TreeNode {
+    int val;
+    struct TreeNode *left;
+    struct TreeNode *right;
+};
+
+/**
+ * A growable string buffer, outputs are collected into it before being printed.
+ */
+typedef struct {
+    char *data;
+    size_t len;
+    size_t cap;
+} lc_buf;
+
+static inline void lc_buf_grow(lc_buf *b, size_t n) {
+    if (b->len + n + 1 <= b->cap) { return; }
+    while (b->len + n + 1 > b->cap) { b->cap = b->cap ? b->cap * 2 : 64; }
+    b->data = (char *) realloc(b->data, b->cap);
+}
+
+static inline void lc_buf_putc(lc_buf *b, char c) {
+    lc_buf_grow(b, 1);
+    b->data[b->len++] = c;
+    b->data[b->len] = '\0';
+}
+
+static inline void lc_buf_puts(lc_buf *b, const char *s) {
+    size_t n = strlen(s);
+    lc_buf_grow(b, n);
+    memcpy(b->data + b->len, s, n + 1);
+    b->len += n;
+}
+
+static inline void lc_buf_printf(lc_buf *b, const char *fmt, ...) {
+    va_list args;
+    va_start(args, fmt);
+    int n = vsnprintf(NULL, 0, fmt, args);
+    va_end(args);
+    lc_buf_grow(b, (size_t) n);
+    va_start(args, fmt);
+    vsnprintf(b->data + b->len, (size_t) n + 1, fmt, args);
+    va_end(args);
+    b->len += (size_t) n;
+}
+
+/**
+ * Reads a line from stdin, the returned buffer is reused by the next call.
+ */
+static inline const char *lc_read_line(void) {
+    static lc_buf line;
+    int c;
+    line.len = 0;
+    lc_buf_grow(&line, 0);
+    line.data[0] = '\0';
+    while ((c = getchar()) != EOF && c != '\n') {
+        lc_buf_putc(&line, (char) c);
+    }
+    return line.data;
+}
+
+static inline void lc_skip_ws(const char **p) {
+    while (isspace((unsigned char) **p)) { ++*p; }
+}
+
+static inline void lc_expect(const char **p, char c) {
+    lc_skip_ws(p);
+    if (**p != c) {
+        fprintf(stderr, "LC_IO: expected '%c', got '%s'\n", c, *p);
+        exit(1);
+    }
+    ++*p;
+}
+
+static inline bool lc_try_consume(const char **p, char c) {
+    lc_skip_ws(p);
+    if (**p != c) { return false; }
+    ++*p;
+    return true;
+}
+
+/**
+ * Functions for scanning scalar values.
+ */
+static inline int lc_scan_int(const char **p) {
+    char *end;
+    long x = strtol(*p, &end, 10);
+    *p = end;
+    return (int) x;
+}
+
+static inline long long lc_scan_long(const char **p) {
+    char *end;
+    long long x = strtoll(*p, &end, 10);
+    *p = end;
+    return x;
+}
+
+static inline double lc_scan_double(const char **p) {
+    char *end;
+    double x = strtod(*p, &end);
+    *p = end;
+    return x;
+}
+
+static inline bool lc_scan_bool(const char **p) {
+    lc_skip_ws(p);
+    bool x = **p == 't';
+    *p += x ? 4 : 5;
+    return x;
+}
+
+static inline char lc_scan_escaped(const char **p) {
+    char c = *(*p)++;
+    if (c != '\\') { return c; }
+    switch (c = *(*p)++) {
+    case 'n': return '\n';
+    case 't': return '\t';
+    case 'r': return '\r';
+    case 'b': return '\b';
+    case 'f': return '\f';
+    case 'u': {
+        char hex[5] = {0};
+        memcpy(hex, *p, 4);
+        *p += 4;
+        return (char) strtol(hex, NULL, 16);
+    }
+    default: return c;
+    }
+}
+
+static inline char lc_scan_char(const char **p) {
+    lc_skip_ws(p);
+    char quote = *(*p)++;
+    char x = lc_scan_escaped(p);
+    lc_expect(p, quote);
+    return x;
+}
+
+static inline char *lc_scan_string(const char **p) {
+    lc_buf s = {0};
+    lc_skip_ws(p);
+    char quote = *(*p)++;
+    lc_buf_grow(&s, 0);
+    s.data[0] = '\0';
+    while (**p != quote) { lc_buf_putc(&s, lc_scan_escaped(p)); }
+    ++*p;
+    return s.data;
+}
+
+static inline struct ListNode *lc_scan_list(const char **p) {
+    struct ListNode dummy = {0, NULL}, *now = &dummy;
+    lc_expect(p, '[');
+    if (lc_try_consume(p, ']')) { return NULL; }
+    do {
+        struct ListNode *node = (struct ListNode *) malloc(sizeof(struct ListNode));
+        node->val = lc_scan_int(p);
+        node->next = NULL;
+        now = now->next = node;
+    } while (lc_try_consume(p, ','));
+    lc_expect(p, ']');
+    return dummy.next;
+}
+
+static inline struct TreeNode *lc_scan_tree(const char **p) {
+    int n = 0, cap = 16;
+    struct TreeNode **nodes = (struct TreeNode **) malloc(sizeof(struct TreeNode *) * cap);
+    lc_expect(p, '[');
+    if (!lc_try_consume(p, ']')) {
+        do {
+            if (n == cap) {
+                cap *= 2;
+                nodes = (struct TreeNode **) realloc(nodes, sizeof(struct TreeNode *) * cap);
+            }
+            lc_skip_ws(p);
+            if (**p == 'n') {
+                *p += 4;
+                nodes[n++] = NULL;
+            } else {
+                struct TreeNode *node = (struct TreeNode *) malloc(sizeof(struct TreeNode));
+                node->val = lc_scan_int(p);
+                node->left = node->right = NULL;
+                nodes[n++] = node;
+            }
+        } while (lc_try_consume(p, ','));
+        lc_expect(p, ']');
+    }
+    for (int i = 0, j = 1; i < n && j < n; ++i) {
+        if (nodes[i] == NULL) { continue; }
+        nodes[i]->left = nodes[j++];
+        if (j < n) { nodes[i]->right = nodes[j++]; }
+    }
+    struct TreeNode *root = n ? nodes[0] : NULL;
+    free(nodes);
+    return root;
+}
+
+/**
+ * Functions for printing scalar values.
+ */
+static inline void lc_print_int(lc_buf *out, int x) { lc_buf_printf(out, "%d", x); }
+
+static inline void lc_print_long(lc_buf *out, long long x) { lc_buf_printf(out, "%lld", x); }
+
+static inline void lc_print_double(lc_buf *out, double x) { lc_buf_printf(out, "%.5f", x); }
+
+static inline void lc_print_bool(lc_buf *out, bool x) { lc_buf_puts(out, x ? "true" : "false"); }
+
+static inline void lc_print_escaped(lc_buf *out, char c) {
+    if (c == '"' || c == '\\') { lc_buf_putc(out, '\\'); }
+    lc_buf_putc(out, c);
+}
+
+static inline void lc_print_char(lc_buf *out, char x) {
+    lc_buf_putc(out, '"');
+    lc_print_escaped(out, x);
+    lc_buf_putc(out, '"');
+}
+
+static inline void lc_print_string(lc_buf *out, const char *x) {
+    if (x == NULL) {
+        lc_buf_puts(out, "null");
+        return;
+    }
+    lc_buf_putc(out, '"');
+    for (; *x; ++x) { lc_print_escaped(out, *x); }
+    lc_buf_putc(out, '"');
+}
+
+static inline void lc_print_list(lc_buf *out, const struct ListNode *node) {
+    lc_buf_putc(out, '[');
+    for (const struct ListNode *now = node; now != NULL; now = now->next) {
+        if (now != node) { lc_buf_putc(out, ','); }
+        lc_print_int(out, now->val);
+    }
+    lc_buf_putc(out, ']');
+}
+
+static inline void lc_print_tree(lc_buf *out, struct TreeNode *root) {
+    int head = 0, tail = 0, cap = 16, not_null = 0, last = 0;
+    struct TreeNode **q = (struct TreeNode **) malloc(sizeof(struct TreeNode *) * cap);
+    lc_buf_putc(out, '[');
+    if (root != NULL) {
+        q[tail++] = root;
+        not_null = 1;
+    }
+    /* stop as soon as no non-null node is left in the queue, so trailing nulls are omitted */
+    while (not_null > 0) {
+        struct TreeNode *node = q[head++];
+        if (last++) { lc_buf_putc(out, ','); }
+        if (node == NULL) {
+            lc_buf_puts(out, "null");
+            continue;
+        }
+        --not_null;
+        lc_print_int(out, node->val);
+        if (tail + 2 > cap) {
+            cap *= 2;
+            q = (struct TreeNode **) realloc(q, sizeof(struct TreeNode *) * cap);
+        }
+        q[tail++] = node->left;
+        q[tail++] = node->right;
+        not_null += (node->left != NULL) + (node->right != NULL);
+    }
+    lc_buf_putc(out, ']');
+    free(q);
+}
+
+/**
+ * Defines functions to scan and print one and two dimensional arrays:
+ * T *lc_scan_<name>_array(const char **p, int *size)
+ * T **lc_scan_<name>_matrix(const char **p, int *size, int **colSizes)
+ * void lc_print_<name>_array(lc_buf *out, T *arr, int size)
+ * void lc_print_<name>_matrix(lc_buf *out, T **arr, int size, int *colSizes)
+ */
+#define LC_DEFINE_ARRAY_IO(name, T)                                                      \
+    static inline T *lc_scan_##name##_array(const char **p, int *size) {                 \
+        int n = 0, cap = 16;                                                             \
+        T *arr = (T *) malloc(sizeof(T) * cap);                                          \
+        lc_expect(p, '[');                                                               \
+        if (!lc_try_consume(p, ']')) {                                                   \
+            do {                                                                         \
+                if (n == cap) {                                                          \
+                    cap *= 2;                                                            \
+                    arr = (T *) realloc(arr, sizeof(T) * cap);                           \
+                }                                                                        \
+                arr[n++] = lc_scan_##name(p);                                            \
+            } while (lc_try_consume(p, ','));                                            \
+            lc_expect(p, ']');                                                           \
+        }                                                                                \
+        *size = n;                                                                       \
+        return arr;                                                                      \
+    }                                                                                    \
+    static inline T **lc_scan_##name##_matrix(const char **p, int *size, int **colSizes) { \
+        int n = 0, cap = 16;                                                             \
+        T **arr = (T **) malloc(sizeof(T *) * cap);                                      \
+        int *cols = (int *) malloc(sizeof(int) * cap);                                   \
+        lc_expect(p, '[');                                                               \
+        if (!lc_try_consume(p, ']')) {                                                   \
+            do {                                                                         \
+                if (n == cap) {                                                          \
+                    cap *= 2;                                                            \
+                    arr = (T **) realloc(arr, sizeof(T *) * cap);                        \
+                    cols = (int *) realloc(cols, sizeof(int) * cap);                     \
+                }                                                                        \
+                arr[n] = lc_scan_##name##_array(p, &cols[n]);                            \
+                ++n;                                                                     \
+            } while (lc_try_consume(p, ','));                                            \
+            lc_expect(p, ']');                                                           \
+        }                                                                                \
+        *size = n;                                                                       \
+        *colSizes = cols;                                                                \
+        return arr;                                                                      \
+    }                                                                                    \
+    static inline void lc_print_##name##_array(lc_buf *out, T *arr, int size) {          \
+        lc_buf_putc(out, '[');                                                           \
+        for (int i = 0; i < size; ++i) {                                                 \
+            if (i) { lc_buf_putc(out, ','); }                                            \
+            lc_print_##name(out, arr[i]);                                                \
+        }                                                                                \
+        lc_buf_putc(out, ']');                                                           \
+    }                                                                                    \
+    static inline void lc_print_##name##_matrix(lc_buf *out, T **arr, int size, int *colSizes) { \
+        lc_buf_putc(out, '[');                                                           \
+        for (int i = 0; i < size; ++i) {                                                 \
+            if (i) { lc_buf_putc(out, ','); }                                            \
+            lc_print_##name##_array(out, arr[i], colSizes[i]);                           \
+        }                                                                                \
+        lc_buf_putc(out, ']');                                                           \
+    }
+
+LC_DEFINE_ARRAY_IO(int, int)
+LC_DEFINE_ARRAY_IO(long, long long)
+LC_DEFINE_ARRAY_IO(double, double)
+LC_DEFINE_ARRAY_IO(bool, bool)
+LC_DEFINE_ARRAY_IO(char, char)
+LC_DEFINE_ARRAY_IO(string, char *)
+LC_DEFINE_ARRAY_IO(list, struct ListNode *)
+LC_DEFINE_ARRAY_IO(tree, struct TreeNode *)
+
+#endif
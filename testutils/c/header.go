@@ -0,0 +1,13 @@
+package c
+
+import (
+	_ "embed"
+)
+
+const HeaderName = "LC_IO.h"
+
+// HeaderContent is the C port of the C++ LC_IO.h, it follows LeetCode's C calling convention
+// (returnSize, returnColumnSizes, malloc'd results).
+//
+//go:embed LC_IO.h
+var HeaderContent []byte
@@ -0,0 +1,59 @@
+#include "../LC_IO.h"
+
+int main_ret = 0;
+
+static void check(const char *raw, lc_buf *out) {
+    if (strcmp(raw, out->data) == 0) {
+        printf("passed\n");
+    } else {
+        printf("want: %s, got: %s\n", raw, out->data);
+        main_ret = 1;
+    }
+    free(out->data);
+}
+
+#define TEST_SCAN_PRINT(name, raw)                      \
+    do {                                                \
+        const char *p = raw;                            \
+        lc_buf out = {0};                               \
+        lc_print_##name(&out, lc_scan_##name(&p));      \
+        check(raw, &out);                               \
+    } while (0)
+
+#define TEST_SCAN_PRINT_MATRIX(name, raw)                          \
+    do {                                                           \
+        const char *p = raw;                                       \
+        lc_buf out = {0};                                          \
+        int size, *colSizes;                                       \
+        void *arr = lc_scan_##name##_matrix(&p, &size, &colSizes); \
+        lc_print_##name##_matrix(&out, arr, size, colSizes);       \
+        check(raw, &out);                                          \
+    } while (0)
+
+void test_all(void) {
+    TEST_SCAN_PRINT(int, "19890604");
+    TEST_SCAN_PRINT(long, "1989060419890604");
+    TEST_SCAN_PRINT(bool, "true");
+    TEST_SCAN_PRINT(char, "\"a\"");
+    TEST_SCAN_PRINT(string, "\"hello\"");
+    TEST_SCAN_PRINT(string, "\"say \\\"hi\\\"\"");
+    TEST_SCAN_PRINT(double, "1.98964");
+    TEST_SCAN_PRINT(list, "[19,89,0,6,0,4]");
+    TEST_SCAN_PRINT(list, "[]");
+    TEST_SCAN_PRINT(tree, "[1989,null,6,null,4]");
+    TEST_SCAN_PRINT(tree, "[]");
+
+    TEST_SCAN_PRINT_MATRIX(int, "[[1989,6,4],[19890604],[]]");
+    TEST_SCAN_PRINT_MATRIX(long, "[[1989060419890604,1989,6,4],[1989060419890604],[]]");
+    TEST_SCAN_PRINT_MATRIX(bool, "[[true,false,true],[false],[]]");
+    TEST_SCAN_PRINT_MATRIX(char, "[[\"t\",\"i\",\"a\",\"n\"],[\"s\",\"q\"],[]]");
+    TEST_SCAN_PRINT_MATRIX(string, "[[\"tiananmen\",\"square\"],[\"\"],[]]");
+    TEST_SCAN_PRINT_MATRIX(double, "[[1989.06040,19.89640],[1.98964],[]]");
+    TEST_SCAN_PRINT_MATRIX(list, "[[[19,89,0,6,0,4],[1989,6,4]],[[19890604]],[]]");
+    TEST_SCAN_PRINT_MATRIX(tree, "[[[1989,null,6,null,4],[1989,6,4]],[[19890604]],[]]");
+}
+
+int main(void) {
+    test_all();
+    return main_ret;
+}
@@ -0,0 +1,313 @@
+using System;
+using System.Collections;
+using System.Collections.Generic;
+using System.Globalization;
+using System.IO;
+using System.Linq;
+using System.Reflection;
+using System.Runtime.ExceptionServices;
+using System.Text.Encodings.Web;
+using System.Text.Json;
+
+namespace Leetgo
+{
+    /// <summary>
+    /// Helpers used by the generated test harness to read test inputs and print outputs.
+    /// LeetCode metadata does not tell whether a C# method takes <c>int[]</c> or <c>IList&lt;int&gt;</c>,
+    /// so values are deserialized according to the reflected parameter types of the solution method.
+    /// </summary>
+    public static class LeetCodeIO
+    {
+        // The default encoder escapes HTML-sensitive and non-ASCII characters, which LeetCode doesn't.
+        private static readonly JsonSerializerOptions JsonOptions = new JsonSerializerOptions
+        {
+            Encoder = JavaScriptEncoder.UnsafeRelaxedJsonEscaping,
+        };
+
+        /// <summary>
+        /// Reads a line from the reader, throws EndOfStreamException if there is no more input.
+        /// </summary>
+        public static string ReadLine(TextReader reader)
+        {
+            var line = reader.ReadLine();
+            if (line == null)
+            {
+                throw new EndOfStreamException("unexpected end of input");
+            }
+            return line.Trim();
+        }
+
+        /// <summary>
+        /// Splits a JSON array which may contain values of different types into its raw elements.
+        /// </summary>
+        public static List<string> SplitArray(string raw)
+        {
+            raw = raw.Trim();
+            if (raw.Length < 2 || raw[0] != '[' || raw[^1] != ']')
+            {
+                throw new FormatException("invalid array: " + raw);
+            }
+            var res = new List<string>();
+            var depth = 0;
+            var inString = false;
+            var start = 1;
+            for (var i = 1; i < raw.Length - 1; i++)
+            {
+                var c = raw[i];
+                if (inString)
+                {
+                    if (c == '\\')
+                    {
+                        i++;
+                    }
+                    else if (c == '"')
+                    {
+                        inString = false;
+                    }
+                    continue;
+                }
+                switch (c)
+                {
+                    case '"':
+                        inString = true;
+                        break;
+                    case '[':
+                    case '{':
+                        depth++;
+                        break;
+                    case ']':
+                    case '}':
+                        depth--;
+                        break;
+                    case ',' when depth == 0:
+                        res.Add(raw.Substring(start, i - start).Trim());
+                        start = i + 1;
+                        break;
+                }
+            }
+            var last = raw.Substring(start, raw.Length - 1 - start).Trim();
+            if (last != "" || res.Count > 0)
+            {
+                res.Add(last);
+            }
+            return res;
+        }
+
+        public static string JoinArray(IEnumerable<string> values)
+        {
+            return "[" + string.Join(",", values) + "]";
+        }
+
+        /// <summary>
+        /// Returns the element type if the type is an array or a generic collection, otherwise null.
+        /// </summary>
+        private static Type GetElementType(Type type)
+        {
+            if (type.IsArray)
+            {
+                return type.GetElementType();
+            }
+            if (type.IsGenericType && type.GetGenericArguments().Length == 1 &&
+                typeof(IEnumerable).IsAssignableFrom(type))
+            {
+                return type.GetGenericArguments()[0];
+            }
+            return null;
+        }
+
+        public static object Deserialize(Type type, string raw)
+        {
+            raw = raw.Trim();
+            var elemType = GetElementType(type);
+            if (elemType != null && type != typeof(string))
+            {
+                var values = SplitArray(raw).Select(v => Deserialize(elemType, v)).ToList();
+                if (type.IsArray)
+                {
+                    var arr = Array.CreateInstance(elemType, values.Count);
+                    for (var i = 0; i < values.Count; i++)
+                    {
+                        arr.SetValue(values[i], i);
+                    }
+                    return arr;
+                }
+                // IList<T>, ICollection<T>, IEnumerable<T> and List<T> are all satisfied by List<T>.
+                var list = (IList)Activator.CreateInstance(typeof(List<>).MakeGenericType(elemType));
+                foreach (var v in values)
+                {
+                    list.Add(v);
+                }
+                return list;
+            }
+            if (type == typeof(int))
+            {
+                return int.Parse(raw, CultureInfo.InvariantCulture);
+            }
+            if (type == typeof(long))
+            {
+                return long.Parse(raw, CultureInfo.InvariantCulture);
+            }
+            if (type == typeof(double))
+            {
+                return double.Parse(raw, CultureInfo.InvariantCulture);
+            }
+            if (type == typeof(bool))
+            {
+                return raw switch
+                {
+                    "true" => true,
+                    "false" => false,
+                    _ => throw new FormatException("invalid boolean: " + raw),
+                };
+            }
+            if (type == typeof(char))
+            {
+                var s = JsonSerializer.Deserialize<string>(raw);
+                if (s == null || s.Length != 1)
+                {
+                    throw new FormatException("invalid character: " + raw);
+                }
+                return s[0];
+            }
+            if (type == typeof(string))
+            {
+                return JsonSerializer.Deserialize<string>(raw);
+            }
+            if (type == typeof(ListNode))
+            {
+                return ListNode.Deserialize(raw);
+            }
+            if (type == typeof(TreeNode))
+            {
+                return TreeNode.Deserialize(raw);
+            }
+            throw new NotSupportedException("unsupported type: " + type);
+        }
+
+        /// <summary>
+        /// Serializes a value of the declared type, an empty list or tree is printed as <c>[]</c> rather than null.
+        /// </summary>
+        public static string Serialize(object obj, Type type)
+        {
+            if (obj == null && (type == typeof(ListNode) || type == typeof(TreeNode)))
+            {
+                return "[]";
+            }
+            return Serialize(obj);
+        }
+
+        public static string Serialize(object obj)
+        {
+            switch (obj)
+            {
+                case null:
+                    return "null";
+                case bool b:
+                    return b ? "true" : "false";
+                case double d:
+                    return d.ToString("F5", CultureInfo.InvariantCulture);
+                case float f:
+                    return f.ToString("F5", CultureInfo.InvariantCulture);
+                case string s:
+                    return JsonSerializer.Serialize(s, JsonOptions);
+                case char c:
+                    return JsonSerializer.Serialize(c.ToString(), JsonOptions);
+                case IEnumerable e:
+                    var values = new List<string>();
+                    foreach (var v in e)
+                    {
+                        values.Add(Serialize(v));
+                    }
+                    return JoinArray(values);
+                case IFormattable n:
+                    return n.ToString(null, CultureInfo.InvariantCulture);
+                default:
+                    return obj.ToString();
+            }
+        }
+
+        /// <summary>
+        /// Finds a public method by name, ignoring case because C# methods are PascalCase on LeetCode.
+        /// </summary>
+        public static MethodInfo GetMethod(Type type, string name)
+        {
+            var methods = type.GetMethods(BindingFlags.Public | BindingFlags.Instance | BindingFlags.Static)
+                .Where(m => string.Equals(m.Name, name, StringComparison.OrdinalIgnoreCase))
+                .ToList();
+            if (methods.Count == 0)
+            {
+                throw new MissingMethodException(type.Name, name);
+            }
+            return methods[0];
+        }
+
+        public static ConstructorInfo GetConstructor(Type type)
+        {
+            var constructors = type.GetConstructors();
+            if (constructors.Length == 0)
+            {
+                throw new MissingMethodException(type.Name, ".ctor");
+            }
+            return constructors[0];
+        }
+
+        /// <summary>
+        /// Reads one line for each parameter of the method and deserializes it.
+        /// </summary>
+        public static object[] ReadArgs(TextReader reader, MethodBase method)
+        {
+            var parameters = method.GetParameters();
+            var args = new object[parameters.Length];
+            for (var i = 0; i < parameters.Length; i++)
+            {
+                args[i] = Deserialize(parameters[i].ParameterType, ReadLine(reader));
+            }
+            return args;
+        }
+
+        public static object[] DeserializeArgs(MethodBase method, IList<string> raw)
+        {
+            var parameters = method.GetParameters();
+            if (parameters.Length != raw.Count)
+            {
+                throw new ArgumentException(
+                    $"{method.Name} expects {parameters.Length} arguments, got {raw.Count}");
+            }
+            var args = new object[parameters.Length];
+            for (var i = 0; i < parameters.Length; i++)
+            {
+                args[i] = Deserialize(parameters[i].ParameterType, raw[i]);
+            }
+            return args;
+        }
+
+        /// <summary>
+        /// Invokes the method and rethrows the exception thrown by the solution as is.
+        /// </summary>
+        public static object Invoke(object obj, MethodInfo method, object[] args)
+        {
+            try
+            {
+                return method.Invoke(obj, args);
+            }
+            catch (TargetInvocationException e) when (e.InnerException != null)
+            {
+                ExceptionDispatchInfo.Capture(e.InnerException).Throw();
+                throw;
+            }
+        }
+
+        public static object NewInstance(ConstructorInfo constructor, object[] args)
+        {
+            try
+            {
+                return constructor.Invoke(args);
+            }
+            catch (TargetInvocationException e) when (e.InnerException != null)
+            {
+                ExceptionDispatchInfo.Capture(e.InnerException).Throw();
+                throw;
+            }
+        }
+    }
+}
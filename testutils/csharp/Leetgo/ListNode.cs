@@ -0,0 +1,43 @@
+using System.Collections.Generic;
+
+/// <summary>
+/// Definition for a singly-linked list.
+/// </summary>
+public class ListNode
+{
+    public int val;
+    public ListNode next;
+
+    public ListNode(int val = 0, ListNode next = null)
+    {
+        this.val = val;
+        this.next = next;
+    }
+
+    public static ListNode Deserialize(string raw)
+    {
+        var dummy = new ListNode();
+        var node = dummy;
+        foreach (var v in Leetgo.LeetCodeIO.SplitArray(raw))
+        {
+            node.next = new ListNode(int.Parse(v));
+            node = node.next;
+        }
+        return dummy.next;
+    }
+
+    public override string ToString()
+    {
+        var seen = new HashSet<ListNode>(ReferenceEqualityComparer.Instance);
+        var values = new List<string>();
+        for (var node = this; node != null; node = node.next)
+        {
+            if (!seen.Add(node))
+            {
+                throw new System.InvalidOperationException("infinite loop detected");
+            }
+            values.Add(node.val.ToString());
+        }
+        return "[" + string.Join(",", values) + "]";
+    }
+}
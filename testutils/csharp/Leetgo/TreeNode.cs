@@ -0,0 +1,74 @@
+using System.Collections.Generic;
+
+/// <summary>
+/// Definition for a binary tree node.
+/// </summary>
+public class TreeNode
+{
+    public int val;
+    public TreeNode left;
+    public TreeNode right;
+
+    public TreeNode(int val = 0, TreeNode left = null, TreeNode right = null)
+    {
+        this.val = val;
+        this.left = left;
+        this.right = right;
+    }
+
+    public static TreeNode Deserialize(string raw)
+    {
+        var values = Leetgo.LeetCodeIO.SplitArray(raw);
+        if (values.Count == 0 || values[0] == "null")
+        {
+            return null;
+        }
+        var nodes = new List<TreeNode>();
+        foreach (var v in values)
+        {
+            nodes.Add(v == "null" ? null : new TreeNode(int.Parse(v)));
+        }
+        var parents = new List<TreeNode> { nodes[0] };
+        for (int i = 1, p = 0; i < nodes.Count && p < parents.Count; p++)
+        {
+            var parent = parents[p];
+            parent.left = nodes[i++];
+            if (parent.left != null)
+            {
+                parents.Add(parent.left);
+            }
+            if (i < nodes.Count)
+            {
+                parent.right = nodes[i++];
+                if (parent.right != null)
+                {
+                    parents.Add(parent.right);
+                }
+            }
+        }
+        return nodes[0];
+    }
+
+    public override string ToString()
+    {
+        var values = new List<string>();
+        var queue = new List<TreeNode> { this };
+        for (var i = 0; i < queue.Count; i++)
+        {
+            var node = queue[i];
+            if (node == null)
+            {
+                values.Add("null");
+                continue;
+            }
+            values.Add(node.val.ToString());
+            queue.Add(node.left);
+            queue.Add(node.right);
+        }
+        while (values.Count > 0 && values[^1] == "null")
+        {
+            values.RemoveAt(values.Count - 1);
+        }
+        return "[" + string.Join(",", values) + "]";
+    }
+}
@@ -0,0 +1,12 @@
+package csharp
+
+import (
+	"embed"
+)
+
+// ProjectName is the name of the class library project that holds the test utilities.
+// It is written into `<out_dir>/Leetgo/` and referenced by the test project of each question.
+const ProjectName = "Leetgo"
+
+//go:embed Leetgo/*.cs
+var Sources embed.FS
@@ -0,0 +1,12 @@
+package java
+
+import (
+	"embed"
+)
+
+// PackageName is the Java package that holds the test utilities.
+// Sources are written into `<out_dir>/leetgo/` so that javac can find them through `-sourcepath`.
+const PackageName = "leetgo"
+
+//go:embed leetgo/*.java
+var Sources embed.FS
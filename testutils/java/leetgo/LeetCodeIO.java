@@ -0,0 +1,339 @@
+package leetgo;
+
+import java.io.BufferedReader;
+import java.io.EOFException;
+import java.io.IOException;
+import java.lang.reflect.Array;
+import java.lang.reflect.Constructor;
+import java.lang.reflect.Executable;
+import java.lang.reflect.InvocationTargetException;
+import java.lang.reflect.Method;
+import java.lang.reflect.Modifier;
+import java.lang.reflect.ParameterizedType;
+import java.lang.reflect.Type;
+import java.util.ArrayList;
+import java.util.Collection;
+import java.util.List;
+import java.util.Locale;
+
+/**
+ * Helpers used by the generated test harness to read test inputs and print outputs.
+ * <p>
+ * LeetCode metadata does not tell whether a Java method takes {@code int[]} or {@code List<Integer>},
+ * so values are deserialized according to the reflected parameter types of the solution method.
+ */
+public final class LeetCodeIO {
+    private LeetCodeIO() {}
+
+    /**
+     * Reads a line from the reader, throws EOFException if there is no more input.
+     */
+    public static String readLine(BufferedReader reader) throws IOException {
+        String line = reader.readLine();
+        if (line == null) {
+            throw new EOFException("unexpected end of input");
+        }
+        return line.trim();
+    }
+
+    /**
+     * Splits a JSON array which may contain values of different types into its raw elements.
+     */
+    public static List<String> splitArray(String raw) {
+        raw = raw.trim();
+        if (raw.length() < 2 || raw.charAt(0) != '[' || raw.charAt(raw.length() - 1) != ']') {
+            throw new IllegalArgumentException("invalid array: " + raw);
+        }
+        List<String> res = new ArrayList<>();
+        int depth = 0;
+        boolean inString = false;
+        int start = 1;
+        for (int i = 1; i < raw.length() - 1; i++) {
+            char c = raw.charAt(i);
+            if (inString) {
+                if (c == '\\') {
+                    i++;
+                } else if (c == '"') {
+                    inString = false;
+                }
+                continue;
+            }
+            switch (c) {
+                case '"':
+                    inString = true;
+                    break;
+                case '[':
+                case '{':
+                    depth++;
+                    break;
+                case ']':
+                case '}':
+                    depth--;
+                    break;
+                case ',':
+                    if (depth == 0) {
+                        res.add(raw.substring(start, i).trim());
+                        start = i + 1;
+                    }
+                    break;
+                default:
+                    break;
+            }
+        }
+        String last = raw.substring(start, raw.length() - 1).trim();
+        if (!last.isEmpty() || !res.isEmpty()) {
+            res.add(last);
+        }
+        return res;
+    }
+
+    /**
+     * Joins serialized elements into a JSON array.
+     */
+    public static String joinArray(List<String> values) {
+        return "[" + String.join(",", values) + "]";
+    }
+
+    /**
+     * Deserializes a raw value into the given (possibly generic) type.
+     */
+    public static Object deserialize(Type type, String raw) {
+        raw = raw.trim();
+        if (type instanceof ParameterizedType) {
+            ParameterizedType pt = (ParameterizedType) type;
+            Class<?> rawType = (Class<?>) pt.getRawType();
+            if (Collection.class.isAssignableFrom(rawType)) {
+                Type elemType = pt.getActualTypeArguments()[0];
+                List<Object> list = new ArrayList<>();
+                for (String s : splitArray(raw)) {
+                    list.add(deserialize(elemType, s));
+                }
+                return list;
+            }
+            throw new IllegalArgumentException("unsupported type: " + type);
+        }
+        if (!(type instanceof Class)) {
+            throw new IllegalArgumentException("unsupported type: " + type);
+        }
+        Class<?> cls = (Class<?>) type;
+        if (cls == int.class || cls == Integer.class) {
+            return Integer.parseInt(raw);
+        } else if (cls == long.class || cls == Long.class) {
+            return Long.parseLong(raw);
+        } else if (cls == double.class || cls == Double.class) {
+            return Double.parseDouble(raw);
+        } else if (cls == boolean.class || cls == Boolean.class) {
+            if (!raw.equals("true") && !raw.equals("false")) {
+                throw new IllegalArgumentException("invalid boolean: " + raw);
+            }
+            return raw.equals("true");
+        } else if (cls == char.class || cls == Character.class) {
+            String s = unquote(raw);
+            if (s.length() != 1) {
+                throw new IllegalArgumentException("invalid char: " + raw);
+            }
+            return s.charAt(0);
+        } else if (cls == String.class) {
+            return unquote(raw);
+        } else if (cls == ListNode.class) {
+            return ListNode.deserialize(raw);
+        } else if (cls == TreeNode.class) {
+            return TreeNode.deserialize(raw);
+        } else if (cls.isArray()) {
+            List<String> splits = splitArray(raw);
+            Object arr = Array.newInstance(cls.getComponentType(), splits.size());
+            for (int i = 0; i < splits.size(); i++) {
+                Array.set(arr, i, deserialize(cls.getComponentType(), splits.get(i)));
+            }
+            return arr;
+        } else if (cls == List.class) {
+            // Raw List type, keep the elements as strings.
+            return new ArrayList<>(splitArray(raw));
+        }
+        throw new IllegalArgumentException("unsupported type: " + cls.getName());
+    }
+
+    /**
+     * Serializes a value into its LeetCode representation.
+     */
+    public static String serialize(Object v) {
+        if (v == null) {
+            return "null";
+        } else if (v instanceof String) {
+            return quote((String) v);
+        } else if (v instanceof Character) {
+            return quote(String.valueOf(v));
+        } else if (v instanceof Double || v instanceof Float) {
+            return String.format(Locale.ROOT, "%.5f", ((Number) v).doubleValue());
+        } else if (v instanceof ListNode || v instanceof TreeNode) {
+            return v.toString();
+        } else if (v.getClass().isArray()) {
+            List<String> values = new ArrayList<>();
+            for (int i = 0; i < Array.getLength(v); i++) {
+                values.add(serialize(Array.get(v, i)));
+            }
+            return joinArray(values);
+        } else if (v instanceof Iterable) {
+            List<String> values = new ArrayList<>();
+            for (Object e : (Iterable<?>) v) {
+                values.add(serialize(e));
+            }
+            return joinArray(values);
+        }
+        // int, long, boolean
+        return v.toString();
+    }
+
+    /**
+     * Finds the method with the given name and number of parameters declared by the class.
+     * Public methods are preferred over helpers of the same name, and an ambiguous overload is an error.
+     */
+    public static Method getMethod(Class<?> cls, String name, int paramCount) {
+        List<Method> candidates = new ArrayList<>();
+        for (Method m : cls.getDeclaredMethods()) {
+            if (m.getName().equals(name) && m.getParameterCount() == paramCount && !m.isSynthetic()) {
+                candidates.add(m);
+            }
+        }
+        if (candidates.size() > 1) {
+            List<Method> publics = new ArrayList<>();
+            for (Method m : candidates) {
+                if (Modifier.isPublic(m.getModifiers())) {
+                    publics.add(m);
+                }
+            }
+            if (!publics.isEmpty()) {
+                candidates = publics;
+            }
+        }
+        String desc = "method " + name + " with " + paramCount + " parameters";
+        if (candidates.isEmpty()) {
+            throw new IllegalArgumentException(desc + " not found in " + cls.getName());
+        }
+        if (candidates.size() > 1) {
+            throw new IllegalArgumentException(desc + " is ambiguous in " + cls.getName() + ": " + candidates);
+        }
+        Method m = candidates.get(0);
+        m.setAccessible(true);
+        return m;
+    }
+
+    /**
+     * Finds the constructor of the class, the one with the most parameters wins.
+     */
+    public static Constructor<?> getConstructor(Class<?> cls) {
+        Constructor<?> res = null;
+        for (Constructor<?> c : cls.getDeclaredConstructors()) {
+            if (res == null || c.getParameterCount() > res.getParameterCount()) {
+                res = c;
+            }
+        }
+        if (res == null) {
+            throw new IllegalArgumentException("constructor not found in " + cls.getName());
+        }
+        res.setAccessible(true);
+        return res;
+    }
+
+    /**
+     * Reads one line per parameter of the method or constructor and deserializes them.
+     */
+    public static Object[] readArgs(BufferedReader reader, Executable e) throws IOException {
+        List<String> raws = new ArrayList<>();
+        for (int i = 0; i < e.getParameterCount(); i++) {
+            raws.add(readLine(reader));
+        }
+        return deserializeArgs(e, raws);
+    }
+
+    /**
+     * Deserializes raw arguments according to the parameter types of the method or constructor.
+     */
+    public static Object[] deserializeArgs(Executable e, List<String> raws) {
+        Type[] types = e.getGenericParameterTypes();
+        if (types.length != raws.size()) {
+            throw new IllegalArgumentException(
+                "expected " + types.length + " arguments for " + e.getName() + ", got " + raws.size()
+            );
+        }
+        Object[] args = new Object[types.length];
+        for (int i = 0; i < types.length; i++) {
+            args[i] = deserialize(types[i], raws.get(i));
+        }
+        return args;
+    }
+
+    /**
+     * Invokes the method, exceptions thrown by the solution are rethrown as is.
+     */
+    public static Object invoke(Object obj, Method m, Object[] args) throws Throwable {
+        try {
+            return m.invoke(obj, args);
+        } catch (InvocationTargetException ex) {
+            throw ex.getCause();
+        }
+    }
+
+    /**
+     * Creates a new instance, exceptions thrown by the constructor are rethrown as is.
+     */
+    public static Object newInstance(Constructor<?> c, Object[] args) throws Throwable {
+        try {
+            return c.newInstance(args);
+        } catch (InvocationTargetException ex) {
+            throw ex.getCause();
+        }
+    }
+
+    private static String quote(String s) {
+        StringBuilder sb = new StringBuilder("\"");
+        for (char c : s.toCharArray()) {
+            if (c == '"' || c == '\\') {
+                sb.append('\\');
+            }
+            sb.append(c);
+        }
+        return sb.append('"').toString();
+    }
+
+    private static String unquote(String raw) {
+        if (raw.length() < 2 || raw.charAt(0) != raw.charAt(raw.length() - 1)
+            || (raw.charAt(0) != '"' && raw.charAt(0) != '\'')) {
+            throw new IllegalArgumentException("invalid string: " + raw);
+        }
+        StringBuilder sb = new StringBuilder();
+        for (int i = 1; i < raw.length() - 1; i++) {
+            char c = raw.charAt(i);
+            if (c != '\\') {
+                sb.append(c);
+                continue;
+            }
+            char next = raw.charAt(++i);
+            switch (next) {
+                case 'n':
+                    sb.append('\n');
+                    break;
+                case 't':
+                    sb.append('\t');
+                    break;
+                case 'r':
+                    sb.append('\r');
+                    break;
+                case 'b':
+                    sb.append('\b');
+                    break;
+                case 'f':
+                    sb.append('\f');
+                    break;
+                case 'u':
+                    sb.append((char) Integer.parseInt(raw.substring(i + 1, i + 5), 16));
+                    i += 4;
+                    break;
+                default:
+                    sb.append(next);
+                    break;
+            }
+        }
+        return sb.toString();
+    }
+}
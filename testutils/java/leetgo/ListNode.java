@@ -0,0 +1,58 @@
+package leetgo;
+
+import java.util.HashSet;
+import java.util.List;
+import java.util.Set;
+
+/**
+ * Definition for a singly-linked list.
+ */
+public class ListNode {
+    public int val;
+    public ListNode next;
+
+    public ListNode() {}
+
+    public ListNode(int val) {
+        this.val = val;
+    }
+
+    public ListNode(int val, ListNode next) {
+        this.val = val;
+        this.next = next;
+    }
+
+    /**
+     * Deserializes a linked list from a string like "[1,2,3]".
+     */
+    public static ListNode deserialize(String s) {
+        List<String> values = LeetCodeIO.splitArray(s);
+        ListNode dummy = new ListNode();
+        ListNode node = dummy;
+        for (String v : values) {
+            node.next = new ListNode(Integer.parseInt(v));
+            node = node.next;
+        }
+        return dummy.next;
+    }
+
+    /**
+     * Serializes the linked list to a string like "[1,2,3]".
+     * Throws IllegalStateException if a cycle is detected.
+     */
+    @Override
+    public String toString() {
+        Set<ListNode> seen = new HashSet<>();
+        StringBuilder sb = new StringBuilder("[");
+        for (ListNode node = this; node != null; node = node.next) {
+            if (!seen.add(node)) {
+                throw new IllegalStateException("infinite loop detected");
+            }
+            if (node != this) {
+                sb.append(',');
+            }
+            sb.append(node.val);
+        }
+        return sb.append(']').toString();
+    }
+}
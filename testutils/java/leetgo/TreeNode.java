@@ -0,0 +1,85 @@
+package leetgo;
+
+import java.util.ArrayDeque;
+import java.util.ArrayList;
+import java.util.Deque;
+import java.util.LinkedList;
+import java.util.List;
+import java.util.Queue;
+
+/**
+ * Definition for a binary tree node.
+ */
+public class TreeNode {
+    public int val;
+    public TreeNode left;
+    public TreeNode right;
+
+    public TreeNode() {}
+
+    public TreeNode(int val) {
+        this.val = val;
+    }
+
+    public TreeNode(int val, TreeNode left, TreeNode right) {
+        this.val = val;
+        this.left = left;
+        this.right = right;
+    }
+
+    /**
+     * Deserializes a binary tree from its level order representation, e.g. "[1,null,2,3]".
+     */
+    public static TreeNode deserialize(String s) {
+        List<String> values = LeetCodeIO.splitArray(s);
+        if (values.isEmpty() || values.get(0).equals("null")) {
+            return null;
+        }
+        List<TreeNode> nodes = new ArrayList<>(values.size());
+        for (String v : values) {
+            nodes.add(v.equals("null") ? null : new TreeNode(Integer.parseInt(v)));
+        }
+        Deque<TreeNode> parents = new ArrayDeque<>();
+        parents.add(nodes.get(0));
+        for (int i = 1; i < nodes.size() && !parents.isEmpty(); ) {
+            TreeNode parent = parents.poll();
+            parent.left = nodes.get(i++);
+            if (parent.left != null) {
+                parents.add(parent.left);
+            }
+            if (i < nodes.size()) {
+                parent.right = nodes.get(i++);
+                if (parent.right != null) {
+                    parents.add(parent.right);
+                }
+            }
+        }
+        return nodes.get(0);
+    }
+
+    /**
+     * Serializes the tree to its level order representation, trailing nulls are omitted.
+     */
+    @Override
+    public String toString() {
+        List<String> values = new ArrayList<>();
+        // LinkedList permits null elements, ArrayDeque does not.
+        Queue<TreeNode> queue = new LinkedList<>();
+        queue.add(this);
+        while (!queue.isEmpty()) {
+            TreeNode node = queue.poll();
+            if (node == null) {
+                values.add("null");
+                continue;
+            }
+            values.add(String.valueOf(node.val));
+            queue.add(node.left);
+            queue.add(node.right);
+        }
+        int end = values.size();
+        while (end > 0 && values.get(end - 1).equals("null")) {
+            end--;
+        }
+        return LeetCodeIO.joinArray(values.subList(0, end));
+    }
+}
@@ -0,0 +1,27 @@
+export declare class ListNode {
+  val: number;
+  next: ListNode | null;
+  constructor(val?: number, next?: ListNode | null);
+}
+
+export declare class TreeNode {
+  val: number;
+  left: TreeNode | null;
+  right: TreeNode | null;
+  constructor(val?: number, left?: TreeNode | null, right?: TreeNode | null);
+}
+
+/** Splits a JSON array which may contain values of different types into its raw elements. */
+export declare function splitArray(raw: string): string[];
+
+/** Joins serialized elements into a JSON array. */
+export declare function joinArray(values: string[]): string;
+
+/** Deserializes a raw value, `type` is a LeetCode type name like "integer[]" or "TreeNode". */
+export declare function deserialize(type: string, raw: string): any;
+
+/** Serializes a value, `type` is a LeetCode type name used for doubles and empty trees or lists. */
+export declare function serialize(value: any, type?: string): string;
+
+/** Reads the next line from stdin. */
+export declare function readLine(): string;
@@ -0,0 +1,206 @@
+"use strict";
+
+const fs = require("fs");
+
+/**
+ * Definition for a singly-linked list.
+ */
+class ListNode {
+  constructor(val, next) {
+    this.val = val === undefined ? 0 : val;
+    this.next = next === undefined ? null : next;
+  }
+
+  static deserialize(raw) {
+    const dummy = new ListNode();
+    let node = dummy;
+    for (const v of JSON.parse(raw)) {
+      node.next = new ListNode(v);
+      node = node.next;
+    }
+    return dummy.next;
+  }
+
+  toString() {
+    const seen = new Set();
+    const values = [];
+    for (let node = this; node !== null; node = node.next) {
+      if (seen.has(node)) {
+        throw new Error("infinite loop detected");
+      }
+      seen.add(node);
+      values.push(node.val);
+    }
+    return "[" + values.join(",") + "]";
+  }
+}
+
+/**
+ * Definition for a binary tree node.
+ */
+class TreeNode {
+  constructor(val, left, right) {
+    this.val = val === undefined ? 0 : val;
+    this.left = left === undefined ? null : left;
+    this.right = right === undefined ? null : right;
+  }
+
+  static deserialize(raw) {
+    const values = JSON.parse(raw);
+    if (values.length === 0 || values[0] === null) {
+      return null;
+    }
+    const nodes = values.map((v) => (v === null ? null : new TreeNode(v)));
+    const parents = [nodes[0]];
+    for (let i = 1, p = 0; i < nodes.length && p < parents.length; p++) {
+      const parent = parents[p];
+      parent.left = nodes[i++];
+      if (parent.left !== null) {
+        parents.push(parent.left);
+      }
+      if (i < nodes.length) {
+        parent.right = nodes[i++];
+        if (parent.right !== null) {
+          parents.push(parent.right);
+        }
+      }
+    }
+    return nodes[0];
+  }
+
+  toString() {
+    const values = [];
+    const queue = [this];
+    for (let i = 0; i < queue.length; i++) {
+      const node = queue[i];
+      if (node === null) {
+        values.push("null");
+        continue;
+      }
+      values.push(String(node.val));
+      queue.push(node.left, node.right);
+    }
+    while (values.length > 0 && values[values.length - 1] === "null") {
+      values.pop();
+    }
+    return "[" + values.join(",") + "]";
+  }
+}
+
+function splitArray(raw) {
+  raw = raw.trim();
+  if (raw.length < 2 || raw[0] !== "[" || raw[raw.length - 1] !== "]") {
+    throw new Error("invalid array: " + raw);
+  }
+  const res = [];
+  let depth = 0;
+  let inString = false;
+  let start = 1;
+  for (let i = 1; i < raw.length - 1; i++) {
+    const c = raw[i];
+    if (inString) {
+      if (c === "\\") {
+        i++;
+      } else if (c === '"') {
+        inString = false;
+      }
+      continue;
+    }
+    if (c === '"') {
+      inString = true;
+    } else if (c === "[" || c === "{") {
+      depth++;
+    } else if (c === "]" || c === "}") {
+      depth--;
+    } else if (c === "," && depth === 0) {
+      res.push(raw.slice(start, i).trim());
+      start = i + 1;
+    }
+  }
+  const last = raw.slice(start, raw.length - 1).trim();
+  if (last !== "" || res.length > 0) {
+    res.push(last);
+  }
+  return res;
+}
+
+function joinArray(values) {
+  return "[" + values.join(",") + "]";
+}
+
+function deserialize(type, raw) {
+  raw = raw.trim();
+  if (type.endsWith("[]")) {
+    const elemType = type.slice(0, -2);
+    return splitArray(raw).map((v) => deserialize(elemType, v));
+  }
+  switch (type) {
+    case "integer":
+    case "long":
+    case "double":
+      return Number(raw);
+    case "boolean":
+      if (raw !== "true" && raw !== "false") {
+        throw new Error("invalid boolean: " + raw);
+      }
+      return raw === "true";
+    case "character":
+    case "string":
+      return JSON.parse(raw);
+    case "ListNode":
+      return ListNode.deserialize(raw);
+    case "TreeNode":
+      return TreeNode.deserialize(raw);
+    default:
+      throw new Error("unknown type: " + type);
+  }
+}
+
+function serialize(value, type) {
+  if (value === null || value === undefined) {
+    if (type === "ListNode" || type === "TreeNode") {
+      return "[]";
+    }
+    return "null";
+  }
+  if (Array.isArray(value)) {
+    const elemType = type !== undefined && type.endsWith("[]") ? type.slice(0, -2) : undefined;
+    return joinArray(value.map((v) => serialize(v, elemType)));
+  }
+  if (value instanceof ListNode || value instanceof TreeNode) {
+    return value.toString();
+  }
+  if (typeof value === "number") {
+    if (type === "double" || !Number.isInteger(value)) {
+      return value.toFixed(5);
+    }
+    return String(value);
+  }
+  if (typeof value === "string") {
+    return JSON.stringify(value);
+  }
+  return String(value);
+}
+
+let lines = null;
+let lineNo = 0;
+
+function readLine() {
+  if (lines === null) {
+    lines = fs.readFileSync(0, "utf8").split(/\r?\n/);
+  }
+  if (lineNo >= lines.length) {
+    throw new Error("unexpected end of input");
+  }
+  return lines[lineNo++].trim();
+}
+
+module.exports = {
+  ListNode,
+  TreeNode,
+  splitArray,
+  joinArray,
+  deserialize,
+  serialize,
+  readLine,
+};
@@ -0,0 +1,11 @@
+package js
+
+import (
+	"embed"
+)
+
+// PackageName is the name of the bundled npm package, it is installed into the workspace as a local dependency.
+const PackageName = "leetgo-js"
+
+//go:embed package.json index.js index.d.ts
+var Files embed.FS
@@ -0,0 +1,12 @@
+package kotlin
+
+import (
+	"embed"
+)
+
+// PackageName is the Kotlin package that holds the test utilities.
+// Sources are written into `<out_dir>/leetgo/` and compiled together with the solution.
+const PackageName = "leetgo"
+
+//go:embed leetgo/*.kt
+var Sources embed.FS
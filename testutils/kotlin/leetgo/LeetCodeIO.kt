@@ -0,0 +1,282 @@
+package leetgo
+
+import java.io.BufferedReader
+import java.io.EOFException
+import java.lang.reflect.Constructor
+import java.lang.reflect.Executable
+import java.lang.reflect.GenericArrayType
+import java.lang.reflect.InvocationTargetException
+import java.lang.reflect.Method
+import java.lang.reflect.Modifier
+import java.lang.reflect.ParameterizedType
+import java.lang.reflect.Type
+import java.lang.reflect.WildcardType
+import java.util.Locale
+import java.lang.reflect.Array as JArray
+
+/**
+ * Helpers used by the generated test harness to read test inputs and print outputs.
+ *
+ * LeetCode metadata does not tell whether a Kotlin function takes `IntArray` or `List<Int>`,
+ * so values are deserialized according to the reflected parameter types of the solution method.
+ */
+object LeetCodeIO {
+    /**
+     * Reads a line from the reader, throws EOFException if there is no more input.
+     */
+    @JvmStatic
+    fun readLine(reader: BufferedReader): String {
+        val line = reader.readLine() ?: throw EOFException("unexpected end of input")
+        return line.trim()
+    }
+
+    /**
+     * Splits a JSON array which may contain values of different types into its raw elements.
+     */
+    @JvmStatic
+    fun splitArray(raw: String): List<String> {
+        val s = raw.trim()
+        require(s.length >= 2 && s.first() == '[' && s.last() == ']') { "invalid array: $s" }
+        val res = ArrayList<String>()
+        var depth = 0
+        var inString = false
+        var start = 1
+        var i = 1
+        while (i < s.length - 1) {
+            val c = s[i]
+            if (inString) {
+                if (c == '\\') {
+                    i++
+                } else if (c == '"') {
+                    inString = false
+                }
+            } else {
+                when (c) {
+                    '"' -> inString = true
+                    '[', '{' -> depth++
+                    ']', '}' -> depth--
+                    ',' -> if (depth == 0) {
+                        res.add(s.substring(start, i).trim())
+                        start = i + 1
+                    }
+                }
+            }
+            i++
+        }
+        val last = s.substring(start, s.length - 1).trim()
+        if (last.isNotEmpty() || res.isNotEmpty()) {
+            res.add(last)
+        }
+        return res
+    }
+
+    /**
+     * Joins serialized elements into a JSON array.
+     */
+    @JvmStatic
+    fun joinArray(values: List<String>): String = values.joinToString(",", "[", "]")
+
+    /**
+     * Deserializes a raw value into the given (possibly generic) type.
+     */
+    @JvmStatic
+    fun deserialize(type: Type, raw: String): Any? {
+        val s = raw.trim()
+        when (type) {
+            // Kotlin emits `List<? extends List<Integer>>` for `List<List<Int>>`.
+            is WildcardType -> return deserialize(type.upperBounds[0], s)
+            is ParameterizedType -> {
+                val rawType = type.rawType as Class<*>
+                require(Collection::class.java.isAssignableFrom(rawType)) { "unsupported type: $type" }
+                val elemType = type.actualTypeArguments[0]
+                return splitArray(s).mapTo(ArrayList()) { deserialize(elemType, it) }
+            }
+            is GenericArrayType -> {
+                val elemType = type.genericComponentType
+                val splits = splitArray(s)
+                val arr = JArray.newInstance(rawClass(elemType), splits.size)
+                splits.forEachIndexed { i, v -> JArray.set(arr, i, deserialize(elemType, v)) }
+                return arr
+            }
+            !is Class<*> -> throw IllegalArgumentException("unsupported type: $type")
+        }
+        val cls = type as Class<*>
+        return when {
+            cls == Int::class.javaPrimitiveType || cls == Int::class.javaObjectType -> s.toInt()
+            cls == Long::class.javaPrimitiveType || cls == Long::class.javaObjectType -> s.toLong()
+            cls == Double::class.javaPrimitiveType || cls == Double::class.javaObjectType -> s.toDouble()
+            cls == Boolean::class.javaPrimitiveType || cls == Boolean::class.javaObjectType -> {
+                require(s == "true" || s == "false") { "invalid boolean: $s" }
+                s == "true"
+            }
+            cls == Char::class.javaPrimitiveType || cls == Char::class.javaObjectType -> {
+                val str = unquote(s)
+                require(str.length == 1) { "invalid char: $s" }
+                str[0]
+            }
+            cls == String::class.java -> unquote(s)
+            cls == ListNode::class.java -> ListNode.deserialize(s)
+            cls == TreeNode::class.java -> TreeNode.deserialize(s)
+            cls.isArray -> {
+                val splits = splitArray(s)
+                val arr = JArray.newInstance(cls.componentType, splits.size)
+                splits.forEachIndexed { i, v -> JArray.set(arr, i, deserialize(cls.componentType, v)) }
+                arr
+            }
+            // Raw List type, keep the elements as strings.
+            cls == List::class.java -> ArrayList(splitArray(s))
+            else -> throw IllegalArgumentException("unsupported type: ${cls.name}")
+        }
+    }
+
+    private fun rawClass(type: Type): Class<*> = when (type) {
+        is Class<*> -> type
+        is ParameterizedType -> type.rawType as Class<*>
+        is WildcardType -> rawClass(type.upperBounds[0])
+        is GenericArrayType -> JArray.newInstance(rawClass(type.genericComponentType), 0).javaClass
+        else -> Any::class.java
+    }
+
+    /**
+     * Serializes a value of the declared type, an empty list or tree is printed as `[]` rather than null.
+     */
+    @JvmStatic
+    fun serialize(v: Any?, type: Type): String {
+        if (v == null && (type == ListNode::class.java || type == TreeNode::class.java)) {
+            return "[]"
+        }
+        return serialize(v)
+    }
+
+    /**
+     * Serializes a value into its LeetCode representation.
+     */
+    @JvmStatic
+    fun serialize(v: Any?): String = when {
+        v == null -> "null"
+        v is String -> quote(v)
+        v is Char -> quote(v.toString())
+        v is Double || v is Float -> String.format(Locale.ROOT, "%.5f", (v as Number).toDouble())
+        v is ListNode || v is TreeNode -> v.toString()
+        v.javaClass.isArray -> joinArray((0 until JArray.getLength(v)).map { serialize(JArray.get(v, it)) })
+        v is Iterable<*> -> joinArray(v.map { serialize(it) })
+        // Int, Long, Boolean
+        else -> v.toString()
+    }
+
+    /**
+     * Finds the method with the given name and number of parameters declared by the class.
+     * Public methods are preferred over helpers of the same name, and an ambiguous overload is an error.
+     */
+    @JvmStatic
+    fun getMethod(cls: Class<*>, name: String, paramCount: Int): Method {
+        var candidates = cls.declaredMethods.filter {
+            it.name == name && it.parameterCount == paramCount && !it.isSynthetic
+        }
+        if (candidates.size > 1) {
+            candidates = candidates.filter { Modifier.isPublic(it.modifiers) }.ifEmpty { candidates }
+        }
+        val desc = "method $name with $paramCount parameters"
+        val m = when (candidates.size) {
+            0 -> throw IllegalArgumentException("$desc not found in ${cls.name}")
+            1 -> candidates[0]
+            else -> throw IllegalArgumentException("$desc is ambiguous in ${cls.name}: $candidates")
+        }
+        m.isAccessible = true
+        return m
+    }
+
+    /**
+     * Finds the constructor of the class, the one with the most parameters wins.
+     * Synthetic constructors generated for default arguments are ignored.
+     */
+    @JvmStatic
+    fun getConstructor(cls: Class<*>): Constructor<*> {
+        val c = cls.declaredConstructors.filter { !it.isSynthetic }.maxByOrNull { it.parameterCount }
+            ?: throw IllegalArgumentException("constructor not found in ${cls.name}")
+        c.isAccessible = true
+        return c
+    }
+
+    /**
+     * Reads one line per parameter of the method or constructor and deserializes them.
+     */
+    @JvmStatic
+    fun readArgs(reader: BufferedReader, e: Executable): Array<Any?> =
+        deserializeArgs(e, List(e.parameterCount) { readLine(reader) })
+
+    /**
+     * Deserializes raw arguments according to the parameter types of the method or constructor.
+     */
+    @JvmStatic
+    fun deserializeArgs(e: Executable, raws: List<String>): Array<Any?> {
+        val types = e.genericParameterTypes
+        require(types.size == raws.size) { "expected ${types.size} arguments for ${e.name}, got ${raws.size}" }
+        return Array(types.size) { deserialize(types[it], raws[it]) }
+    }
+
+    /**
+     * Invokes the method, exceptions thrown by the solution are rethrown as is.
+     */
+    @JvmStatic
+    fun invoke(obj: Any, m: Method, args: Array<Any?>): Any? {
+        try {
+            return m.invoke(obj, *args)
+        } catch (ex: InvocationTargetException) {
+            throw ex.cause ?: ex
+        }
+    }
+
+    /**
+     * Creates a new instance, exceptions thrown by the constructor are rethrown as is.
+     */
+    @JvmStatic
+    fun newInstance(c: Constructor<*>, args: Array<Any?>): Any {
+        try {
+            return c.newInstance(*args)
+        } catch (ex: InvocationTargetException) {
+            throw ex.cause ?: ex
+        }
+    }
+
+    private fun quote(s: String): String {
+        val sb = StringBuilder("\"")
+        for (c in s) {
+            if (c == '"' || c == '\\') {
+                sb.append('\\')
+            }
+            sb.append(c)
+        }
+        return sb.append('"').toString()
+    }
+
+    private fun unquote(raw: String): String {
+        require(raw.length >= 2 && raw.first() == raw.last() && (raw.first() == '"' || raw.first() == '\'')) {
+            "invalid string: $raw"
+        }
+        val sb = StringBuilder()
+        var i = 1
+        while (i < raw.length - 1) {
+            val c = raw[i]
+            if (c != '\\') {
+                sb.append(c)
+                i++
+                continue
+            }
+            when (val next = raw[++i]) {
+                'n' -> sb.append('\n')
+                't' -> sb.append('\t')
+                'r' -> sb.append('\r')
+                'b' -> sb.append('\b')
+                'f' -> sb.append('\u000C')
+                'u' -> {
+                    sb.append(raw.substring(i + 1, i + 5).toInt(16).toChar())
+                    i += 4
+                }
+                else -> sb.append(next)
+            }
+            i++
+        }
+        return sb.toString()
+    }
+}
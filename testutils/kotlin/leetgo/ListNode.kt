@@ -0,0 +1,35 @@
+package leetgo
+
+/**
+ * Definition for a singly-linked list.
+ */
+class ListNode(var `val`: Int) {
+    var next: ListNode? = null
+
+    override fun toString(): String {
+        val seen = HashSet<ListNode>()
+        val values = ArrayList<String>()
+        var node: ListNode? = this
+        while (node != null) {
+            if (!seen.add(node)) {
+                throw IllegalStateException("infinite loop detected")
+            }
+            values.add(node.`val`.toString())
+            node = node.next
+        }
+        return LeetCodeIO.joinArray(values)
+    }
+
+    companion object {
+        @JvmStatic
+        fun deserialize(raw: String): ListNode? {
+            val dummy = ListNode(0)
+            var node = dummy
+            for (v in LeetCodeIO.splitArray(raw)) {
+                node.next = ListNode(v.toInt())
+                node = node.next!!
+            }
+            return dummy.next
+        }
+    }
+}
@@ -0,0 +1,55 @@
+package leetgo
+
+/**
+ * Definition for a binary tree node.
+ */
+class TreeNode(var `val`: Int) {
+    var left: TreeNode? = null
+    var right: TreeNode? = null
+
+    override fun toString(): String {
+        val values = ArrayList<String>()
+        val queue = ArrayList<TreeNode?>()
+        queue.add(this)
+        var i = 0
+        while (i < queue.size) {
+            val node = queue[i++]
+            if (node == null) {
+                values.add("null")
+                continue
+            }
+            values.add(node.`val`.toString())
+            queue.add(node.left)
+            queue.add(node.right)
+        }
+        while (values.isNotEmpty() && values.last() == "null") {
+            values.removeAt(values.size - 1)
+        }
+        return LeetCodeIO.joinArray(values)
+    }
+
+    companion object {
+        @JvmStatic
+        fun deserialize(raw: String): TreeNode? {
+            val values = LeetCodeIO.splitArray(raw)
+            if (values.isEmpty() || values[0] == "null") {
+                return null
+            }
+            val nodes = values.map { if (it == "null") null else TreeNode(it.toInt()) }
+            val parents = ArrayList<TreeNode>()
+            parents.add(nodes[0]!!)
+            var i = 1
+            var p = 0
+            while (i < nodes.size && p < parents.size) {
+                val parent = parents[p++]
+                parent.left = nodes[i++]
+                parent.left?.let { parents.add(it) }
+                if (i < nodes.size) {
+                    parent.right = nodes[i++]
+                    parent.right?.let { parents.add(it) }
+                }
+            }
+            return nodes[0]
+        }
+    }
+}
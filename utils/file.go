@@ -4,6 +4,8 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strings"
 )
 
 // IsExist checks if a file or directory exists
@@ -18,6 +20,19 @@ func IsExist(path string) bool {
 	return false
 }
 
+// IsExecutable checks if the path is a regular file that can be executed.
+func IsExecutable(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() {
+		return false
+	}
+	if runtime.GOOS == "windows" {
+		ext := strings.ToLower(filepath.Ext(path))
+		return ext == ".exe" || ext == ".bat" || ext == ".cmd"
+	}
+	return info.Mode().Perm()&0o111 != 0
+}
+
 func MakeDir(dir string) error {
 	return os.MkdirAll(dir, 0o755)
 }